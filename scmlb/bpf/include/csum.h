@@ -20,6 +20,16 @@ static inline u16 ipv4_csum_update_u32(u16 csum, u32 old_val, u32 new_val) {
 	return ipv4_csum_update_u16(csum, old_val_tail, new_val_tail);
 }
 
+// IPv6 アドレスの書き換えに伴う TCP/UDP チェックサムの差分更新を行います。
+// 128 bit のアドレスを 32 bit ずつ更新します。
+static inline u16 ipv6_csum_update_addr(u16 csum, struct in6_addr *old_addr, struct in6_addr *new_addr) {
+#pragma unroll
+	for (int i = 0; i < 4; i++) {
+		csum = ipv4_csum_update_u32(csum, old_addr->in6_u.u6_addr32[i], new_addr->in6_u.u6_addr32[i]);
+	}
+	return csum;
+}
+
 static inline __u16 csum_fold_helper(__u64 csum) {
 	int i;
 #pragma unroll
@@ -0,0 +1,33 @@
+#include "vmlinux.h"
+
+// Linux カーネルの include/linux/jhash.h から必要な部分を移植しています。
+// Maglev でのバックエンド選択で 5-tuple からハッシュ値を計算するために利用します。
+
+#define JHASH_INITVAL 0xdeadbeef
+
+static inline u32 rol32(u32 word, unsigned int shift) {
+	return (word << shift) | (word >> ((-shift) & 31));
+}
+
+#define __jhash_final(a, b, c)			\
+{						\
+	c ^= b; c -= rol32(b, 14);		\
+	a ^= c; a -= rol32(c, 11);		\
+	b ^= a; b -= rol32(a, 25);		\
+	c ^= b; c -= rol32(b, 16);		\
+	a ^= c; a -= rol32(c, 4);		\
+	b ^= a; b -= rol32(a, 14);		\
+	c ^= b; c -= rol32(b, 24);		\
+}
+
+static inline u32 __jhash_nwords(u32 a, u32 b, u32 c, u32 initval) {
+	a += initval;
+	b += initval;
+	c += initval;
+	__jhash_final(a, b, c);
+	return c;
+}
+
+static inline u32 jhash_3words(u32 a, u32 b, u32 c, u32 initval) {
+	return __jhash_nwords(a, b, c, initval + JHASH_INITVAL + (3 << 2));
+}
@@ -3,9 +3,23 @@
 
 #include "scmlb.h"
 
-#define FIRE_WALL_RULE_MAX_SIZE_PER_NETWORK 16
+// 以下のマップの大きさはロード時に scmlbd start のフラグで変更できます。
+// ここで定義した値はデフォルト値です。
+#define FIRE_WALL_RULE_MAX_SIZE 1028
+#define CONNTRACK_MAX_SIZE 2056
 #define BACKEND_MAX_SIZE 16
 
+// 1 つのネットワークに適用されるルールの数の上限です。XDP プログラムでルールを評価するループの回数の上限になります。
+#define FIRE_WALL_RULE_MAX_SIZE_PER_NETWORK 512
+// adv_rule_lists に格納できるルール id の数です。
+#define FIRE_WALL_RULE_LIST_MAX_SIZE 65536
+#define SERVICE_MAX_SIZE 16
+#define RR_TABLE_MAX_SIZE 256
+// Maglev のルックアップテーブルのサイズです。素数である必要があります。
+#define MAGLEV_TABLE_SIZE 65537
+#define AFFINITY_TABLE_MAX_SIZE 65536
+#define DOSP_POLICY_MAX_SIZE 256
+
 // tail call 用の特別なマップです
 // Go 言語のユーザーランドのプログラムから要素を追加して tail call する関数を登録します。
 struct {
@@ -26,40 +40,61 @@ struct {
 // LPM_TRIE は Longest Prefix Match Trie の略でキーにネットワークプレフィックスを与えることで longest prefix match をカーネル側で処理してくれます。
 struct {
 	__uint(type, BPF_MAP_TYPE_LPM_TRIE);
-	// key は uint64 の値でないといけないようです
-	__uint(key_size, sizeof(u64));
+	// key は先頭にプレフィックス長を持つ構造体でないといけません。
+	// IPv4 と IPv6 を同じマップで扱うために IPv6 アドレスを格納できる network 構造体をキーにします。
+	__uint(key_size, sizeof(struct network));
 	__uint(value_size, sizeof(struct fw_rule));
-	__uint(max_entries, 1028);
+	__uint(max_entries, FIRE_WALL_RULE_MAX_SIZE);
 	// LPM_TRIE ではこのフラグを指定しないとロードできません
 	__uint(map_flags, BPF_F_NO_PREALLOC);
 } rules SEC(".maps");
 
 // advanced な fire wall のための LPM_TRIE のマップです。
-// ネットワークプレフィックス address/prefix を uint64 に埋め込んだ値をキーとして
-// uint16 の fire wall id の列をバリューとして持ちます。
+// 世代とネットワークプレフィックス address/prefix を fw_matcher_key 構造体に格納した値をキーとして
+// adv_rule_lists に格納した fire wall id の列の位置と長さをバリューとして持ちます。
+// ルールを一括で適用する間は新旧 2 つの世代のエントリーが格納されるので、ルールの最大数の 2 倍の大きさにします。
 struct {
 	__uint(type, BPF_MAP_TYPE_LPM_TRIE);
-	__uint(key_size, sizeof(u64));
-	__uint(value_size, sizeof(u16) * FIRE_WALL_RULE_MAX_SIZE_PER_NETWORK);
-	__uint(max_entries, 1028);
+	__uint(key_size, sizeof(struct fw_matcher_key));
+	__uint(value_size, sizeof(struct fw_rule_list));
+	__uint(max_entries, FIRE_WALL_RULE_MAX_SIZE * 2);
 	__uint(map_flags, BPF_F_NO_PREALLOC);
 } adv_rulematcher SEC(".maps");
 
+// XDP プログラムが参照する adv_rulematcher の世代を格納する要素が 1 つの配列のマップです。
+// scmlbd は新しい世代のエントリーをすべて書き込んでからこの値を切り替えます。
+struct {
+	__uint(type, BPF_MAP_TYPE_ARRAY);
+	__uint(key_size, sizeof(u32));
+	__uint(value_size, sizeof(u32));
+	__uint(max_entries, 1);
+} fw_generation SEC(".maps");
+
+// adv_rulematcher から参照する fire wall id の列を格納する配列のマップです。
+// プレフィックスごとの列は連続した領域に格納していて、領域は scmlbd が割り当てます。
+struct {
+	__uint(type, BPF_MAP_TYPE_ARRAY);
+	__uint(key_size, sizeof(u32));
+	__uint(value_size, sizeof(u32));
+	__uint(max_entries, FIRE_WALL_RULE_LIST_MAX_SIZE);
+} adv_rule_lists SEC(".maps");
+
 // advanced な fire wall のための rule id をキーとして port, protocol などのルールを value とするマップです。
 struct {
 	__uint(type, BPF_MAP_TYPE_HASH);
 	__uint(key_size, sizeof(u32));
 	__uint(value_size, sizeof(struct fw_rule));
-	__uint(max_entries, 1028);
+	__uint(max_entries, FIRE_WALL_RULE_MAX_SIZE);
 } adv_rules SEC(".maps");
 
 
-// fire wall でドロップされたパケットをカウントして保存するマップです。
+// fire wall のルールにマッチしたパケットをルールごとにカウントして保存するマップです。
+// Deny のルールの場合はドロップしたパケットの数になります。
 struct {
 	__uint(type, BPF_MAP_TYPE_HASH);
 	__uint(key_size, sizeof(u32));
 	__uint(value_size, sizeof(u64));
-	__uint(max_entries, 2056);
+	__uint(max_entries, FIRE_WALL_RULE_MAX_SIZE);
 } drop_counter SEC(".maps");
 
 // DoS protector のためのパケット種類別の数をカウントするためのマップです。
@@ -109,22 +144,95 @@ struct {
 	// __uint(pinning, LIBBPF_PIN_BY_NAME);
 } upstream_info SEC(".maps");
 
+// コネクションの 5-tuple をキーとして、転送先のバックエンドとコネクションの状態、統計情報を値として持つマップです。
+// scmlbd start で --conntrack-lru を指定すると、ロード時に BPF_MAP_TYPE_LRU_HASH に変更されます。
+// LRU の場合はエントリーが溢れたときに最も使われていないエントリーが削除され、新しいコネクションを受け付けられます。
 struct {
 	__uint(type, BPF_MAP_TYPE_HASH);
 	__uint(key_size, sizeof(struct connection));
 	__uint(value_size, sizeof(struct connection_info));
-	__uint(max_entries, 2056);
+	__uint(max_entries, CONNTRACK_MAX_SIZE);
 	// __uint(pinning, LIBBPF_PIN_BY_NAME);
 } conntrack SEC(".maps");
 
 // ロードバランサーのバックエンド選択のラウンドロビンのテーブルです。
-// キーは配列のインデックスです。
+// サービスごとに RR_TABLE_MAX_SIZE の領域を持ち、キーは サービスの id * RR_TABLE_MAX_SIZE + 配列のインデックス です。
 // バリューは backend の id です。
-// BPF プログラムの方ではグローバル変数として前回選択したインデックスを保存していて、次はそのインデックスからバックエンドを選択します。
+// BPF プログラムの方では service 構造体に前回選択したインデックスを保存していて、次はそのインデックスからバックエンドを選択します。
+// 重み付きラウンドロビンのために一つのバックエンドが重みの数だけ複数のインデックスに格納されます。
 struct {
 	__uint(type, BPF_MAP_TYPE_ARRAY);
 	__uint(key_size, sizeof(u32));
 	__uint(value_size, sizeof(u32));
-	__uint(max_entries, BACKEND_MAX_SIZE);
+	__uint(max_entries, RR_TABLE_MAX_SIZE * SERVICE_MAX_SIZE);
 	// __uint(pinning, LIBBPF_PIN_BY_NAME);
 } rr_table SEC(".maps");
+
+// Maglev によるバックエンド選択のためのルックアップテーブルです。
+// サービスごとに MAGLEV_TABLE_SIZE の領域を持ち、キーは サービスの id * MAGLEV_TABLE_SIZE + 5-tuple のハッシュ値を MAGLEV_TABLE_SIZE で割った余り です。
+// バリューは backend の id です。
+// テーブルの計算は Go 言語のプログラム(LbBackendManager) 側で行って、このマップに書き込みます。
+struct {
+	__uint(type, BPF_MAP_TYPE_ARRAY);
+	__uint(key_size, sizeof(u32));
+	__uint(value_size, sizeof(u32));
+	__uint(max_entries, MAGLEV_TABLE_SIZE * SERVICE_MAX_SIZE);
+} maglev_table SEC(".maps");
+
+// ロードバランサーのサービスを登録するマップです。
+// キーは VIP とポート、プロトコルの組です。
+// バリューはサービスの id や選択方式を格納した service 構造体です。
+struct {
+	__uint(type, BPF_MAP_TYPE_HASH);
+	__uint(key_size, sizeof(struct service_key));
+	__uint(value_size, sizeof(struct service));
+	__uint(max_entries, SERVICE_MAX_SIZE);
+} services SEC(".maps");
+
+// バックエンドからの戻りパケットがどのサービスのものかを調べるためのマップです。
+// キーはバックエンドのアドレスとサービスのポート、プロトコルの組です。
+// バリューはサービスの VIP です。
+struct {
+	__uint(type, BPF_MAP_TYPE_HASH);
+	__uint(key_size, sizeof(struct service_key));
+	__uint(value_size, sizeof(struct in6_addr));
+	__uint(max_entries, BACKEND_MAX_SIZE);
+} reverse_service SEC(".maps");
+
+// 送信元アドレスによるセッション維持のためのテーブルです。
+// キーはクライアントのアドレスとサービスの id の組で、バリューは割り当てたバックエンドの id と最後にパケットを受信した時刻です。
+// アイドルタイムアウトを過ぎたエントリーは XDP プログラムの方で無視して、新しいバックエンドを選択し直します。
+// クライアントの数は事前にわからないので、エントリーが溢れたときは最も使われていないものから削除される LRU のマップにしています。
+struct {
+	__uint(type, BPF_MAP_TYPE_LRU_HASH);
+	__uint(key_size, sizeof(struct affinity_key));
+	__uint(value_size, sizeof(struct affinity_info));
+	__uint(max_entries, AFFINITY_TABLE_MAX_SIZE);
+} affinity SEC(".maps");
+
+// 以下のマップは XDP プログラムからは参照しません。
+// scmlbd を --persist で起動したときに、ピン留めしたマップから Go 言語のプログラム側の状態を復元するために利用します。
+
+// バックエンドの id をキーとして、backend_info に含まれないバックエンドの情報を値として持つマップです。
+struct {
+	__uint(type, BPF_MAP_TYPE_HASH);
+	__uint(key_size, sizeof(u32));
+	__uint(value_size, sizeof(struct backend_meta));
+	__uint(max_entries, BACKEND_MAX_SIZE);
+} backend_meta SEC(".maps");
+
+// DoS protection policy の id をキーとしてポリシーを値として持つマップです。
+struct {
+	__uint(type, BPF_MAP_TYPE_HASH);
+	__uint(key_size, sizeof(u32));
+	__uint(value_size, sizeof(struct dosp_policy));
+	__uint(max_entries, DOSP_POLICY_MAX_SIZE);
+} dosp_policies SEC(".maps");
+
+// DoS protector が追加した fire wall ルールの id をキーとして、ルールを追加したポリシーの id を値として持つマップです。
+struct {
+	__uint(type, BPF_MAP_TYPE_HASH);
+	__uint(key_size, sizeof(u32));
+	__uint(value_size, sizeof(u32));
+	__uint(max_entries, FIRE_WALL_RULE_MAX_SIZE);
+} dosp_fw_rules SEC(".maps");
@@ -1,8 +1,13 @@
 #include "vmlinux.h"
 
+#define BACKEND_NAME_MAX_SIZE 64
+#define BACKEND_HEALTHCHECK_MAX_SIZE 128
+
+// LPM_TRIE のキーとなるネットワークプレフィックスの構造体です。
+// IPv4 のプレフィックスは IPv4-mapped IPv6 アドレス (::ffff:0:0/96) の中に格納して、prefix_len に 96 を足した値を指定します。
 struct network {
 	u32 prefix_len;
-	u32 address;
+	struct in6_addr address;
 };
 
 struct fw_rule {
@@ -12,10 +17,28 @@ struct fw_rule {
 	u16 from_dst_port;
 	u16 to_dst_port;
 	u32 protocol;
+	u32 priority; // 評価する順序です。XDP プログラムでは利用せず、scmlbd の再起動時にルールを復元するために保存しています。
+	u32 action; // ルールにマッチしたパケットの扱いです。enum FireWallAction の値を格納します。
+	struct network dst; // 宛先アドレスのプレフィックスです。prefix_len が 0 の場合はすべての宛先が対象です。
+};
+
+// adv_rulematcher のキーです。
+// fw_generation が指す世代のプレフィックスだけを検索できるように、generation をアドレスの前に置いて prefix_len に generation の 32 ビットを含めます。
+struct fw_matcher_key {
+	u32 prefix_len;
+	u32 generation;
+	struct in6_addr address;
+};
+
+// adv_rulematcher の値です。
+// adv_rule_lists の offset から len 個の要素に、ルール id が評価する順に並んでいます。
+struct fw_rule_list {
+	u32 offset;
+	u32 len;
 };
 
 struct dos_protection_identifier {
-	u32 address;
+	struct in6_addr address;
 	u8 protocol;
 	u8 packet_type;
 };
@@ -27,21 +50,57 @@ struct backend {
 	u32 status;
 	u8 src_macaddr[6];
 	u8 dst_macaddr[6];
-	u32 dst_ipaddr;
+	struct in6_addr dst_ipaddr; // IPv4 アドレスは IPv4-mapped IPv6 アドレスとして格納します。
+	struct in6_addr src_ipaddr; // DSR でカプセル化するときの外側のヘッダの送信元アドレスです。バックエンドにつながるデバイスのアドレスを格納します。
+	u32 forwarding; // バックエンドへの転送方式です。enum ForwardingMode の値を格納します。
 };
 
 // upstream の情報を格納する構造体です。
+// VIP はサービスごとに services マップに登録されます。
 struct upstream {
-	u32 ipaddr; // ロードバランサーが待ち受ける VIP です。
 	u16 ifindex; // VIP がついているデバイスではなく、実際にパケットを処理するデバイスの番号です。
 	u8 macaddr[6]; // こちらも実際にパケット処理を行うデバイスの MAC アドレスです。
 };
 
+// サービスを検索するためのキーとなる構造体です。
+// port と protocol が 0 のときはすべてのポート、プロトコルを対象とするサービスになります。
+// reverse_service マップではバックエンドのアドレスをキーにするためにも利用します。
+struct service_key {
+	struct in6_addr addr; // IPv4 アドレスは IPv4-mapped IPv6 アドレスとして格納します。
+	u16 port;
+	u8 protocol;
+	u8 pad;
+};
+
+// VIP とポート、プロトコルの組で表されるサービスの情報を格納する構造体です。
+struct service {
+	u32 id;
+	u32 scheduler; // バックエンドの選択方式です。enum Scheduler の値を格納します。
+	u32 rr_index; // ラウンドロビンで前回選択した rr_table のインデックスです。
+	u32 affinity_timeout; // 送信元アドレスによるセッション維持のアイドルタイムアウト(秒)です。0 のときはセッション維持を行いません。
+	u32 forwarding; // サービスの転送方式です。XDP プログラムでは利用せず、scmlbd の再起動時にサービスを復元するために保存しています。
+};
+
+// セッション維持のためのテーブルのキーとなる構造体です。
+// クライアントのアドレスとサービスの id の組でクライアントを識別します。
+struct affinity_key {
+	struct in6_addr client_addr;
+	u32 service_id;
+};
+
+// セッション維持のテーブルに保存するクライアントが割り当てられているバックエンドの情報です。
+struct affinity_info {
+	u32 backend_id;
+	u32 pad;
+	u64 last_seen; // 最後にパケットを受信した時刻です。bpf_ktime_get_ns() の値を格納します。
+};
+
 // 5-tuple (送信元アドレス/ポート、宛先アドレス/ポート、プロトコル) のセットの構造体です。
 // ロードバランサがコネクションを一位に識別するためのキーとなります。
+// アドレスは IPv4 の場合も IPv4-mapped IPv6 アドレスとして格納します。
 struct connection {
-	u32 src_addr;
-	u32 dst_addr;
+	struct in6_addr src_addr;
+	struct in6_addr dst_addr;
 	u16 src_port;
 	u16 dst_port;
 	u32 protocol;
@@ -52,21 +111,65 @@ struct connection {
 struct connection_info {
 	u32 backend_id;
 	u32 index;
-	u16 status; // TCP コネクションの状態を表します。
+	u8 status; // TCP コネクションの状態を表します。
+	u8 closer; // 先に FIN を送った向き(TcpDirection)を表します。
 	u8 src_macaddr[6]; // 送信元の MAC アドレス
 	u64 counter; // 受信したパケット数を記録するカウンター
+	u64 egress_packets; // バックエンドから送信されたパケット数
+	u64 ingress_bytes; // 受信したバイト数
+	u64 egress_bytes; // バックエンドから送信されたバイト数
+	u64 first_seen; // 最初のパケットを受信した時刻(bpf_ktime_get_ns)
+	u64 last_seen; // 最後にいずれかの方向のパケットを処理した時刻(bpf_ktime_get_ns)
 };
 
 // connection_info.status に格納するコネクションの状態を表す enum です。
 // UDP の場合はコネクションではないので NotTcp を代入します。
-// TCP の場合はコネクションの状態によって状態を遷移させます。
-// 実際の TCP の状態より簡略化して表現しています。
+// TCP の場合は両方向のパケットの TCP フラグによって状態を遷移させます。
+// 状態は Linux の nf_conntrack の TCP の状態に対応しています。
 enum ConnectionStatus {
 	NotTcp,
-	Opening,
+	SynSent, // クライアントの SYN を受信した状態
+	SynRecv, // バックエンドの SYN+ACK を受信した状態
 	Established,
-	Closing,
-	Closed,
+	FinWait, // 片方から FIN を受信した状態
+	CloseWait, // 先に FIN を送った側に反対側から ACK が返った状態(片方向のみ閉じている状態)
+	LastAck, // 両方向の FIN を受信して最後の ACK を待っている状態
+	TimeWait, // 最後の ACK を受信した状態
+	Closed, // RST を受信した状態
+};
+
+// conntrack のエントリーに対するパケットの向きを表す enum です。
+enum TcpDirection {
+	DirNone,
+	DirOriginal, // クライアントからバックエンドへの向き(Ingress)
+	DirReply, // バックエンドからクライアントへの向き(Egress)
+};
+
+// 新しいコネクションを割り当てるバックエンドの選択方式を表す enum です。
+// LeastConnection は コントロールプレーンがコネクション数の少ないバックエンドほど多く rr_table に格納するので、
+// XDP プログラムでは RoundRobin と同じように rr_table からバックエンドを選択します。
+enum Scheduler {
+	RoundRobin,
+	Maglev,
+	LeastConnection,
+};
+
+// バックエンドへのパケットの転送方式を表す enum です。
+// Nat は宛先アドレスをバックエンドのアドレスに書き換えて転送して、戻りパケットも lb_egress で処理します。
+// DsrIpip と DsrGue は宛先アドレスを VIP のままパケットをカプセル化して転送して、バックエンドはクライアントに直接応答します(Direct Server Return)。
+// ForwardingUnspecified はコントロールプレーンでバックエンドがサービスの設定に従うことを表す値で、XDP プログラムでは Nat と同じように扱います。
+enum ForwardingMode {
+	ForwardingUnspecified,
+	Nat,
+	DsrIpip,
+	DsrGue,
+};
+
+// fire wall のルールにマッチしたパケットの扱いを表す enum です。
+// Deny はパケットをドロップして、Allow は以降のルールを評価せずに次の機能に渡します。
+enum FireWallAction {
+	Deny,
+	Allow,
 };
 
 // ロードバランサーのバックエンドが利用可能な状態かどうかを示す enum です。
@@ -74,3 +177,25 @@ enum BackendStatus {
 	Available,
 	Unavailable,
 };
+
+// バックエンドの情報のうち XDP プログラムでは利用しないものを格納する構造体です。
+// scmlbd を再起動したときに backend_info マップと合わせてバックエンドを復元するために利用します。
+struct backend_meta {
+	u32 service_id;
+	u32 weight;
+	u32 forwarding; // バックエンドに指定された転送方式です。サービスの転送方式に従う場合は ForwardingUnspecified です。
+	u32 drained; // drain されている場合は 1 です。
+	s64 drain_deadline; // drain の期限を UNIX 時間(ナノ秒)で格納します。期限がない場合は 0 です。
+	char name[BACKEND_NAME_MAX_SIZE];
+	char healthcheck[BACKEND_HEALTHCHECK_MAX_SIZE];
+};
+
+// DoS protection policy を格納する構造体です。
+// XDP プログラムでは利用せず、scmlbd を再起動したときにポリシーを復元するために利用します。
+struct dosp_policy {
+	u64 limit;
+	u32 protocol;
+	u32 packet_type;
+	u32 status;
+	u32 pad;
+};
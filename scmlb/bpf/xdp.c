@@ -5,13 +5,31 @@
 #include "maps.h"
 #include "tail_call.h"
 #include "csum.h"
+#include "jhash.h"
 
 #define ETH_ALEN	6		/* Octets in one ethernet addr	 */
 #define ETH_P_IP 0x0800
+#define ETH_P_IPV6 0x86DD
 
 #define IP_PROTO_ICMP 1
 #define IP_PROTO_TCP 6
 #define IP_PROTO_UDP 17 
+#define IP_PROTO_ICMPV6 58
+// IP-in-IP のカプセル化で外側のヘッダに指定するプロトコル番号です。
+#define IP_PROTO_IPIP 4
+#define IP_PROTO_IPV6 41
+
+// GUE (Generic UDP Encapsulation) でカプセル化するときの宛先 UDP ポートです。
+// pkg/constants の GUE_PORT に対応しています。
+#define GUE_PORT 6080
+// GUE で外側の UDP ヘッダの送信元ポートに使う範囲です。経路上の ECMP でフローが分散されるようにクライアントのアドレスから計算します。
+#define GUE_SRC_PORT_MIN 49152
+#define GUE_SRC_PORT_RANGE 16384
+
+// ICMPv6 の近隣探索 (Neighbor Discovery) で利用されるメッセージのタイプの範囲です。
+// Router Solicitation(133) から Redirect(137) までが該当します。
+#define ICMPV6_NDP_TYPE_MIN 133
+#define ICMPV6_NDP_TYPE_MAX 137
 
 #define TCP_FLAG_FIN 1
 #define TCP_FLAG_SYN 2
@@ -22,10 +40,31 @@
 #define TCP_FLAG_ECE 64
 #define TCP_FLAG_CWR 128
 
-// ロードバランサーのバックエンド選択方式でラウンドロビンを利用するときに
-// 前回の選択結果のバックエンド id を記録しておくためのグローバル変数です。
+// ロードバランサーのバックエンド選択の結果のバックエンド id を記録しておくためのグローバル変数です。
 u32 selected_backend_id = 0;
-u32 selected_backend_index = 0;
+
+// 宛先アドレスがルールの宛先プレフィックスに含まれるとき 1 を返して、それ以外の場合は 0 を返す関数です
+// ルールの宛先プレフィックスのアドレスはコントロールプレーンでマスクして格納しています。
+static inline int fw_dst_match(struct fw_rule *rule, struct in6_addr *daddr) {
+	u32 prefix_len = rule->dst.prefix_len;
+	// 32 bit ずつプレフィックス長の分だけマスクして比較します。
+	for (int i = 0; i < 4; i++) {
+		if (prefix_len == 0) {
+			return 1;
+		}
+		u32 mask = 0xffffffff;
+		if (prefix_len < 32) {
+			mask = bpf_htonl(0xffffffff << (32 - prefix_len));
+			prefix_len = 0;
+		} else {
+			prefix_len -= 32;
+		}
+		if ((daddr->in6_u.u6_addr32[i] & mask) != rule->dst.address.in6_u.u6_addr32[i]) {
+			return 0;
+		}
+	}
+	return 1;
+}
 
 // ルールに対して与えたプロトコル番号とポートが対象のとき 1 を返して、それ以外の場合は 0 を返す関数です
 int fw_match(struct fw_rule *rule, u8 protocol, u16 src_port, u16 dst_port) {
@@ -82,175 +121,546 @@ void copy_backend(struct backend *src, struct backend *dst) {
 	__builtin_memcpy(dst->src_macaddr, src->src_macaddr, ETH_ALEN);
 	__builtin_memcpy(dst->dst_macaddr, src->dst_macaddr, ETH_ALEN);
 	dst->dst_ipaddr = src->dst_ipaddr;
+	dst->src_ipaddr = src->src_ipaddr;
+	dst->forwarding = src->forwarding;
 }
 
 // connection_info 構造体をコピーします
 void copy_connection_info(struct connection_info *src, struct connection_info *dst) {
 	dst->counter = src->counter;
+	dst->egress_packets = src->egress_packets;
+	dst->ingress_bytes = src->ingress_bytes;
+	dst->egress_bytes = src->egress_bytes;
+	dst->first_seen = src->first_seen;
+	dst->last_seen = src->last_seen;
 	dst->backend_id = src->backend_id;
 	dst->index = src->index;
 	dst->status = src->status;
+	dst->closer = src->closer;
 	__builtin_memcpy(dst->src_macaddr, src->src_macaddr, ETH_ALEN);
 }
 
+// IPv4 と IPv6 のパケットを共通に扱うために L3 ヘッダから取り出した情報を格納する構造体です。
+// アドレスは IPv4 の場合も IPv4-mapped IPv6 アドレス (::ffff:a.b.c.d) として格納します。
+struct l3_info {
+	struct iphdr *iph; // IPv4 パケットのときのみセットされます。
+	struct ipv6hdr *ip6h; // IPv6 パケットのときのみセットされます。
+	struct in6_addr saddr;
+	struct in6_addr daddr;
+	u8 protocol; // L4 のプロトコル番号です。
+};
+
+// IPv4 アドレスを IPv4-mapped IPv6 アドレスに変換して格納します。
+static inline void ipv4_mapped_addr(struct in6_addr *dst, u32 addr) {
+	dst->in6_u.u6_addr32[0] = 0;
+	dst->in6_u.u6_addr32[1] = 0;
+	dst->in6_u.u6_addr32[2] = bpf_htonl(0xffff);
+	dst->in6_u.u6_addr32[3] = addr;
+}
+
+// Ethernet ヘッダに続く IPv4 または IPv6 ヘッダを解析して l3_info 構造体に格納します。
+// 成功した場合は L3 ヘッダの長さを返します。
+// IPv4/IPv6 以外のパケットの場合は 0 を返して、ヘッダの長さが足りない不正なパケットの場合は -1 を返します。
+// IPv6 の拡張ヘッダはたどらないので、拡張ヘッダを持つパケットの protocol には拡張ヘッダの番号が格納されます。
+static __always_inline int parse_l3(struct ethhdr *ethh, void *data_end, struct l3_info *l3) {
+	void *data = (void *)(ethh + 1);
+	u16 proto = bpf_ntohs(ethh->h_proto);
+
+	if (proto == ETH_P_IP) {
+		struct iphdr *iph = data;
+		if (data + sizeof(*iph) > data_end) {
+			return -1;
+		}
+		l3->iph = iph;
+		l3->protocol = iph->protocol;
+		ipv4_mapped_addr(&l3->saddr, iph->saddr);
+		ipv4_mapped_addr(&l3->daddr, iph->daddr);
+		return sizeof(*iph);
+	}
+
+	if (proto == ETH_P_IPV6) {
+		struct ipv6hdr *ip6h = data;
+		if (data + sizeof(*ip6h) > data_end) {
+			return -1;
+		}
+		l3->ip6h = ip6h;
+		l3->protocol = ip6h->nexthdr;
+		l3->saddr = ip6h->saddr;
+		l3->daddr = ip6h->daddr;
+		return sizeof(*ip6h);
+	}
+
+	return 0;
+}
+
+// パケットカウンタや fire wall、DoS protector では ICMPv6 を ICMP と同じプロトコルとして扱うために、プロトコル番号を ICMP に揃えます。
+static inline u8 normalize_protocol(u8 protocol) {
+	if (protocol == IP_PROTO_ICMPV6) {
+		return IP_PROTO_ICMP;
+	}
+	return protocol;
+}
+
+// サービスのキーを作成して services マップを引きます。
+static inline struct service *lookup_service_elem(struct in6_addr *addr, u16 port, u8 protocol) {
+	struct service_key key;
+	__builtin_memset(&key, 0, sizeof(key));
+	key.addr = *addr;
+	key.port = port;
+	key.protocol = protocol;
+	return bpf_map_lookup_elem(&services, &key);
+}
+
+// パケットの宛先アドレス、ポート、プロトコルからサービスを検索します。
+// 完全に一致するサービスがなければプロトコルを 0、ポートを 0、さらにポートとプロトコルを 0 としたワイルドカードのサービスの順に探します。
+static inline struct service *lookup_service(struct in6_addr *addr, u16 port, u8 protocol) {
+	struct service *svc = lookup_service_elem(addr, port, protocol);
+	if (svc) {
+		return svc;
+	}
+	svc = lookup_service_elem(addr, port, 0);
+	if (svc) {
+		return svc;
+	}
+	svc = lookup_service_elem(addr, 0, protocol);
+	if (svc) {
+		return svc;
+	}
+	return lookup_service_elem(addr, 0, 0);
+}
+
+// バックエンドのアドレス、ポート、プロトコルの組から reverse_service マップを引きます。
+static inline struct in6_addr *lookup_reverse_service_elem(struct in6_addr *addr, u16 port, u8 protocol) {
+	struct service_key key;
+	__builtin_memset(&key, 0, sizeof(key));
+	key.addr = *addr;
+	key.port = port;
+	key.protocol = protocol;
+	return bpf_map_lookup_elem(&reverse_service, &key);
+}
+
+// バックエンドからの戻りパケットの送信元アドレス、ポート、プロトコルからサービスの VIP を検索します。
+// サービスの検索と同じようにワイルドカードのサービスも探します。
+static inline struct in6_addr *lookup_reverse_service(struct in6_addr *addr, u16 port, u8 protocol) {
+	struct in6_addr *vip = lookup_reverse_service_elem(addr, port, protocol);
+	if (vip) {
+		return vip;
+	}
+	vip = lookup_reverse_service_elem(addr, port, 0);
+	if (vip) {
+		return vip;
+	}
+	vip = lookup_reverse_service_elem(addr, 0, protocol);
+	if (vip) {
+		return vip;
+	}
+	return lookup_reverse_service_elem(addr, 0, 0);
+}
+
+// 128 bit のアドレスをハッシュ計算のために 32 bit に畳み込みます。
+static inline u32 fold_addr(struct in6_addr *addr) {
+	return addr->in6_u.u6_addr32[0] ^ addr->in6_u.u6_addr32[1] ^ addr->in6_u.u6_addr32[2] ^ addr->in6_u.u6_addr32[3];
+}
+
+// Maglev のルックアップテーブルを使ってバックエンドを選択します。
+// 5-tuple のハッシュ値からテーブルのインデックスを計算するので、同じコネクションのパケットは常に同じバックエンドが選ばれます。
+// 選択したバックエンドの値は グローバル変数の selected_backend_id に格納されます。
+static inline int select_backend_maglev(struct service *svc, struct connection *conn) {
+	u32 ports = ((u32)conn->src_port << 16) | conn->dst_port;
+	u32 hash = jhash_3words(fold_addr(&conn->src_addr), fold_addr(&conn->dst_addr), ports, conn->protocol);
+	u32 index = svc->id * MAGLEV_TABLE_SIZE + hash % MAGLEV_TABLE_SIZE;
+
+	u32 *backend_id = bpf_map_lookup_elem(&maglev_table, &index);
+	if (!backend_id || *backend_id == 0) {
+		// テーブルにバックエンドが登録されていないときはエラーで返ります。
+		return -1;
+	}
+	bpf_printk("select backend by maglev. id is %d", *backend_id);
+	selected_backend_id = *backend_id;
+
+	return 0;
+}
+
 // 新しいコネクションを処理するバックエンドを選択するための関数です。
+// Maglev 以外の選択方式では rr_table を順番にたどってバックエンドを選択します。
 // 選択したバックエンドの値は グローバル変数の selected_backend_id に格納されます。
-static inline int select_backend() {
+static inline int select_backend(struct service *svc, struct connection *conn) {
 
-	// rr_table から selected_backend_id + 1 の値を引きます。
-	u32 next = selected_backend_index + 1;
-	u32 *backend_id = bpf_map_lookup_elem(&rr_table, &next);
+	if (svc->scheduler == Maglev) {
+		return select_backend_maglev(svc, conn);
+	}
+
+	// サービスの rr_table の領域の先頭のインデックスです。
+	u32 base = svc->id * RR_TABLE_MAX_SIZE;
+
+	// rr_table から前回選択したインデックス + 1 の値を引きます。
+	u32 next = svc->rr_index + 1;
+	if (next >= RR_TABLE_MAX_SIZE) {
+		next = 0;
+	}
+	u32 key = base + next;
+	u32 *backend_id = bpf_map_lookup_elem(&rr_table, &key);
 	if (backend_id && *backend_id > 0) {
 		// 値が取れたときはその backend id を利用して backend_info マップを引いて通信を転送します。
 		bpf_printk("select backend. id is %d", *backend_id);
-		selected_backend_index = next;
+		svc->rr_index = next;
 		selected_backend_id = *backend_id;
 	} else {
 		// もし取れなかったときは rr_table 配列の最後に到達しているということなので折り返して index 0 で探索します。
-		u32 next = 0;
-		u32 *backend_id2 = bpf_map_lookup_elem(&rr_table, &next);
+		u32 key2 = base;
+		u32 *backend_id2 = bpf_map_lookup_elem(&rr_table, &key2);
 		if (!backend_id2 || *backend_id2 == 0) {
 			// ここでも 0 だったとき、rr_table には値が格納されていないとみなしてエラーで返ります。
 			return -1;
 		}
 		// 取れた場合はその値を selected_backend_id とする。
 		bpf_printk("select backend. id is %d", *backend_id2);
-		selected_backend_index = next;
+		svc->rr_index = 0;
 		selected_backend_id = *backend_id2;
 	}
 
 	return 0;
 }
 
-// Ingress の TCP コネクションの状態を処理します。
-static inline void process_tcp_state_ingress(struct tcphdr *tcph, struct connection_info *conn_info) {
-	// エントリーが取れた場合は connection_info 構造体にキャストします。
-	conn_info->counter++;
-	bpf_printk("existing backend id is %d. count up to %d", conn_info->backend_id, conn_info->counter);
+// セッション維持のテーブルのキーを作成します。
+static inline void build_affinity_key(struct affinity_key *key, struct service *svc, struct connection *conn) {
+	key->client_addr = conn->src_addr;
+	key->service_id = svc->id;
+}
 
-	if (tcph->fin) {
-		// TCP FIN フラグがセットされていたとき、Opening or Established -> Closing か Closing -> Closed に遷移する必要があります。
-		if (conn_info->status == Opening || conn_info->status == Established) {
-			conn_info->status = Closing;
-		} else if (conn_info->status == Closing) {
-			conn_info->status = Closed;
-		} else {
-			// その他の状態で FIN がきたときは Closed に遷移します。
-			conn_info->status = Closed;
+// セッション維持を考慮して新しいコネクションを処理するバックエンドを選択します。
+// サービスでセッション維持が有効な場合は、アイドルタイムアウト内にパケットを受信しているクライアントに対して前回と同じバックエンドを選択します。
+// 割り当てられていたバックエンドが削除されたり利用できない状態になっていた場合は通常の選択方式で選び直します。
+// 選択したバックエンドの値は グローバル変数の selected_backend_id に格納されます。
+static inline int select_backend_with_affinity(struct service *svc, struct connection *conn) {
+
+	if (svc->affinity_timeout == 0) {
+		return select_backend(svc, conn);
+	}
+
+	struct affinity_key key;
+	__builtin_memset(&key, 0, sizeof(key));
+	build_affinity_key(&key, svc, conn);
+
+	u64 now = bpf_ktime_get_ns();
+
+	struct affinity_info *info = bpf_map_lookup_elem(&affinity, &key);
+	if (info && now - info->last_seen < (u64)svc->affinity_timeout * 1000000000) {
+		struct backend *b = bpf_map_lookup_elem(&backend_info, &info->backend_id);
+		if (b && b->status == Available) {
+			bpf_printk("select backend by affinity. id is %d", info->backend_id);
+			info->last_seen = now;
+			selected_backend_id = info->backend_id;
+			return 0;
 		}
-	} else if (tcph->rst) {
-		// TCP RST フラグがセットされていたときは問答無用で Closed に遷移します。
-		conn_info->status = Closed;
-	} else {
-		// その他のフラグに関してはここではコネクションは継続とみなします。
+	}
+
+	int res = select_backend(svc, conn);
+	if (res != 0) {
+		return res;
+	}
+
+	struct affinity_info new_info;
+	__builtin_memset(&new_info, 0, sizeof(new_info));
+	new_info.backend_id = selected_backend_id;
+	new_info.last_seen = now;
+
+	// セッション維持のテーブルの更新に失敗してもパケットの転送は継続します。
+	bpf_map_update_elem(&affinity, &key, &new_info, 0);
+
+	return 0;
+}
+
+// 既存のコネクションのパケットを受信したときにセッション維持のテーブルの最終受信時刻を更新します。
+// コネクションが続いている間はアイドルタイムアウトで割り当てが失われないようにします。
+static inline void refresh_affinity(struct service *svc, struct connection *conn) {
+
+	if (svc->affinity_timeout == 0) {
+		return;
+	}
+
+	struct affinity_key key;
+	__builtin_memset(&key, 0, sizeof(key));
+	build_affinity_key(&key, svc, conn);
+
+	struct affinity_info *info = bpf_map_lookup_elem(&affinity, &key);
+	if (info) {
+		info->last_seen = bpf_ktime_get_ns();
 	}
 }
 
-// Egress の TCP コネクションの状態を処理します。
-static inline void process_tcp_state_egress(struct tcphdr *tcph, struct connection_info *conn_info) {
+// TCP コネクションの状態を処理します。
+// dir にはパケットの向きを渡します。
+// シーケンス番号は追跡せずに、TCP フラグとパケットの向きから nf_conntrack と同じように状態を遷移させます。
+static inline void process_tcp_state(struct tcphdr *tcph, struct connection_info *conn_info, u8 dir) {
+	u8 state = conn_info->status;
+
+	// RST フラグがセットされていたときはどの状態でも Closed に遷移します。
+	if (tcph->rst) {
+		conn_info->status = Closed;
+		return;
+	}
 
-	// TCP フラグと保存されているコネクションの状態をみてコネクションを処理します。
 	if (tcph->syn) {
-		// SYN フラグが戻りパケットについているとき、Opening -> Established と遷移します。
-		if (conn_info->status == Opening) {
+		if (tcph->ack) {
+			// バックエンドからの SYN+ACK で SynSent -> SynRecv と遷移します。
+			if (dir == DirReply && state == SynSent) {
+				conn_info->status = SynRecv;
+			}
+		} else if (dir == DirOriginal) {
+			// 終了したコネクションと同じ 5-tuple で新しいコネクションが開始された場合はエントリーを再利用します。
+			if (state == TimeWait || state == Closed) {
+				conn_info->status = SynSent;
+				conn_info->closer = DirNone;
+			}
+		} else if (state == SynSent) {
+			// バックエンドからも SYN がきた場合は同時オープンとして SynRecv に遷移します。
+			conn_info->status = SynRecv;
+		}
+		return;
+	}
+
+	if (tcph->fin) {
+		if (state == SynRecv || state == Established) {
+			// 最初の FIN で FinWait に遷移して、FIN を送った向きを記録します。
+			conn_info->status = FinWait;
+			conn_info->closer = dir;
+		} else if ((state == FinWait || state == CloseWait) && dir != conn_info->closer) {
+			// 反対側からも FIN がきたら両方向が閉じられたので LastAck に遷移します。
+			// 同時クローズの場合は FinWait から直接 LastAck に遷移します。
+			conn_info->status = LastAck;
+		}
+		return;
+	}
+
+	if (tcph->ack) {
+		if (state == SynRecv && dir == DirOriginal) {
+			// 3 way handshake の最後の ACK で Established に遷移します。
 			conn_info->status = Established;
+		} else if (state == FinWait && dir != conn_info->closer) {
+			// 反対側が FIN に ACK を返したら片方向のみ閉じられた CloseWait に遷移します。
+			conn_info->status = CloseWait;
+		} else if (state == LastAck && dir == conn_info->closer) {
+			// 先に FIN を送った側が最後の FIN に ACK を返したら TimeWait に遷移します。
+			conn_info->status = TimeWait;
 		}
-	} else if (tcph->rst) {
-		// RST フラグがセットされているときはどのような状態でも Closed に遷移します。
+	}
+}
+
+// DSR で転送するコネクションの TCP の状態を処理します。
+// DSR ではバックエンドがクライアントに直接応答するので、lb_egress() はバックエンドからのパケットを処理しません。
+// そのためクライアントからのパケットの TCP フラグのみで状態を遷移させます。
+static inline void process_tcp_state_dsr(struct tcphdr *tcph, struct connection_info *conn_info) {
+	u8 state = conn_info->status;
+
+	if (tcph->rst) {
 		conn_info->status = Closed;
+		return;
+	}
 
-	} else if (tcph->fin) {
-		// FIN フラグがついているときは Opening or Established -> Closing か Closing -> Closed に遷移します。
-		if (conn_info->status == Opening || conn_info->status == Established) {
-			conn_info->status = Closing;
-		} else if (conn_info->status == Closing) {
-			conn_info->status = Closed;
-		} else {
-			// 他の状態のときも Closed に遷移します。
-			conn_info->status = Closed;
+	if (tcph->syn) {
+		// 終了したコネクションと同じ 5-tuple で新しいコネクションが開始された場合はエントリーを再利用します。
+		if (!tcph->ack && (state == TimeWait || state == Closed)) {
+			conn_info->status = SynSent;
+			conn_info->closer = DirNone;
+		}
+		return;
+	}
+
+	if (tcph->fin) {
+		// クライアントの FIN で FinWait に遷移します。バックエンドの FIN は見えないので、以降は終了処理中のタイムアウトに従います。
+		if (state == SynSent || state == SynRecv || state == Established) {
+			conn_info->status = FinWait;
+			conn_info->closer = DirOriginal;
 		}
+		return;
+	}
+
+	// バックエンドの SYN+ACK は見えないので、3 way handshake の最後のクライアントの ACK で Established に遷移します。
+	if (tcph->ack && (state == SynSent || state == SynRecv)) {
+		conn_info->status = Established;
 	}
 }
 
-// UDP コネクションの状態を処理します。
-// UDP の conntrack エントリーは状態を管理しないのでここでは counter をカウントアップするだけです。
-// Ingress でのみ呼び出されます。
-static inline void process_udp_state(struct udphdr *udph, struct connection_info *conn_info) {
+// Ingress のパケットをフローの統計情報に記録します。
+// bytes には Ethernet ヘッダを含むパケットの長さを渡します。
+static inline void record_flow_ingress(struct connection_info *conn_info, u64 bytes) {
 	conn_info->counter++;
+	conn_info->ingress_bytes += bytes;
+	conn_info->last_seen = bpf_ktime_get_ns();
 }
 
-// Ingress の TCP パケットの書き換えを行います。
-// Ingress の TCP パケットは選択したバックエンドに転送するために宛先アドレスを VIP からバックエンドのアドレスに書き換えます。
-// それに伴って IP/TCP のチェックサムを再計算します。
-static inline void update_tcp_packet_ingress(struct iphdr *iph, struct tcphdr *tcph, u32 addr) {
-	// tcp checksum を再計算します。
-	// tcp checksum は tcp ヘッダを含めたデータ全体と送信元アドレス、宛先アドレス、プロトコル番号と tcp データを含めたパケット長を計算対象とします。
-	// このチェックサム計算のみに使うデたの塊を疑似ヘッダ(peseudo header) といいます。
-	// ここではアドレス書き換えの差分のみ計算できるようにしています。
-	u16 old_tcp_check = tcph->check;
-	tcph->check = ipv4_csum_update_u32(tcph->check, iph->daddr, addr);
-
-	// dnat のために ip header の daddr を書き換えます。
-	u32 old_daddr = iph->daddr;
-	iph->daddr = addr;
-	// ip checksum を再計算します。
-	// iph->check = ipv4_csum_update_u16(iph->check, old_tcp_check, tcph->check);
-	iph->check = ipv4_csum_update_u32(iph->check, old_daddr, iph->daddr);
-}
-
-// Ingress の UDP パケットの書き換えを行います。
-// Ingress の UDP パケットは選択したバックエンドに転送するために宛先アドレスを VIP からバックエンドのアドレスに書き換えます。
-// それに伴って IP/UDP のチェックサムを再計算します。
-static inline void update_udp_packet_ingress(struct iphdr *iph, struct udphdr *udph, u32 addr) {
-	// UDP checksum を再計算します。
-	// UDP checksum は tcp ヘッダを含めたデータ全体と送信元アドレス、宛先アドレス、プロトコル番号と tcp データを含めたパケット長を計算対象とします。
-	// このチェックサム計算のみに使うデたの塊を疑似ヘッダ(peseudo header) といいます。
-	// ここではアドレス書き換えの差分のみ計算できるようにしています。
-	u16 old_udp_check = udph->check;
-	udph->check = ipv4_csum_update_u32(udph->check, iph->daddr, addr);
-	u32 old_addr = iph->daddr;
-	// dnat のために ip header の daddr を書き換えます。
-	iph->daddr = addr;
-	iph->check = ipv4_csum_update_u32(iph->check, old_addr, iph->daddr);
-}
-
-// Egress の TCP パケットの書き換えを行います。
-// Egress のパケットはバックエンドからクライアントに送られるときに送信元アドレスを VIP にして送信されなければなりません。
-// それに伴って IP/TCP のチェックサムを再計算します。
-static inline void update_tcp_packet_egress(struct iphdr *iph, struct tcphdr *tcph, u32 addr) {
-	// tcp checksum を再計算します。
-	// ここでは 戻りパケットの src address を vip に書き換えています。
-	u16 old_tcp_check = tcph->check;
-	tcph->check = ipv4_csum_update_u32(tcph->check, iph->saddr, addr);
-
-	// reverse SNAT のために ip header の saddr を書き換えます。
-	u32 old_saddr = iph->saddr;
-	iph->saddr = addr;
-	// ip checksum を再計算します。
-	// iph->check = ipv4_csum_update_u16(iph->check, old_tcp_check, tcph->check);
-	iph->check = ipv4_csum_update_u32(iph->check, old_saddr, iph->saddr);
-}
-
-// Egress の UDP パケットの書き換えを行います。
+// Egress のパケットをフローの統計情報に記録します。
+// bytes には Ethernet ヘッダを含むパケットの長さを渡します。
+static inline void record_flow_egress(struct connection_info *conn_info, u64 bytes) {
+	conn_info->egress_packets++;
+	conn_info->egress_bytes += bytes;
+	conn_info->last_seen = bpf_ktime_get_ns();
+}
+
+// Ingress のパケットの書き換えを行います。
+// Ingress のパケットは選択したバックエンドに転送するために宛先アドレスを VIP からバックエンドのアドレスに書き換えます。
+// それに伴って TCP/UDP のチェックサムと、IPv4 の場合は IP ヘッダのチェックサムを再計算します。
+// TCP/UDP のチェックサムは送信元アドレス、宛先アドレスを含む疑似ヘッダ(peseudo header) も計算対象とします。
+// ここではアドレス書き換えの差分のみ計算できるようにしています。
+// l4_check には TCP/UDP ヘッダのチェックサムのフィールドのポインタを渡します。
+// DSR のバックエンドの場合は宛先アドレスを VIP のままカプセル化して転送するので何も書き換えません。
+static inline void update_packet_ingress(struct l3_info *l3, u16 *l4_check, struct backend *b) {
+	if (b->forwarding == DsrIpip || b->forwarding == DsrGue) {
+		return;
+	}
+	struct in6_addr *addr = &b->dst_ipaddr;
+	if (l3->iph) {
+		struct iphdr *iph = l3->iph;
+		u32 old_daddr = iph->daddr;
+		u32 new_daddr = addr->in6_u.u6_addr32[3];
+		*l4_check = ipv4_csum_update_u32(*l4_check, old_daddr, new_daddr);
+
+		// dnat のために ip header の daddr を書き換えます。
+		iph->daddr = new_daddr;
+		// ip checksum を再計算します。
+		iph->check = ipv4_csum_update_u32(iph->check, old_daddr, new_daddr);
+	} else if (l3->ip6h) {
+		struct ipv6hdr *ip6h = l3->ip6h;
+		*l4_check = ipv6_csum_update_addr(*l4_check, &ip6h->daddr, addr);
+
+		// IPv6 ヘッダにはチェックサムがないのでアドレスを書き換えるだけです。
+		ip6h->daddr = *addr;
+	}
+}
+
+// Egress のパケットの書き換えを行います。
 // Egress のパケットはバックエンドからクライアントに送られるときに送信元アドレスを VIP にして送信されなければなりません。
-// それに伴って IP/UDP のチェックサムを再計算します。
-static inline void update_udp_packet_egress(struct iphdr *iph, struct udphdr *udph, u32 addr) {
-	// udp checksum を再計算します。
-	u16 old_udp_check = udph->check;
-	udph->check = ipv4_csum_update_u32(udph->check, iph->saddr, addr);
+// それに伴ってチェックサムを再計算します。
+static inline void update_packet_egress(struct l3_info *l3, u16 *l4_check, struct in6_addr *addr) {
+	if (l3->iph) {
+		struct iphdr *iph = l3->iph;
+		u32 old_saddr = iph->saddr;
+		u32 new_saddr = addr->in6_u.u6_addr32[3];
+		*l4_check = ipv4_csum_update_u32(*l4_check, old_saddr, new_saddr);
+
+		// reverse SNAT のために ip header の saddr を書き換えます。
+		iph->saddr = new_saddr;
+		iph->check = ipv4_csum_update_u32(iph->check, old_saddr, new_saddr);
+	} else if (l3->ip6h) {
+		struct ipv6hdr *ip6h = l3->ip6h;
+		*l4_check = ipv6_csum_update_addr(*l4_check, &ip6h->saddr, addr);
+
+		ip6h->saddr = *addr;
+	}
+}
+
+// GUE ヘッダです。
+// GUE のバージョン 0 では制御メッセージとオプションを使わない場合は 4 バイトの固定長のヘッダになります。
+// 先頭のバイトはバージョン(2 bit)、C フラグ(1 bit)、オプションの長さ(5 bit) でここではすべて 0 にします。
+struct guehdr_base {
+	u8 hlen;
+	u8 proto_ctype;
+	u16 flags;
+};
+
+// DSR のために IPv4 のパケットを IP-in-IP もしくは GUE でカプセル化してバックエンドに転送します。
+// 外側の IPv4 ヘッダの宛先はバックエンドのアドレスで、内側のパケットの宛先は VIP のままです。
+// bpf_xdp_adjust_head() でパケットの先頭を広げたあとは、それ以前に取得したパケットのポインタは無効になるので取得し直します。
+// 外側の UDP ヘッダのチェックサムは IPv4 の場合は 0 (チェックサムなし) にできるので計算しません。
+static __always_inline int encap_ipv4(struct xdp_md *ctx, struct backend *b, u16 entropy) {
+	bool gue = b->forwarding == DsrGue;
+	int encap_len = sizeof(struct iphdr);
+	if (gue) {
+		encap_len += sizeof(struct udphdr) + sizeof(struct guehdr_base);
+	}
+	u16 inner_len = ctx->data_end - ctx->data - sizeof(struct ethhdr);
+
+	if (bpf_xdp_adjust_head(ctx, -encap_len) != 0) {
+		return XDP_DROP;
+	}
+
+	void *data = (void *)(long)ctx->data;
+	void *data_end = (void *)(long)ctx->data_end;
+
+	struct ethhdr *ethh = data;
+	struct iphdr *outer = data + sizeof(*ethh);
+	if ((void *)(outer + 1) > data_end) {
+		return XDP_DROP;
+	}
+	ethh->h_proto = bpf_htons(ETH_P_IP);
+
+	outer->version = 4;
+	outer->ihl = sizeof(*outer) >> 2;
+	outer->tos = 0;
+	outer->tot_len = bpf_htons(inner_len + encap_len);
+	outer->id = 0;
+	outer->frag_off = 0;
+	outer->ttl = 64;
+	outer->protocol = gue ? IP_PROTO_UDP : IP_PROTO_IPIP;
+	outer->check = 0;
+	outer->saddr = b->src_ipaddr.in6_u.u6_addr32[3];
+	outer->daddr = b->dst_ipaddr.in6_u.u6_addr32[3];
+
+	u64 csum = 0;
+	ipv4_csum_inline(outer, &csum);
+	outer->check = csum;
+
+	if (gue) {
+		struct udphdr *udph = (void *)(outer + 1);
+		struct guehdr_base *gueh = (void *)(udph + 1);
+		if ((void *)(gueh + 1) > data_end) {
+			return XDP_DROP;
+		}
+		udph->source = bpf_htons(GUE_SRC_PORT_MIN + entropy % GUE_SRC_PORT_RANGE);
+		udph->dest = bpf_htons(GUE_PORT);
+		udph->len = bpf_htons(inner_len + sizeof(*udph) + sizeof(*gueh));
+		udph->check = 0;
+
+		gueh->hlen = 0;
+		gueh->proto_ctype = IP_PROTO_IPIP;
+		gueh->flags = 0;
+	}
 
-	// reverse SNAT のために ip header の saddr を書き換えます。
-	u32 old_saddr = iph->saddr;
-	iph->saddr = addr;
-	// ip checksum を再計算します。
-	// iph->check = ipv4_csum_update_u16(iph->check, old_udp_check, udph->check);
-	iph->check = ipv4_csum_update_u32(iph->check, old_saddr, iph->saddr);
+	return redirect(ethh, b->src_macaddr, b->dst_macaddr, b->ifindex);
+}
+
+// DSR のために IPv6 のパケットを IPv6-in-IPv6 でカプセル化してバックエンドに転送します。
+// IPv6 では外側の UDP ヘッダのチェックサムを省略できないので、GUE には対応していません。
+// GUE のバックエンドはコントロールプレーンで IPv6 のサービスに登録できないようにしています。
+static __always_inline int encap_ipv6(struct xdp_md *ctx, struct backend *b) {
+	int encap_len = sizeof(struct ipv6hdr);
+	u16 inner_len = ctx->data_end - ctx->data - sizeof(struct ethhdr);
 
+	if (bpf_xdp_adjust_head(ctx, -encap_len) != 0) {
+		return XDP_DROP;
+	}
+
+	void *data = (void *)(long)ctx->data;
+	void *data_end = (void *)(long)ctx->data_end;
+
+	struct ethhdr *ethh = data;
+	struct ipv6hdr *outer = data + sizeof(*ethh);
+	if ((void *)(outer + 1) > data_end) {
+		return XDP_DROP;
+	}
+	ethh->h_proto = bpf_htons(ETH_P_IPV6);
+
+	outer->version = 6;
+	outer->priority = 0;
+	__builtin_memset(outer->flow_lbl, 0, sizeof(outer->flow_lbl));
+	outer->payload_len = bpf_htons(inner_len);
+	outer->nexthdr = IP_PROTO_IPV6;
+	outer->hop_limit = 64;
+	outer->saddr = b->src_ipaddr;
+	outer->daddr = b->dst_ipaddr;
+
+	return redirect(ethh, b->src_macaddr, b->dst_macaddr, b->ifindex);
 }
 
-// connection_info 造体を初期化します。
-static inline void new_connection_info(struct connection_info *conn_info, u32 backend_id, u32 ifindex, u8 mac_addr[6], u16 state) {
+// connection_info 構造体を初期化します。
+// bytes には最初のパケットの長さを渡します。
+static inline void new_connection_info(struct connection_info *conn_info, u32 backend_id, u32 ifindex, u8 mac_addr[6], u8 state, u64 bytes) {
+	u64 now = bpf_ktime_get_ns();
 	conn_info->counter = 1;
+	conn_info->ingress_bytes = bytes;
+	conn_info->first_seen = now;
+	conn_info->last_seen = now;
 	conn_info->backend_id = backend_id;
 	conn_info->index = ifindex;
 	__builtin_memcpy(conn_info->src_macaddr, mac_addr, ETH_ALEN);
@@ -259,48 +669,49 @@ static inline void new_connection_info(struct connection_info *conn_info, u32 ba
 
 // Ingress TCP パケット用の connection 構造体を作成します。
 // ここで作成した connection 構造体は conntrack に登録するために使われます。
-static inline void build_tcp_connection_ingress(struct connection *conn, struct iphdr *iph, struct tcphdr *tcph) {
-	conn->src_addr = iph->saddr;
-	conn->dst_addr = iph->daddr;
+static inline void build_tcp_connection_ingress(struct connection *conn, struct l3_info *l3, struct tcphdr *tcph) {
+	conn->src_addr = l3->saddr;
+	conn->dst_addr = l3->daddr;
 	conn->src_port = tcph->source;
 	conn->dst_port = tcph->dest;
-	conn->protocol = iph->protocol;
+	conn->protocol = l3->protocol;
 }
 
 // Ingress UDP パケット用の connection 構造体を作成します。
 // ここで作成した connection 構造体は conntrack に登録するために使われます。
-static inline void build_udp_connection_ingress(struct connection *conn, struct iphdr *iph, struct udphdr *udph) {
-	conn->src_addr = iph->saddr;
-	conn->dst_addr = iph->daddr;
+static inline void build_udp_connection_ingress(struct connection *conn, struct l3_info *l3, struct udphdr *udph) {
+	conn->src_addr = l3->saddr;
+	conn->dst_addr = l3->daddr;
 	conn->src_port = udph->source;
 	conn->dst_port = udph->dest;
-	conn->protocol = iph->protocol;
+	conn->protocol = l3->protocol;
 }
 
 // Egress TCP パケットの connection 構造体を作成します。
 // ここで作成した connection 構造体は conntrack に登録したエントリーを引くために利用されるので
 // 宛先アドレスを addr として明示的に渡しています(ここでは VIP を期待しています)。
-static inline void build_tcp_connection_egress(struct connection *conn, struct iphdr *iph, struct tcphdr *tcph, u32 addr) {
-	conn->src_addr = iph->daddr;
-	conn->dst_addr = addr;
+static inline void build_tcp_connection_egress(struct connection *conn, struct l3_info *l3, struct tcphdr *tcph, struct in6_addr *addr) {
+	conn->src_addr = l3->daddr;
+	conn->dst_addr = *addr;
 	conn->src_port = tcph->dest;
 	conn->dst_port = tcph->source;
-	conn->protocol = iph->protocol;
+	conn->protocol = l3->protocol;
 }
 
 // Egress UDP パケットの connection 構造体を作成します。
 // ここで作成した connection 構造体は conntrack に登録したエントリーを引くために利用されるので
 // 宛先アドレスを addr として明示的に渡しています(ここでは VIP を期待しています)。
-static inline void build_udp_connection_egress(struct connection *conn, struct iphdr *iph, struct udphdr *udph, u32 addr) {
-	conn->src_addr = iph->daddr;
-	conn->dst_addr = addr;
+static inline void build_udp_connection_egress(struct connection *conn, struct l3_info *l3, struct udphdr *udph, struct in6_addr *addr) {
+	conn->src_addr = l3->daddr;
+	conn->dst_addr = *addr;
 	conn->src_port = udph->dest;
 	conn->dst_port = udph->source;
-	conn->protocol = iph->protocol;
+	conn->protocol = l3->protocol;
 }
 
 // ロードバランサーの TCP パケットを処理する部分の関数です。
-static inline int handle_tcp_ingress(struct tcphdr *tcph, struct iphdr *iph, u8 src_macaddr[6], struct backend *target) {
+// pkt_len には統計情報に記録するパケットの長さを渡します。
+static inline int handle_tcp_ingress(struct tcphdr *tcph, struct l3_info *l3, struct service *svc, u8 src_macaddr[6], u64 pkt_len, struct backend *target) {
 
 	if (tcph == NULL) {
 		return -1;
@@ -309,14 +720,16 @@ static inline int handle_tcp_ingress(struct tcphdr *tcph, struct iphdr *iph, u8
 	// conntrack のエントリーを取得するために connection 構造体を宣言します。
 	struct connection conn;
 	__builtin_memset(&conn, 0, sizeof(conn));
-	build_tcp_connection_ingress(&conn, iph, tcph);
+	build_tcp_connection_ingress(&conn, l3, tcph);
 
 	void *r = bpf_map_lookup_elem(&conntrack, &conn);
 
 	if (r) {
 		// エントリーが取れた場合は connection_info 構造体にキャストします。
 		struct connection_info *conn_info = r;
-		process_tcp_state_ingress(tcph, conn_info);
+		record_flow_ingress(conn_info, pkt_len);
+		bpf_printk("existing backend id is %d. count up to %d", conn_info->backend_id, conn_info->counter);
+		refresh_affinity(svc, &conn);
 
 		// backend id からバックエンドの情報を取り出します。
 		void *res = bpf_map_lookup_elem(&backend_info, &conn_info->backend_id);
@@ -326,7 +739,14 @@ static inline int handle_tcp_ingress(struct tcphdr *tcph, struct iphdr *iph, u8
 		}
 		struct backend *b = res;
 
-		update_tcp_packet_ingress(iph, tcph, b->dst_ipaddr);
+		// DSR の場合はバックエンドからのパケットを処理しないので、クライアントからのパケットのみで状態を遷移させます。
+		if (b->forwarding == DsrIpip || b->forwarding == DsrGue) {
+			process_tcp_state_dsr(tcph, conn_info);
+		} else {
+			process_tcp_state(tcph, conn_info, DirOriginal);
+		}
+
+		update_packet_ingress(l3, &tcph->check, b);
 
 		// target backend を引数に渡したポインタに書き込みます。
 		copy_backend(b, target);
@@ -336,7 +756,19 @@ static inline int handle_tcp_ingress(struct tcphdr *tcph, struct iphdr *iph, u8
 
 	// もし conntrack にエントリーがない場合は新しいコネクションとして扱います。
 
-	int selection_result = select_backend();
+	// 新しいコネクションに対して TCP SYN フラグがついていない場合コネクションは確立されていないので無視します。
+	// ただし Maglev の場合は conntrack のエントリーが失われた既存のコネクションでも同じバックエンドを選択できるので、
+	// 確立済みのコネクションとして処理を継続します。
+	u8 state = SynSent;
+	if (tcph->syn != 1) {
+		if (svc->scheduler != Maglev) {
+			bpf_printk("new connection packet must be set syn flag");
+			return -1;
+		}
+		state = Established;
+	}
+
+	int selection_result = select_backend_with_affinity(svc, &conn);
 	if (selection_result != 0) {
 		return selection_result;
 	}
@@ -354,13 +786,7 @@ static inline int handle_tcp_ingress(struct tcphdr *tcph, struct iphdr *iph, u8
 
 	struct connection_info conn_info;
 	__builtin_memset(&conn_info, 0, sizeof(conn_info));
-	new_connection_info(&conn_info, b->id, b->ifindex, src_macaddr, Opening);
-
-	// 新しいコネクションに対して TCP SYN フラグがついていない場合コネクションは確立されていないので無視します。
-	if (tcph->syn != 1) {
-		bpf_printk("new connection packet must be set syn flag");
-		return -1;
-	}
+	new_connection_info(&conn_info, b->id, b->ifindex, src_macaddr, state, pkt_len);
 
 	// conntrack エントリーを保存します
 	int update_res = bpf_map_update_elem(&conntrack, &conn, &conn_info, 0);
@@ -368,7 +794,7 @@ static inline int handle_tcp_ingress(struct tcphdr *tcph, struct iphdr *iph, u8
 		return update_res;
 	}
 
-	update_tcp_packet_ingress(iph, tcph, b->dst_ipaddr);
+	update_packet_ingress(l3, &tcph->check, b);
 
 	// target backend を引数に渡したポインタに書き込みます。
 	copy_backend(b, target);
@@ -377,7 +803,8 @@ static inline int handle_tcp_ingress(struct tcphdr *tcph, struct iphdr *iph, u8
 }
 
 // ロードバランサーの UDP パケットを処理する部分の関数です。
-static inline int handle_udp_ingress(struct udphdr *udph, struct iphdr *iph, u8 src_macaddr[6], struct backend *target) {
+// pkt_len には統計情報に記録するパケットの長さを渡します。
+static inline int handle_udp_ingress(struct udphdr *udph, struct l3_info *l3, struct service *svc, u8 src_macaddr[6], u64 pkt_len, struct backend *target) {
 
 	if (udph == NULL) {
 		return -1;
@@ -386,14 +813,16 @@ static inline int handle_udp_ingress(struct udphdr *udph, struct iphdr *iph, u8
 	// conntrack のエントリーを取得するために connection 構造体を宣言します。
 	struct connection conn;
 	__builtin_memset(&conn, 0, sizeof(conn));
-	build_udp_connection_ingress(&conn, iph, udph);
+	build_udp_connection_ingress(&conn, l3, udph);
 
 	void *r = bpf_map_lookup_elem(&conntrack, &conn);
 	if (r) {
 		// エントリーが取れた場合は connection_info 構造体にキャストします。
 		struct connection_info *conn_info = r;
 
-		process_udp_state(udph, conn_info);
+		// UDP の conntrack エントリーは状態を管理しないので統計情報を記録するだけです。
+		record_flow_ingress(conn_info, pkt_len);
+		refresh_affinity(svc, &conn);
 
 		// backend id からバックエンドの情報を取り出します。
 
@@ -404,7 +833,7 @@ static inline int handle_udp_ingress(struct udphdr *udph, struct iphdr *iph, u8
 		}
 		struct backend *b = res;
 
-		update_udp_packet_ingress(iph, udph, b->dst_ipaddr);
+		update_packet_ingress(l3, &udph->check, b);
 
 		// target backend を引数に渡したポインタに書き込みます。
 		copy_backend(b, target);
@@ -417,7 +846,7 @@ static inline int handle_udp_ingress(struct udphdr *udph, struct iphdr *iph, u8
 	struct connection_info conn_info;
 	__builtin_memset(&conn_info, 0, sizeof(conn_info));
 
-	int selection_result = select_backend();
+	int selection_result = select_backend_with_affinity(svc, &conn);
 	if (selection_result != 0) {
 		bpf_printk("failed to select backend. errno is %d", selection_result);
 		return selection_result;
@@ -434,7 +863,7 @@ static inline int handle_udp_ingress(struct udphdr *udph, struct iphdr *iph, u8
 
 	struct backend *b = res;
 
-	new_connection_info(&conn_info, b->id, b->ifindex, src_macaddr, NotTcp);
+	new_connection_info(&conn_info, b->id, b->ifindex, src_macaddr, NotTcp, pkt_len);
 
 	// 新しい conntrack エントリーを保存します
 	int update_res = bpf_map_update_elem(&conntrack, &conn, &conn_info, 0);
@@ -442,7 +871,7 @@ static inline int handle_udp_ingress(struct udphdr *udph, struct iphdr *iph, u8
 		return update_res;
 	}
 
-	update_udp_packet_egress(iph, udph, b->dst_ipaddr);
+	update_packet_ingress(l3, &udph->check, b);
 	
 	// target backend を引数に渡したポインタに書き込みます。
 	copy_backend(b, target);
@@ -451,14 +880,14 @@ static inline int handle_udp_ingress(struct udphdr *udph, struct iphdr *iph, u8
 }
 
 // ロードバランサーの外向きの TCP パケットを処理する部分の関数です。
-static inline int handle_tcp_egress(struct tcphdr *tcph, struct iphdr *iph, struct upstream *us, struct connection_info *target) {
+static inline int handle_tcp_egress(struct tcphdr *tcph, struct l3_info *l3, struct in6_addr *vip, u64 pkt_len, struct connection_info *target) {
 
 	// egress の TCP パケットの処理を記述します。
 
 	// conntrack を引くための構造体を宣言します。
 	struct connection conn;
 	__builtin_memset(&conn, 0, sizeof(conn));
-	build_tcp_connection_egress(&conn, iph, tcph, us->ipaddr);
+	build_tcp_connection_egress(&conn, l3, tcph, vip);
 
 	// conntrack のエントリーを引きます。
 	void *conn_res = bpf_map_lookup_elem(&conntrack, &conn);
@@ -468,9 +897,10 @@ static inline int handle_tcp_egress(struct tcphdr *tcph, struct iphdr *iph, stru
 
 	struct connection_info *conn_info = conn_res;
 
-	process_tcp_state_egress(tcph, conn_info);
+	record_flow_egress(conn_info, pkt_len);
+	process_tcp_state(tcph, conn_info, DirReply);
 
-	update_tcp_packet_egress(iph, tcph, us->ipaddr);
+	update_packet_egress(l3, &tcph->check, vip);
 
 	// connection_info をコピーします。
 	copy_connection_info(conn_info, target);
@@ -479,12 +909,12 @@ static inline int handle_tcp_egress(struct tcphdr *tcph, struct iphdr *iph, stru
 }
 
 // ロードバランサーの外向きの UDP パケットを処理する部分の関数です。
-static inline int handle_udp_egress(struct udphdr *udph, struct iphdr *iph, struct upstream *us, struct connection_info *target) {
+static inline int handle_udp_egress(struct udphdr *udph, struct l3_info *l3, struct in6_addr *vip, u64 pkt_len, struct connection_info *target) {
 
 	// conntrack を引くための構造体を宣言します。
 	struct connection conn;
 	__builtin_memset(&conn, 0, sizeof(conn));
-	build_udp_connection_egress(&conn, iph, udph, us->ipaddr);
+	build_udp_connection_egress(&conn, l3, udph, vip);
 
 	// conntrack のエントリーを引きます。
 	void *conn_res = bpf_map_lookup_elem(&conntrack, &conn);
@@ -493,7 +923,9 @@ static inline int handle_udp_egress(struct udphdr *udph, struct iphdr *iph, stru
 	}
 	struct connection_info *info = conn_res;
 
-	update_udp_packet_egress(iph, udph, us->ipaddr);
+	record_flow_egress(info, pkt_len);
+
+	update_packet_egress(l3, &udph->check, vip);
 
 	// connection_info をコピーします。
 	copy_connection_info(info, target);
@@ -517,21 +949,20 @@ int count(struct xdp_md *ctx) {
 		return XDP_ABORTED;
 	}
 
-	// IPv4 パケットだけを集計の対象とする
-	if (bpf_ntohs(ethh->h_proto) != ETH_P_IP) {
-		return XDP_PASS;
-	}
-
-	data += sizeof(*ethh);
-
-	// Ethernet payload を IPv4 パケットにマッピングする
-	struct iphdr *iph = data;
-	if (data + sizeof(*iph) > data_end) {
+	// IPv4 と IPv6 パケットを対象とする
+	struct l3_info l3;
+	__builtin_memset(&l3, 0, sizeof(l3));
+	int l3_len = parse_l3(ethh, data_end, &l3);
+	if (l3_len < 0) {
 		return XDP_ABORTED;
 	}
+	if (l3_len == 0) {
+		return XDP_PASS;
+	}
 
 	// L4 のプロトコルに合わせてカウントアップする
-	u32 l4_protocol = (u32)iph->protocol;
+	// ICMPv6 は ICMP としてカウントします。
+	u32 l4_protocol = (u32)normalize_protocol(l3.protocol);
 	u32 initial_value = 1;
 	
 	u32 *c = bpf_map_lookup_elem(&counter, &l4_protocol);
@@ -563,66 +994,102 @@ int firewall(struct xdp_md *ctx) {
 		return XDP_ABORTED;
 	}
 
-	// IPv4 パケットだけを集計の対象とする
-	if (bpf_ntohs(ethh->h_proto) != ETH_P_IP) {
+	// IPv4 と IPv6 パケットを対象とする
+	struct l3_info l3;
+	__builtin_memset(&l3, 0, sizeof(l3));
+	int l3_len = parse_l3(ethh, data_end, &l3);
+	if (l3_len < 0) {
+		return XDP_ABORTED;
+	}
+	if (l3_len == 0) {
 		return XDP_PASS;
 	}
 
-	data += sizeof(*ethh);
+	data += sizeof(*ethh) + l3_len;
 
-	// Ethernet payload を IPv4 パケットにマッピングする
-	struct iphdr *iph = data;
-	if (data + sizeof(*iph) > data_end) {
-		return XDP_ABORTED;
+	u8 l4_protocol = normalize_protocol(l3.protocol);
+
+	// 近隣探索の ICMPv6 パケットは IPv6 の通信に必須なのでファイアウォールの対象外とします。
+	if (l3.protocol == IP_PROTO_ICMPV6) {
+		struct icmp6hdr *icmp6h = data;
+		if (data + sizeof(*icmp6h) > data_end) {
+			return XDP_ABORTED;
+		}
+		if (icmp6h->icmp6_type >= ICMPV6_NDP_TYPE_MIN && icmp6h->icmp6_type <= ICMPV6_NDP_TYPE_MAX) {
+			bpf_tail_call(ctx, &calls_map, TAIL_CALLED_FUNC_DOS_PROTECTOR);
+			return XDP_PASS;
+		}
 	}
 
-	data += sizeof(*iph);
+	// ルールを一括で適用している途中の状態が見えないように、パケットごとに最初に読み出した世代のエントリーだけを検索します
+	u32 generation_key = 0;
+	u32 *generation = bpf_map_lookup_elem(&fw_generation, &generation_key);
+	if (generation == NULL) {
+		return XDP_ABORTED;
+	}
 
-	struct network nw = {
-		.prefix_len = 32,
-		.address = iph->saddr,
-	};
+	struct fw_matcher_key key;
+	__builtin_memset(&key, 0, sizeof(key));
+	key.prefix_len = 32 + 128;
+	key.generation = *generation;
+	key.address = l3.saddr;
 
 	// LPM Trie マップを検索します
-	u16 *ids = bpf_map_lookup_elem(&adv_rulematcher, &nw);
-	if (ids) {
-		// 返ってきたポインタを u16 の配列にキャストします
-		for (int i = 0; i < FIRE_WALL_RULE_MAX_SIZE_PER_NETWORK; i++) {
-			if (ids[i] == 0) {
-				break;
+	// 値が指す adv_rule_lists の領域にはそのプレフィックスを含むすべてのプレフィックスのルールが評価する順に並んでいるので、最初にマッチしたルールに従います。
+	struct fw_rule_list *list = bpf_map_lookup_elem(&adv_rulematcher, &key);
+	if (list) {
+		// ルールごとにヘッダを解析しないように、ループの前にパケットのプロトコルを判別して port をとりだします
+		u16 src_port = 0;
+		u16 dst_port = 0;
+		if (l4_protocol == IP_PROTO_TCP) {
+			struct tcphdr *tcph = data;
+			if (data + sizeof(*tcph) > data_end) {
+				return XDP_ABORTED;
+			}
+			src_port = tcph->source;
+			dst_port = tcph->dest;
+		} else if (l4_protocol == IP_PROTO_UDP) {
+			struct udphdr *udph = data;
+			if (data + sizeof(*udph) > data_end) {
+				return XDP_ABORTED;
 			}
+			src_port = udph->source;
+			dst_port = udph->dest;
+		}
+		u32 len = list->len;
+		u32 offset = list->offset;
 
-			u32 id = (u32)ids[i];
+		for (u32 i = 0; i < FIRE_WALL_RULE_MAX_SIZE_PER_NETWORK; i++) {
+			if (i >= len) {
+				break;
+			}
 
+			u32 index = offset + i;
+			u32 *id = bpf_map_lookup_elem(&adv_rule_lists, &index);
+			if (id == NULL) {
+				break;
+			}
 
 			// map から id をキーとして rule をとりだします
-			void *rule_res = bpf_map_lookup_elem(&adv_rules, &id);
-			if (rule_res == NULL) {
+			struct fw_rule *rule = bpf_map_lookup_elem(&adv_rules, id);
+			if (rule == NULL) {
 				continue;
 			}
-			struct fw_rule *rule = rule_res;
 
-			// パケットのプロトコルを判別して port などの必要な値をとりだしてルールにマッチするか確かめます
-			int res = 0;
-			if (iph->protocol == IP_PROTO_ICMP) {
-				res = fw_match(rule, iph->protocol, 0, 0);
-			} else if (iph->protocol == IP_PROTO_TCP) {
-				struct tcphdr *tcph = data;
-				if (data + sizeof(*tcph) > data_end) {
-					return XDP_ABORTED;
-				}
+			// 宛先アドレスがルールの宛先プレフィックスに含まれない場合は次のルールを評価します
+			if (!fw_dst_match(rule, &l3.daddr)) {
+				continue;
+			}
 
-				res = fw_match(rule, iph->protocol, tcph->source, tcph->dest);
-			} else if (iph->protocol == IP_PROTO_UDP) {
-				struct udphdr *udph = data;
-				if (data + sizeof(*udph) > data_end) {
-					return XDP_ABORTED;
-				}
-				res = fw_match(rule, iph->protocol, udph->source, udph->dest);
+			// ICMP, TCP, UDP 以外のパケットはどのルールにもマッチしません
+			int res = 0;
+			if (l4_protocol == IP_PROTO_ICMP || l4_protocol == IP_PROTO_TCP || l4_protocol == IP_PROTO_UDP) {
+				res = fw_match(rule, l4_protocol, src_port, dst_port);
 			}
-			// もしルールにマッチしていたら drop_counter の値をカウントアップしてパケットをドロップします
+			// もしルールにマッチしていたら drop_counter の値をカウントアップします
+			// Deny のルールの場合はパケットをドロップして、Allow のルールの場合は以降のルールを評価せずに DoS protector に渡します
 			if (res == 1) {
-				bpf_printk("matched the rule: %d", id);
+				bpf_printk("matched the rule: %d", rule->id);
 				u64 *c = bpf_map_lookup_elem(&drop_counter, &rule->id);
 				if (c) {
 					(*c)++;
@@ -630,6 +1097,9 @@ int firewall(struct xdp_md *ctx) {
 					u64 init_value = 1;
 					bpf_map_update_elem(&drop_counter, &rule->id, &init_value, 0);
 				}
+				if (rule->action == Allow) {
+					break;
+				}
 				return XDP_DROP;
 			}
 		}
@@ -659,30 +1129,28 @@ int dos_protector(struct xdp_md *ctx) {
 		return XDP_ABORTED;
 	}
 
-	// IPv4 パケットだけを集計の対象とする
-	if (bpf_ntohs(ethh->h_proto) != ETH_P_IP) {
-		return XDP_PASS;
-	}
-
-	data += sizeof(*ethh);
-
-	// Ethernet payload を IPv4 パケットにマッピングする
-	struct iphdr *iph = data;
-	if (data + sizeof(*iph) > data_end) {
+	// IPv4 と IPv6 パケットを対象とする
+	struct l3_info l3;
+	__builtin_memset(&l3, 0, sizeof(l3));
+	int l3_len = parse_l3(ethh, data_end, &l3);
+	if (l3_len < 0) {
 		return XDP_ABORTED;
 	}
+	if (l3_len == 0) {
+		return XDP_PASS;
+	}
 
-	data += sizeof(*iph);
+	data += sizeof(*ethh) + l3_len;
 
 	// L4 プロトコルを判別する
-	u8 l4_protocol = iph->protocol;
-	u32 src_addr = iph->saddr;
+	// ICMPv6 は ICMP として集計します。
+	u8 l4_protocol = normalize_protocol(l3.protocol);
 
 	struct dos_protection_identifier ident;
 	// 構造体のメンバをすべて 0 で初期化しています。
 	// 初期化をしっかりやらないと verifier に怒られます。
 	__builtin_memset(&ident, 0, sizeof(ident));
-	ident.address = src_addr;
+	ident.address = l3.saddr;
 	ident.protocol = l4_protocol;
 
 	if (l4_protocol == IP_PROTO_ICMP) {
@@ -741,6 +1209,8 @@ int lb_ingress(struct xdp_md *ctx) {
 	// パケットのバイト列のはじまりのポインタ (data) とおわりのポインタ (data_end) を定義する
 	void *data = (void *)(long)ctx->data;
 	void *data_end = (void *)(long)ctx->data_end;
+	// フローの統計情報に記録するパケットの長さです。
+	u64 pkt_len = data_end - data;
 
 	// Ethernet header の構造体にパケットのデータをマッピングする
 	struct ethhdr *ethh = data;
@@ -750,22 +1220,20 @@ int lb_ingress(struct xdp_md *ctx) {
 		return XDP_ABORTED;
 	}
 
-	// IPv4 パケットだけを集計の対象とする
-	if (bpf_ntohs(ethh->h_proto) != ETH_P_IP) {
-		return XDP_PASS;
-	}
-
-	data += sizeof(*ethh);
-
-	// Ethernet payload を IPv4 パケットにマッピングする
-	struct iphdr *iph = data;
-	if (data + sizeof(*iph) > data_end) {
+	// IPv4 と IPv6 パケットを対象とする
+	struct l3_info l3;
+	__builtin_memset(&l3, 0, sizeof(l3));
+	int l3_len = parse_l3(ethh, data_end, &l3);
+	if (l3_len < 0) {
 		return XDP_ABORTED;
 	}
+	if (l3_len == 0) {
+		return XDP_PASS;
+	}
 
-	data += sizeof(*iph);
+	data += sizeof(*ethh) + l3_len;
 
-	u8 l4_protocol = iph->protocol;
+	u8 l4_protocol = l3.protocol;
 
 	// ロードバランサーでは TCP と UDP プロトコルを対象とします。
 	if (l4_protocol != IP_PROTO_TCP && l4_protocol != IP_PROTO_UDP) {
@@ -780,7 +1248,12 @@ int lb_ingress(struct xdp_md *ctx) {
 		if (data + sizeof(*tcph) > data_end) {
 			return XDP_ABORTED;
 		}
-		int res = handle_tcp_ingress(tcph, iph, ethh->h_source, &target);
+		// 宛先に対応するサービスがなければロードバランサーの対象外なので kernel にパスします。
+		struct service *svc = lookup_service(&l3.daddr, tcph->dest, IP_PROTO_TCP);
+		if (!svc) {
+			return XDP_PASS;
+		}
+		int res = handle_tcp_ingress(tcph, &l3, svc, ethh->h_source, pkt_len, &target);
 		if (res != 0) {
 			// 何かしらのエラーが発生した場合は kernel にパスします。
 			return XDP_PASS;
@@ -790,14 +1263,32 @@ int lb_ingress(struct xdp_md *ctx) {
 		if (data + sizeof(*udph) > data_end) {
 			return XDP_ABORTED;
 		}
-		int res = handle_udp_ingress(udph, iph, ethh->h_source, &target);
+		struct service *svc = lookup_service(&l3.daddr, udph->dest, IP_PROTO_UDP);
+		if (!svc) {
+			return XDP_PASS;
+		}
+		int res = handle_udp_ingress(udph, &l3, svc, ethh->h_source, pkt_len, &target);
 		if (res != 0) {
 			// 何かしらのエラーが発生した場合は kernel にパスします。
 			return XDP_PASS;
 		}
 	}
 
-	return target.ifindex != 0 ? redirect(ethh, target.src_macaddr, target.dst_macaddr, target.ifindex) : XDP_PASS;
+	if (target.ifindex == 0) {
+		return XDP_PASS;
+	}
+
+	// DSR のバックエンドにはパケットをカプセル化して転送します。
+	// バックエンドからの応答は VIP を送信元としてクライアントに直接送られるので lb_egress では処理されません。
+	if (target.forwarding == DsrIpip || target.forwarding == DsrGue) {
+		if (l3.ip6h) {
+			return encap_ipv6(ctx, &target);
+		}
+		u32 h = fold_addr(&l3.saddr);
+		return encap_ipv4(ctx, &target, (u16)(h ^ (h >> 16)));
+	}
+
+	return redirect(ethh, target.src_macaddr, target.dst_macaddr, target.ifindex);
 }
 
 SEC("xdp_lb_egress")
@@ -806,6 +1297,8 @@ int lb_egress(struct xdp_md *ctx) {
 	// パケットのバイト列のはじまりのポインタ (data) とおわりのポインタ (data_end) を定義する
 	void *data = (void *)(long)ctx->data;
 	void *data_end = (void *)(long)ctx->data_end;
+	// フローの統計情報に記録するパケットの長さです。
+	u64 pkt_len = data_end - data;
 
 	// Ethernet header の構造体にパケットのデータをマッピングする
 	struct ethhdr *ethh = data;
@@ -815,22 +1308,20 @@ int lb_egress(struct xdp_md *ctx) {
 		return XDP_ABORTED;
 	}
 
-	// IPv4 パケットだけを集計の対象とする
-	if (bpf_ntohs(ethh->h_proto) != ETH_P_IP) {
-		return XDP_PASS;
-	}
-
-	data += sizeof(*ethh);
-
-	// Ethernet payload を IPv4 パケットにマッピングする
-	struct iphdr *iph = data;
-	if (data + sizeof(*iph) > data_end) {
+	// IPv4 と IPv6 パケットを対象とする
+	struct l3_info l3;
+	__builtin_memset(&l3, 0, sizeof(l3));
+	int l3_len = parse_l3(ethh, data_end, &l3);
+	if (l3_len < 0) {
 		return XDP_ABORTED;
 	}
+	if (l3_len == 0) {
+		return XDP_PASS;
+	}
 
-	data += sizeof(*iph);
+	data += sizeof(*ethh) + l3_len;
 
-	u8 l4_protocol = iph->protocol;
+	u8 l4_protocol = l3.protocol;
 
 	// ロードバランサーでは TCP と UDP プロトコルを対象とします。
 	if (l4_protocol != IP_PROTO_TCP && l4_protocol != IP_PROTO_UDP) {
@@ -857,7 +1348,12 @@ int lb_egress(struct xdp_md *ctx) {
 		if (data + sizeof(*tcph) > data_end) {
 			return XDP_ABORTED;
 		}
-		int res = handle_tcp_egress(tcph, iph, us, &target);
+		// 送信元のバックエンドとポートからサービスの VIP を調べます。
+		struct in6_addr *vip = lookup_reverse_service(&l3.saddr, tcph->source, IP_PROTO_TCP);
+		if (!vip) {
+			return XDP_PASS;
+		}
+		int res = handle_tcp_egress(tcph, &l3, vip, pkt_len, &target);
 		if (res != 0) {
 			// 何かしらのエラーが発生した場合は kernel にパスします。
 			bpf_printk("tcp egress handle error %d", res);
@@ -868,7 +1364,11 @@ int lb_egress(struct xdp_md *ctx) {
 		if (data + sizeof(*udph) > data_end) {
 			return XDP_ABORTED;
 		}
-		int res = handle_udp_egress(udph, iph, us, &target);
+		struct in6_addr *vip = lookup_reverse_service(&l3.saddr, udph->source, IP_PROTO_UDP);
+		if (!vip) {
+			return XDP_PASS;
+		}
+		int res = handle_udp_egress(udph, &l3, vip, pkt_len, &target);
 		if (res != 0) {
 			// 何かしらのエラーが発生した場合は kernel にパスします。
 			bpf_printk("udp egress handle error %d", res);
@@ -9,6 +9,7 @@ import (
 	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/subcommands/dosprotection"
 	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/subcommands/fw"
 	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/subcommands/lb"
+	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/subcommands/service"
 	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/subcommands/stat"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
 )
@@ -39,6 +40,8 @@ func init() {
 	rootCmd.AddCommand(&dosprotection.DoSProtectionCmd)
 	// $ scmlb lb で呼び出される lb サブコマンドを登録しています。
 	rootCmd.AddCommand(&lb.LbCmd)
+	// $ scmlb service で呼び出される service サブコマンドを登録しています。
+	rootCmd.AddCommand(&service.ServiceCmd)
 }
 
 func main() {
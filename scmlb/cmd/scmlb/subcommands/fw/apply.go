@@ -0,0 +1,94 @@
+package fw
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/api"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/config"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/logger"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/rpc"
+	"golang.org/x/exp/slog"
+)
+
+var applyCmd = cobra.Command{
+	Use:   "apply",
+	Short: "apply fire wall rules in a file at once",
+	RunE:  executeApply,
+}
+
+func init() {
+	applyCmd.Flags().StringP("file", "f", "", "path of the YAML file describing fire wall rules in the same format as the firewall section of the scmlbd config")
+	applyCmd.Flags().Bool("replace", false, "replace all fire wall rules except rules added by DoS protection with the rules in the file")
+	applyCmd.Flags().Int32Slice("delete", nil, "rule ids to delete together with adding the rules in the file(can not be used with --replace)")
+
+	applyCmd.MarkFlagRequired("file")
+}
+
+func executeApply(cmd *cobra.Command, args []string) error {
+	out, err := logger.Output(constants.LogOutput)
+	if err != nil {
+		return err
+	}
+	logger := logger.SetUpLogger(constants.LogFormat, out, logger.ValidateLevel(constants.LogLevel))
+
+	path, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+	replace, err := cmd.Flags().GetBool("replace")
+	if err != nil {
+		return err
+	}
+	deleteIds, err := cmd.Flags().GetInt32Slice("delete")
+	if err != nil {
+		return err
+	}
+
+	rules, err := config.LoadFireWallRules(path)
+	if err != nil {
+		return err
+	}
+
+	req := &rpc.FireWallRuleApplyRequest{
+		Rules:     make([]*rpc.FireWallRule, 0, len(rules)),
+		DeleteIds: deleteIds,
+		Replace:   replace,
+	}
+	for _, r := range rules {
+		// 宛先のネットワークが指定されていない場合は空文字列で送ります。
+		var dstNetwork string
+		if r.DstPrefix.IsValid() {
+			dstNetwork = r.DstPrefix.String()
+		}
+		req.Rules = append(req.Rules, &rpc.FireWallRule{
+			Prefix:      r.Prefix.String(),
+			DstPrefix:   dstNetwork,
+			Protocol:    int32(r.Protocol),
+			FromSrcPort: int32(r.FromSrcPort),
+			ToSrcPort:   int32(r.ToSrcPort),
+			FromDstPort: int32(r.FromDstPort),
+			ToDstPort:   int32(r.ToDstPort),
+			Priority:    int32(r.Priority),
+			Action:      r.Action.String(),
+		})
+	}
+
+	logger.Debug("setup API client", slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+	client, closeF, err := api.NewClient(api.Endpoint, uint32(api.Port))
+	if err != nil {
+
+		logger.Error("failed to setup API client", err, slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+		return err
+	}
+	defer closeF()
+
+	res, err := client.FireWallRuleApply(cmd.Context(), req)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d rules added %v, %d rules deleted %v\n", len(res.AddedIds), res.AddedIds, len(res.DeletedIds), res.DeletedIds)
+
+	return nil
+}
@@ -14,4 +14,5 @@ func init() {
 	FwCmd.AddCommand(&setCmd)
 	FwCmd.AddCommand(&getCmd)
 	FwCmd.AddCommand(&deleteCmd)
+	FwCmd.AddCommand(&applyCmd)
 }
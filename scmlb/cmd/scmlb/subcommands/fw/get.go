@@ -16,7 +16,7 @@ import (
 
 var getCmd = cobra.Command{
 	Use:   "get",
-	Short: "get fire wall rules",
+	Short: "get fire wall rules in evaluation order",
 	RunE:  executeGet,
 }
 
@@ -49,11 +49,15 @@ func executeGet(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
-		data = append(data, []string{strconv.Itoa(int(r.Id)), r.Prefix, strconv.Itoa(int(r.FromSrcPort)), strconv.Itoa(int(r.ToSrcPort)), strconv.Itoa(int(r.FromDstPort)), strconv.Itoa(int(r.ToDstPort)), proto.String(), strconv.Itoa(int(r.Count))})
+		dstPrefix := r.DstPrefix
+		if dstPrefix == "" {
+			dstPrefix = "any"
+		}
+		data = append(data, []string{strconv.Itoa(int(r.Id)), r.Prefix, dstPrefix, strconv.Itoa(int(r.FromSrcPort)), strconv.Itoa(int(r.ToSrcPort)), strconv.Itoa(int(r.FromDstPort)), strconv.Itoa(int(r.ToDstPort)), proto.String(), r.Action, strconv.Itoa(int(r.Priority)), strconv.Itoa(int(r.Count))})
 	}
 
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"id", "network", "srcfromport", "srctoport", "dstfromport", "dsttoport", "protocol", "dropped"})
+	table.SetHeader([]string{"id", "network", "dstnetwork", "srcfromport", "srctoport", "dstfromport", "dsttoport", "protocol", "action", "priority", "matched"})
 	table.SetAutoWrapText(false)
 	table.SetAutoFormatHeaders(true)
 	table.SetHeaderAlignment(tablewriter.ALIGN_CENTER)
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/api"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/firewall"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/logger"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/rpc"
@@ -22,10 +23,13 @@ var setCmd = cobra.Command{
 }
 
 func init() {
-	setCmd.Flags().StringP("src-network", "n", "0.0.0.0/0", "source network range to deny by fire wall")
-	setCmd.Flags().StringP("protocol", "t", "any", "transport protocols to deny(expected value is any/icmp/tcp/udp)")
-	setCmd.Flags().StringP("src-port", "s", "0", "port range to deny(example: 22, 5000-6000)")
-	setCmd.Flags().StringP("dst-port", "d", "0", "port range to deny(example: 22, 5000-6000)")
+	setCmd.Flags().StringP("src-network", "n", "0.0.0.0/0", "source network range to match by fire wall")
+	setCmd.Flags().String("dst-network", "", "destination network range to match by fire wall(optional, all destinations are matched if not specified)")
+	setCmd.Flags().StringP("protocol", "t", "any", "transport protocols to match(expected value is any/icmp/tcp/udp)")
+	setCmd.Flags().StringP("src-port", "s", "0", "port range to match(example: 22, 5000-6000)")
+	setCmd.Flags().StringP("dst-port", "d", "0", "port range to match(example: 22, 5000-6000)")
+	setCmd.Flags().StringP("action", "a", "deny", "action for matched packets(expected value is allow/deny)")
+	setCmd.Flags().Int32("priority", 1000, "evaluation order of the rule. rules with smaller values are evaluated first and the first matched rule is applied")
 
 	setCmd.MarkFlagRequired("src-network")
 }
@@ -41,6 +45,10 @@ func executeSet(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	dstNetworkStr, err := cmd.Flags().GetString("dst-network")
+	if err != nil {
+		return err
+	}
 	protocolStr, err := cmd.Flags().GetString("protocol")
 	if err != nil {
 		return err
@@ -53,12 +61,29 @@ func executeSet(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	actionStr, err := cmd.Flags().GetString("action")
+	if err != nil {
+		return err
+	}
+	priority, err := cmd.Flags().GetInt32("priority")
+	if err != nil {
+		return err
+	}
 
 	network, err := netip.ParsePrefix(networkStr)
 	if err != nil {
 		return err
 	}
 
+	// 宛先のネットワークが指定されている場合はプレフィックスとして正しいか確認します。
+	if dstNetworkStr != "" {
+		dstNetwork, err := netip.ParsePrefix(dstNetworkStr)
+		if err != nil {
+			return err
+		}
+		dstNetworkStr = dstNetwork.String()
+	}
+
 	protocol, err := protocols.TransportProtocolFromString(protocolStr)
 	if err != nil {
 		return err
@@ -71,6 +96,13 @@ func executeSet(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	action, err := firewall.ActionFromString(actionStr)
+	if err != nil {
+		return err
+	}
+	if priority < 0 {
+		return fmt.Errorf("priority must not be negative: %d", priority)
+	}
 
 	logger.Debug("setup API client", slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
 	client, closeF, err := api.NewClient(api.Endpoint, uint32(api.Port))
@@ -84,11 +116,14 @@ func executeSet(cmd *cobra.Command, args []string) error {
 	_, err = client.FireWallRuleSet(cmd.Context(), &rpc.FireWallRuleSetRqeust{
 		Rule: &rpc.FireWallRule{
 			Prefix:      network.String(),
+			DstPrefix:   dstNetworkStr,
 			Protocol:    int32(protocol),
 			FromSrcPort: int32(srcFrom),
 			ToSrcPort:   int32(srcTo),
 			FromDstPort: int32(dstFrom),
 			ToDstPort:   int32(dstTo),
+			Priority:    priority,
+			Action:      action.String(),
 		},
 	})
 	if err != nil {
@@ -0,0 +1,17 @@
+package affinity
+
+import "github.com/spf13/cobra"
+
+var AffinityCmd = cobra.Command{
+	Use:   "affinity",
+	Short: "source ip based session affinity related commands",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+}
+
+func init() {
+	AffinityCmd.AddCommand(&setCmd)
+	AffinityCmd.AddCommand(&getCmd)
+	AffinityCmd.AddCommand(&flushCmd)
+}
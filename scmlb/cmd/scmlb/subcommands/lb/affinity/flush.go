@@ -0,0 +1,51 @@
+package affinity
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/api"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/logger"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/rpc"
+	"golang.org/x/exp/slog"
+)
+
+var flushCmd = cobra.Command{
+	Use:   "flush",
+	Short: "flush session affinity entries",
+	RunE:  executeFlush,
+}
+
+func init() {
+	flushCmd.Flags().Int32P("service", "s", 0, "service id to flush entries. 0 means all services")
+}
+
+func executeFlush(cmd *cobra.Command, args []string) error {
+	out, err := logger.Output(constants.LogOutput)
+	if err != nil {
+		return err
+	}
+	logger := logger.SetUpLogger(constants.LogFormat, out, logger.ValidateLevel(constants.LogLevel))
+
+	serviceId, err := cmd.Flags().GetInt32("service")
+	if err != nil {
+		return err
+	}
+
+	logger.DebugCtx(cmd.Context(), "flush session affinity entries", slog.Int("service id", int(serviceId)))
+
+	logger.Debug("setup API client", slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+	client, closeF, err := api.NewClient(api.Endpoint, uint32(api.Port))
+	if err != nil {
+
+		logger.Error("failed to setup API client", err, slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+		return err
+	}
+	defer closeF()
+
+	if _, err := client.LoadBalancerAffinityFlush(cmd.Context(), &rpc.LoadBalancerAffinityFlushRequest{
+		ServiceId: serviceId,
+	}); err != nil {
+		return err
+	}
+	return nil
+}
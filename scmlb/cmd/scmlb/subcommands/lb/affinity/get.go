@@ -0,0 +1,81 @@
+package affinity
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/api"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/logger"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/rpc"
+	"golang.org/x/exp/slog"
+)
+
+var getCmd = cobra.Command{
+	Use:   "get",
+	Short: "get session affinity entries",
+	RunE:  executeGet,
+}
+
+func init() {
+	getCmd.Flags().Int32P("service", "s", 0, "service id to filter entries. 0 means all services")
+}
+
+func executeGet(cmd *cobra.Command, args []string) error {
+	out, err := logger.Output(constants.LogOutput)
+	if err != nil {
+		return err
+	}
+
+	logger := logger.SetUpLogger(constants.LogFormat, out, logger.ValidateLevel(constants.LogLevel))
+
+	serviceId, err := cmd.Flags().GetInt32("service")
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("setup API client", slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+	client, closeF, err := api.NewClient(api.Endpoint, uint32(api.Port))
+	if err != nil {
+
+		logger.Error("failed to setup API client", err, slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+		return err
+	}
+	defer closeF()
+
+	res, err := client.LoadBalancerAffinityGet(cmd.Context(), &rpc.LoadBalancerAffinityGetRequest{
+		ServiceId: serviceId,
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.DebugCtx(cmd.Context(), "affinity entries", slog.Any("entries", res.Entries))
+
+	data := [][]string{}
+
+	for _, e := range res.Entries {
+		data = append(data, []string{strconv.Itoa(int(e.ServiceId)), e.ClientAddr, strconv.Itoa(int(e.BackendId)), (time.Duration(e.Idle) * time.Second).String(), strconv.FormatBool(e.Expired)})
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"service", "client addr", "backend id", "idle", "expired"})
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(true)
+	table.SetHeaderAlignment(tablewriter.ALIGN_CENTER)
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetTablePadding("\t")
+	table.SetNoWhiteSpace(true)
+	table.AppendBulk(data)
+
+	table.Render()
+
+	return nil
+}
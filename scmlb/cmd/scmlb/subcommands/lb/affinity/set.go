@@ -0,0 +1,67 @@
+package affinity
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/api"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/logger"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/rpc"
+	"golang.org/x/exp/slog"
+)
+
+var setCmd = cobra.Command{
+	Use:   "set",
+	Short: "enable or disable session affinity of a service",
+	RunE:  executeSet,
+}
+
+func init() {
+	setCmd.Flags().Int32P("service", "s", 0, "service id to configure")
+	setCmd.Flags().DurationP("timeout", "t", time.Minute*5, "idle timeout of affinity entries. it is truncated to seconds")
+	setCmd.Flags().Bool("disable", false, "disable session affinity and flush its entries")
+
+	setCmd.MarkFlagRequired("service")
+}
+
+func executeSet(cmd *cobra.Command, args []string) error {
+	out, err := logger.Output(constants.LogOutput)
+	if err != nil {
+		return err
+	}
+	logger := logger.SetUpLogger(constants.LogFormat, out, logger.ValidateLevel(constants.LogLevel))
+
+	serviceId, err := cmd.Flags().GetInt32("service")
+	if err != nil {
+		return err
+	}
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	if err != nil {
+		return err
+	}
+	disable, err := cmd.Flags().GetBool("disable")
+	if err != nil {
+		return err
+	}
+
+	logger.DebugCtx(cmd.Context(), "set session affinity", slog.Int("service id", int(serviceId)), slog.Duration("timeout", timeout), slog.Bool("disable", disable))
+
+	logger.Debug("setup API client", slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+	client, closeF, err := api.NewClient(api.Endpoint, uint32(api.Port))
+	if err != nil {
+
+		logger.Error("failed to setup API client", err, slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+		return err
+	}
+	defer closeF()
+
+	if _, err := client.LoadBalancerAffinitySet(cmd.Context(), &rpc.LoadBalancerAffinitySetRequest{
+		ServiceId: serviceId,
+		Enabled:   !disable,
+		Timeout:   int64(timeout / time.Second),
+	}); err != nil {
+		return err
+	}
+	return nil
+}
@@ -12,4 +12,7 @@ var ConntrackCmd = cobra.Command{
 
 func init() {
 	ConntrackCmd.AddCommand(&getCmd)
+	ConntrackCmd.AddCommand(&statCmd)
+	ConntrackCmd.AddCommand(&watchCmd)
+	ConntrackCmd.AddCommand(&deleteCmd)
 }
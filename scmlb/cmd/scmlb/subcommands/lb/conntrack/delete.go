@@ -0,0 +1,116 @@
+package conntrack
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/api"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/logger"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/rpc"
+	"golang.org/x/exp/slog"
+)
+
+var deleteCmd = cobra.Command{
+	Use:   "delete",
+	Short: "delete connection tracking entries matching a 5-tuple or conditions",
+	RunE:  executeDelete,
+}
+
+func init() {
+	deleteCmd.Flags().String("src-addr", "", "source address of the entry to delete(requires --dst-addr, --src-port, --dst-port and --protocol)")
+	deleteCmd.Flags().String("dst-addr", "", "destination address of the entry to delete")
+	deleteCmd.Flags().Int32("src-port", 0, "source port of the entry to delete")
+	deleteCmd.Flags().Int32("dst-port", 0, "destination port of the entry to delete")
+	deleteCmd.Flags().StringP("protocol", "t", "any", "transport protocol of entries to delete(expected value is any/tcp/udp)")
+	deleteCmd.Flags().Int32P("backend-id", "b", 0, "delete entries assigned to this backend")
+	deleteCmd.Flags().String("src", "", "delete entries whose source address is in this prefix")
+	deleteCmd.Flags().Bool("all", false, "delete all entries")
+}
+
+func executeDelete(cmd *cobra.Command, args []string) error {
+	out, err := logger.Output(constants.LogOutput)
+	if err != nil {
+		return err
+	}
+
+	logger := logger.SetUpLogger(constants.LogFormat, out, logger.ValidateLevel(constants.LogLevel))
+
+	srcAddr, err := cmd.Flags().GetString("src-addr")
+	if err != nil {
+		return err
+	}
+	dstAddr, err := cmd.Flags().GetString("dst-addr")
+	if err != nil {
+		return err
+	}
+	srcPort, err := cmd.Flags().GetInt32("src-port")
+	if err != nil {
+		return err
+	}
+	dstPort, err := cmd.Flags().GetInt32("dst-port")
+	if err != nil {
+		return err
+	}
+	protocolStr, err := cmd.Flags().GetString("protocol")
+	if err != nil {
+		return err
+	}
+	protocol, err := protocols.TransportProtocolFromString(protocolStr)
+	if err != nil {
+		return err
+	}
+	backendId, err := cmd.Flags().GetInt32("backend-id")
+	if err != nil {
+		return err
+	}
+	src, err := cmd.Flags().GetString("src")
+	if err != nil {
+		return err
+	}
+	all, err := cmd.Flags().GetBool("all")
+	if err != nil {
+		return err
+	}
+
+	req := &rpc.LoadBalancerConntrackDeleteRequest{
+		BackendId: backendId,
+		SrcPrefix: src,
+		Protocol:  int32(protocol),
+		All:       all,
+	}
+	// 5-tuple のいずれかが指定されている場合は、そのエントリーのみを削除します。
+	if srcAddr != "" || dstAddr != "" || srcPort != 0 || dstPort != 0 {
+		if srcAddr == "" || dstAddr == "" || srcPort == 0 || dstPort == 0 || protocol == protocols.TransportProtocolAny {
+			return fmt.Errorf("--src-addr, --dst-addr, --src-port, --dst-port and --protocol are required to delete an entry by 5-tuple")
+		}
+		req = &rpc.LoadBalancerConntrackDeleteRequest{
+			Tuple: &rpc.ConntrackTuple{
+				SrcAddr:  srcAddr,
+				DstAddr:  dstAddr,
+				SrcPort:  srcPort,
+				DstPort:  dstPort,
+				Protocol: int32(protocol),
+			},
+		}
+	}
+
+	logger.Debug("setup API client", slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+	client, closeF, err := api.NewClient(api.Endpoint, uint32(api.Port))
+	if err != nil {
+
+		logger.Error("failed to setup API client", err, slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+		return err
+	}
+	defer closeF()
+
+	res, err := client.LoadBalancerConntrackDelete(cmd.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d entries deleted\n", res.Deleted)
+
+	return nil
+}
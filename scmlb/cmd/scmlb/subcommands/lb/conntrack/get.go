@@ -1,6 +1,7 @@
 package conntrack
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 	"time"
@@ -22,6 +23,19 @@ var getCmd = cobra.Command{
 	RunE:  executeGet,
 }
 
+func init() {
+	getCmd.Flags().Int32P("backend-id", "b", 0, "show only entries assigned to this backend(0 means all backends)")
+	getCmd.Flags().StringP("protocol", "t", "any", "show only entries of this transport protocol(expected value is any/tcp/udp)")
+	getCmd.Flags().StringSliceP("state", "s", nil, "show only entries in these states(expected value is nottcp/synsent/synrecv/established/finwait/closewait/lastack/timewait/closed)")
+	getCmd.Flags().String("src", "", "show only entries whose source address is in this prefix")
+	getCmd.Flags().Int32("dst-port", 0, "show only entries with this destination port(0 means all ports)")
+	getCmd.Flags().Duration("min-idle", 0, "show only entries idle for at least this duration")
+	getCmd.Flags().String("sort", "tuple", "sort key of entries(expected value is tuple/packets/bytes/last-seen)")
+	getCmd.Flags().BoolP("reverse", "r", false, "reverse the sort order")
+	getCmd.Flags().Int32P("limit", "l", 0, "maximum number of entries to show(0 means all entries)")
+	getCmd.Flags().String("page-token", "", "page token returned by the previous command to show the next entries")
+}
+
 func executeGet(cmd *cobra.Command, args []string) error {
 	out, err := logger.Output(constants.LogOutput)
 	if err != nil {
@@ -30,6 +44,59 @@ func executeGet(cmd *cobra.Command, args []string) error {
 
 	logger := logger.SetUpLogger(constants.LogFormat, out, logger.ValidateLevel(constants.LogLevel))
 
+	backendId, err := cmd.Flags().GetInt32("backend-id")
+	if err != nil {
+		return err
+	}
+	protocolStr, err := cmd.Flags().GetString("protocol")
+	if err != nil {
+		return err
+	}
+	protocol, err := protocols.TransportProtocolFromString(protocolStr)
+	if err != nil {
+		return err
+	}
+	stateStrs, err := cmd.Flags().GetStringSlice("state")
+	if err != nil {
+		return err
+	}
+	states := make([]int32, 0, len(stateStrs))
+	for _, s := range stateStrs {
+		state, err := loadbalancer.ConnectionStateFromString(s)
+		if err != nil {
+			return err
+		}
+		states = append(states, int32(state))
+	}
+	src, err := cmd.Flags().GetString("src")
+	if err != nil {
+		return err
+	}
+	dstPort, err := cmd.Flags().GetInt32("dst-port")
+	if err != nil {
+		return err
+	}
+	minIdle, err := cmd.Flags().GetDuration("min-idle")
+	if err != nil {
+		return err
+	}
+	sortBy, err := cmd.Flags().GetString("sort")
+	if err != nil {
+		return err
+	}
+	reverse, err := cmd.Flags().GetBool("reverse")
+	if err != nil {
+		return err
+	}
+	limit, err := cmd.Flags().GetInt32("limit")
+	if err != nil {
+		return err
+	}
+	pageToken, err := cmd.Flags().GetString("page-token")
+	if err != nil {
+		return err
+	}
+
 	logger.Debug("setup API client", slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
 	client, closeF, err := api.NewClient(api.Endpoint, uint32(api.Port))
 	if err != nil {
@@ -39,7 +106,18 @@ func executeGet(cmd *cobra.Command, args []string) error {
 	}
 	defer closeF()
 
-	res, err := client.LoadBalancerConntrackGet(cmd.Context(), &rpc.LoadBalancerConntrackGetRequest{})
+	res, err := client.LoadBalancerConntrackGet(cmd.Context(), &rpc.LoadBalancerConntrackGetRequest{
+		BackendId: backendId,
+		Protocol:  int32(protocol),
+		States:    states,
+		SrcPrefix: src,
+		DstPort:   dstPort,
+		MinIdle:   int64(minIdle / time.Second),
+		SortBy:    sortBy,
+		Reverse:   reverse,
+		PageSize:  limit,
+		PageToken: pageToken,
+	})
 	if err != nil {
 		return err
 	}
@@ -53,11 +131,11 @@ func executeGet(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
-		data = append(data, []string{e.SrcAddr, e.DstAddr, strconv.Itoa(int(e.SrcPort)), strconv.Itoa(int(e.DstPort)), protocol.String(), strconv.Itoa(int(e.BackendId)), loadbalancer.ConnectionState(uint8(e.Status)).String(), e.Timestamp.AsTime().UTC().Format(time.RFC3339)})
+		data = append(data, []string{e.SrcAddr, e.DstAddr, strconv.Itoa(int(e.SrcPort)), strconv.Itoa(int(e.DstPort)), protocol.String(), strconv.Itoa(int(e.BackendId)), loadbalancer.ConnectionState(uint8(e.Status)).String(), fmt.Sprintf("%d/%d", e.Counter, e.EgressPackets), fmt.Sprintf("%d/%d", e.IngressBytes, e.EgressBytes), e.FirstSeen.AsTime().UTC().Format(time.RFC3339), e.Timestamp.AsTime().UTC().Format(time.RFC3339)})
 	}
 
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"src addr", "dst addr", "src port", "dst port", "protocol", "backend id", "status", "timestamp"})
+	table.SetHeader([]string{"src addr", "dst addr", "src port", "dst port", "protocol", "backend id", "status", "packets(in/out)", "bytes(in/out)", "first seen", "last seen"})
 	table.SetAutoWrapText(false)
 	table.SetAutoFormatHeaders(true)
 	table.SetHeaderAlignment(tablewriter.ALIGN_CENTER)
@@ -72,5 +150,10 @@ func executeGet(cmd *cobra.Command, args []string) error {
 
 	table.Render()
 
+	// 続きのエントリーがある場合は次のページを表示するためのトークンを表示します。
+	if res.NextPageToken != "" {
+		fmt.Printf("\n%d of %d entries. next page token: %s\n", len(res.Entries), res.Total, res.NextPageToken)
+	}
+
 	return nil
 }
@@ -0,0 +1,86 @@
+package conntrack
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/api"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/loadbalancer"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/logger"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/rpc"
+	"golang.org/x/exp/slog"
+)
+
+var statCmd = cobra.Command{
+	Use:   "stat",
+	Short: "show idle timeouts and the number of evicted connection tracking entries",
+	RunE:  executeStat,
+}
+
+func executeStat(cmd *cobra.Command, args []string) error {
+	out, err := logger.Output(constants.LogOutput)
+	if err != nil {
+		return err
+	}
+
+	logger := logger.SetUpLogger(constants.LogFormat, out, logger.ValidateLevel(constants.LogLevel))
+
+	logger.Debug("setup API client", slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+	client, closeF, err := api.NewClient(api.Endpoint, uint32(api.Port))
+	if err != nil {
+
+		logger.Error("failed to setup API client", err, slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+		return err
+	}
+	defer closeF()
+
+	res, err := client.LoadBalancerConntrackStat(cmd.Context(), &rpc.LoadBalancerConntrackStatRequest{})
+	if err != nil {
+		return err
+	}
+
+	logger.DebugCtx(cmd.Context(), "conntrack eviction counters", slog.Bool("gc enabled", res.GcEnabled), slog.Any("counters", res.Counters))
+
+	data := [][]string{}
+
+	for _, c := range res.Counters {
+		protocol, err := protocols.NewTransportProtocol(uint32(c.Protocol))
+		if err != nil {
+			return err
+		}
+		state := loadbalancer.ConnectionState(uint8(c.Status))
+		// GC が無効な場合はタイムアウトが 0 の状態のエントリーはタイムアウトによる削除は行われません。
+		timeout := "disabled"
+		if state == loadbalancer.ConnectionStateClosed {
+			timeout = "immediate"
+		} else if c.Timeout > 0 {
+			timeout = (time.Duration(c.Timeout) * time.Second).String()
+		} else if res.GcEnabled {
+			timeout = "none"
+		}
+		data = append(data, []string{protocol.String(), state.String(), timeout, strconv.FormatUint(c.Evicted, 10)})
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"protocol", "status", "timeout", "evicted"})
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(true)
+	table.SetHeaderAlignment(tablewriter.ALIGN_CENTER)
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetTablePadding("\t")
+	table.SetNoWhiteSpace(true)
+	table.AppendBulk(data)
+
+	table.Render()
+
+	return nil
+}
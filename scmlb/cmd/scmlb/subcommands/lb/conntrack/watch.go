@@ -0,0 +1,89 @@
+package conntrack
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/api"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/loadbalancer"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/logger"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/rpc"
+	"golang.org/x/exp/slog"
+)
+
+var watchCmd = cobra.Command{
+	Use:   "watch",
+	Short: "watch connection tracking entries being created, changing state and deleted",
+	RunE:  executeWatch,
+}
+
+func init() {
+	watchCmd.Flags().Int32P("backend-id", "b", 0, "backend id to watch(0 means all backends)")
+}
+
+func executeWatch(cmd *cobra.Command, args []string) error {
+	out, err := logger.Output(constants.LogOutput)
+	if err != nil {
+		return err
+	}
+
+	logger := logger.SetUpLogger(constants.LogFormat, out, logger.ValidateLevel(constants.LogLevel))
+
+	backendId, err := cmd.Flags().GetInt32("backend-id")
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("setup API client", slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+	client, closeF, err := api.NewClient(api.Endpoint, uint32(api.Port))
+	if err != nil {
+
+		logger.Error("failed to setup API client", err, slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+		return err
+	}
+	defer closeF()
+
+	stream, err := client.LoadBalancerConntrackWatch(cmd.Context(), &rpc.LoadBalancerConntrackWatchRequest{
+		BackendId: backendId,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		logger.DebugCtx(cmd.Context(), "conntrack event", slog.Any("event", event))
+
+		e := event.Entry
+		protocol, err := protocols.NewTransportProtocol(uint32(e.Protocol))
+		if err != nil {
+			return err
+		}
+		typ := loadbalancer.ConntrackEventType(uint8(event.Type))
+		state := loadbalancer.ConnectionState(uint8(e.Status))
+
+		// 状態の変化や削除の理由をエントリーの情報のあとに表示します。
+		detail := state.String()
+		switch typ {
+		case loadbalancer.ConntrackEventUpdated:
+			detail = fmt.Sprintf("%s -> %s", loadbalancer.ConnectionState(uint8(event.PrevStatus)), state)
+		case loadbalancer.ConntrackEventDeleted:
+			detail = fmt.Sprintf("%s (%s)", state, event.Reason)
+		}
+
+		fmt.Printf("%s %-7s %s %s:%d -> %s:%d backend %d %s\n",
+			event.Timestamp.AsTime().Local().Format(time.RFC3339), typ, protocol,
+			e.SrcAddr, e.SrcPort, e.DstAddr, e.DstPort, e.BackendId, detail)
+	}
+}
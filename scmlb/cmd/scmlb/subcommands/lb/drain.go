@@ -1,6 +1,10 @@
 package lb
 
 import (
+	"fmt"
+	"io"
+	"time"
+
 	"github.com/spf13/cobra"
 	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/api"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
@@ -11,12 +15,14 @@ import (
 
 var drainCmd = cobra.Command{
 	Use:   "drain",
-	Short: "drain existing connection. mark unavailable to target backend.",
+	Short: "drain existing connection. mark unavailable to target backend and delete it when connections are finished.",
 	RunE:  executeDrain,
 }
 
 func init() {
 	drainCmd.Flags().Int32P("id", "i", 0, "backend id to delete")
+	drainCmd.Flags().DurationP("deadline", "d", 0, "deadline to delete a backend even if connections remain(0 means waiting until all connections are finished)")
+	drainCmd.Flags().BoolP("wait", "w", false, "wait until a backend is deleted and show the number of remaining connections")
 
 	drainCmd.MarkFlagRequired("id")
 }
@@ -32,6 +38,17 @@ func executeDrain(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	deadline, err := cmd.Flags().GetDuration("deadline")
+	if err != nil {
+		return err
+	}
+	if deadline < 0 {
+		return fmt.Errorf("deadline must not be negative: %s", deadline)
+	}
+	wait, err := cmd.Flags().GetBool("wait")
+	if err != nil {
+		return err
+	}
 
 	logger.DebugCtx(cmd.Context(), "drain backend", slog.Int("id", int(id)))
 
@@ -45,9 +62,39 @@ func executeDrain(cmd *cobra.Command, args []string) error {
 	defer closeF()
 
 	if _, err := client.LoadBalancerDrain(cmd.Context(), &rpc.LoadBalancerDrainRequest{
-		Id: id,
+		Id:       id,
+		Deadline: int64(deadline / time.Second),
 	}); err != nil {
 		return err
 	}
-	return nil
+
+	if !wait {
+		return nil
+	}
+
+	stream, err := client.LoadBalancerDrainWait(cmd.Context(), &rpc.LoadBalancerDrainWaitRequest{
+		Id: id,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		status, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if status.Completed {
+			fmt.Printf("backend %d is drained and deleted\n", status.Id)
+			return nil
+		}
+		if status.Deadline != nil {
+			fmt.Printf("backend %d: %d connections remaining (deadline %s)\n", status.Id, status.Remaining, status.Deadline.AsTime().Local().Format(time.RFC3339))
+		} else {
+			fmt.Printf("backend %d: %d connections remaining\n", status.Id, status.Remaining)
+		}
+	}
 }
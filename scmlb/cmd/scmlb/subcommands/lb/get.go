@@ -45,11 +45,11 @@ func executeGet(cmd *cobra.Command, args []string) error {
 	data := [][]string{}
 
 	for _, b := range backends.Backends {
-		data = append(data, []string{strconv.Itoa(int(b.Id)), b.Name, b.IpAddr, b.MacAddr, b.DevName, b.Healthcheck, loadbalancer.BackendStatus(b.Status).String()})
+		data = append(data, []string{strconv.Itoa(int(b.Id)), strconv.Itoa(int(b.ServiceId)), b.Name, b.IpAddr, b.MacAddr, b.DevName, b.Healthcheck, strconv.Itoa(int(b.Weight)), strconv.Itoa(int(b.ActiveConnections)), b.Forwarding, loadbalancer.BackendStatus(b.Status).String(), loadbalancer.HealthState(b.Health).String()})
 	}
 
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"id", "name", "ip addr", "mac addr", "device", "healthcheck", "status"})
+	table.SetHeader([]string{"id", "service", "name", "ip addr", "mac addr", "device", "healthcheck", "weight", "conns", "forwarding", "status", "health"})
 	table.SetAutoWrapText(false)
 	table.SetAutoFormatHeaders(true)
 	table.SetHeaderAlignment(tablewriter.ALIGN_CENTER)
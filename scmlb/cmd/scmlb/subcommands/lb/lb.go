@@ -2,6 +2,7 @@ package lb
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/subcommands/lb/affinity"
 	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/subcommands/lb/conntrack"
 )
 
@@ -20,4 +21,5 @@ func init() {
 	LbCmd.AddCommand(&drainCmd)
 
 	LbCmd.AddCommand(&conntrack.ConntrackCmd)
+	LbCmd.AddCommand(&affinity.AffinityCmd)
 }
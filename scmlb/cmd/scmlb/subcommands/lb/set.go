@@ -1,11 +1,14 @@
 package lb
 
 import (
+	"fmt"
+	"net"
 	"net/netip"
 
 	"github.com/spf13/cobra"
 	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/api"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/loadbalancer"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/logger"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/rpc"
 	"golang.org/x/exp/slog"
@@ -20,7 +23,12 @@ var SetCmd = cobra.Command{
 func init() {
 	SetCmd.Flags().StringP("name", "n", "", "name of a lb backend")
 	SetCmd.Flags().StringP("address", "a", "", "IP address of a lb backend")
-	SetCmd.Flags().StringP("healthcheck", "c", "/", "healthchecing path")
+	SetCmd.Flags().Int32P("service", "s", 0, "service id which a lb backend belongs to(can be omitted when only one service exists)")
+	SetCmd.Flags().Int32P("weight", "w", 1, "weight of a lb backend for weighted round robin")
+	SetCmd.Flags().StringP("healthcheck", "c", "/", "health check target(example: http://10.0.5.2:8080/health, tcp://10.0.5.2:7070, udp://10.0.5.2:9090)")
+	SetCmd.Flags().StringP("mac", "m", "", "MAC address of the next hop to a lb backend(resolved by the neighbor table if omitted)")
+	SetCmd.Flags().StringP("device", "d", "", "device name to send packets to a lb backend(resolved by the route table if omitted)")
+	SetCmd.Flags().StringP("forwarding", "f", "", fmt.Sprintf("forwarding mode of a lb backend(expected value is nat/ipip/gue, empty means the same as the service. gue uses udp port %d)", constants.GUE_PORT))
 
 	SetCmd.MarkFlagRequired("name")
 	SetCmd.MarkFlagRequired("address")
@@ -47,11 +55,47 @@ func executeSet(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	serviceId, err := cmd.Flags().GetInt32("service")
+	if err != nil {
+		return err
+	}
+
+	weight, err := cmd.Flags().GetInt32("weight")
+	if err != nil {
+		return err
+	}
+	if weight <= 0 {
+		return fmt.Errorf("weight must be positive: %d", weight)
+	}
+
+	forwardingStr, err := cmd.Flags().GetString("forwarding")
+	if err != nil {
+		return err
+	}
+	forwarding, err := loadbalancer.ForwardingModeFromString(forwardingStr)
+	if err != nil {
+		return err
+	}
+
 	addr, err := netip.ParseAddr(address)
 	if err != nil {
 		return err
 	}
 
+	mac, err := cmd.Flags().GetString("mac")
+	if err != nil {
+		return err
+	}
+	if mac != "" {
+		if _, err := net.ParseMAC(mac); err != nil {
+			return err
+		}
+	}
+	device, err := cmd.Flags().GetString("device")
+	if err != nil {
+		return err
+	}
+
 	logger.DebugCtx(cmd.Context(), "set backend", slog.String("name", name))
 
 	logger.Debug("setup API client", slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
@@ -67,6 +111,11 @@ func executeSet(cmd *cobra.Command, args []string) error {
 		Address:     addr.String(),
 		Name:        name,
 		Healthcheck: hc,
+		Weight:      weight,
+		ServiceId:   serviceId,
+		Forwarding:  forwarding.String(),
+		MacAddr:     mac,
+		Device:      device,
 	}); err != nil {
 		return err
 	}
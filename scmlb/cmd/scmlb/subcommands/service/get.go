@@ -0,0 +1,77 @@
+package service
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/api"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/logger"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/rpc"
+	"golang.org/x/exp/slog"
+)
+
+var getCmd = cobra.Command{
+	Use:   "get",
+	Short: "get load balancer services",
+	RunE:  executeGet,
+}
+
+func executeGet(cmd *cobra.Command, args []string) error {
+	out, err := logger.Output(constants.LogOutput)
+	if err != nil {
+		return err
+	}
+
+	logger := logger.SetUpLogger(constants.LogFormat, out, logger.ValidateLevel(constants.LogLevel))
+
+	logger.Debug("setup API client", slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+	client, closeF, err := api.NewClient(api.Endpoint, uint32(api.Port))
+	if err != nil {
+
+		logger.Error("failed to setup API client", err, slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+		return err
+	}
+	defer closeF()
+
+	services, err := client.ServiceGet(cmd.Context(), &rpc.ServiceGetRequest{})
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+
+	for _, s := range services.Services {
+		proto, err := protocols.NewTransportProtocol(uint32(s.Protocol))
+		if err != nil {
+			return err
+		}
+		affinity := "disabled"
+		if s.AffinityTimeout > 0 {
+			affinity = (time.Duration(s.AffinityTimeout) * time.Second).String()
+		}
+		data = append(data, []string{strconv.Itoa(int(s.Id)), s.Vip, strconv.Itoa(int(s.Port)), proto.String(), s.Scheduler, affinity, s.Forwarding})
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"id", "vip", "port", "protocol", "scheduler", "affinity", "forwarding"})
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(true)
+	table.SetHeaderAlignment(tablewriter.ALIGN_CENTER)
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetTablePadding("\t")
+	table.SetNoWhiteSpace(true)
+	table.AppendBulk(data)
+
+	table.Render()
+
+	return nil
+}
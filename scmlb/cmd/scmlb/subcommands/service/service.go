@@ -0,0 +1,17 @@
+package service
+
+import "github.com/spf13/cobra"
+
+var ServiceCmd = cobra.Command{
+	Use:   "service",
+	Short: "manage load balancer services",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+}
+
+func init() {
+	ServiceCmd.AddCommand(&setCmd)
+	ServiceCmd.AddCommand(&getCmd)
+	ServiceCmd.AddCommand(&deleteCmd)
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/spf13/cobra"
+	"github.com/terassyi/seccamp-xdp/scmlb/cmd/scmlb/api"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/loadbalancer"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/logger"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/rpc"
+	"golang.org/x/exp/slog"
+)
+
+var setCmd = cobra.Command{
+	Use:   "set",
+	Short: "set a load balancer service",
+	RunE:  executeSet,
+}
+
+func init() {
+	setCmd.Flags().StringP("vip", "v", "", "Virtual IP address of a service")
+	setCmd.Flags().Int32P("port", "p", 0, "port of a service(0 means all ports)")
+	setCmd.Flags().StringP("protocol", "t", "any", "transport protocol of a service(expected value is any/tcp/udp)")
+	setCmd.Flags().StringP("scheduler", "s", "rr", "backend scheduling algorithm(expected value is rr/maglev/lc)")
+	setCmd.Flags().StringP("forwarding", "f", "nat", "forwarding mode to backends(expected value is nat/ipip/gue)")
+
+	setCmd.MarkFlagRequired("vip")
+}
+
+func executeSet(cmd *cobra.Command, args []string) error {
+	out, err := logger.Output(constants.LogOutput)
+	if err != nil {
+		return err
+	}
+	logger := logger.SetUpLogger(constants.LogFormat, out, logger.ValidateLevel(constants.LogLevel))
+
+	vipStr, err := cmd.Flags().GetString("vip")
+	if err != nil {
+		return err
+	}
+	port, err := cmd.Flags().GetInt32("port")
+	if err != nil {
+		return err
+	}
+	protocolStr, err := cmd.Flags().GetString("protocol")
+	if err != nil {
+		return err
+	}
+	schedulerStr, err := cmd.Flags().GetString("scheduler")
+	if err != nil {
+		return err
+	}
+	forwardingStr, err := cmd.Flags().GetString("forwarding")
+	if err != nil {
+		return err
+	}
+
+	vip, err := netip.ParseAddr(vipStr)
+	if err != nil {
+		return err
+	}
+	if port < 0 || port > 65535 {
+		return fmt.Errorf("invalid port: %d", port)
+	}
+	protocol, err := protocols.TransportProtocolFromString(protocolStr)
+	if err != nil {
+		return err
+	}
+	scheduler, err := loadbalancer.SchedulerFromString(schedulerStr)
+	if err != nil {
+		return err
+	}
+	forwarding, err := loadbalancer.ForwardingModeFromString(forwardingStr)
+	if err != nil {
+		return err
+	}
+
+	logger.DebugCtx(cmd.Context(), "set service", slog.String("vip", vip.String()), slog.Int("port", int(port)), slog.String("protocol", protocol.String()))
+
+	logger.Debug("setup API client", slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+	client, closeF, err := api.NewClient(api.Endpoint, uint32(api.Port))
+	if err != nil {
+
+		logger.Error("failed to setup API client", err, slog.String("endpoint", api.Endpoint), slog.Int("port", api.Port))
+		return err
+	}
+	defer closeF()
+
+	if _, err := client.ServiceSet(cmd.Context(), &rpc.ServiceSetRequest{
+		Service: &rpc.Service{
+			Vip:        vip.String(),
+			Port:       port,
+			Protocol:   int32(protocol),
+			Scheduler:  scheduler.String(),
+			Forwarding: forwarding.String(),
+		},
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
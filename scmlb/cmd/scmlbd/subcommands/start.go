@@ -2,12 +2,15 @@ package subcommands
 
 import (
 	"log"
+	"net"
 	"net/netip"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/daemon"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/loadbalancer"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/loader"
 )
 
 // この関数はプログラムの起動時に一度だけ呼び出されます
@@ -16,9 +19,28 @@ func init() {
 	StartCmd.Flags().StringP("api-addr", "a", constants.API_SERVER_ENDPOINT, "API server serving address")
 	StartCmd.Flags().Int32P("api-port", "p", constants.API_SERVER_PORT, "API server serving port")
 	StartCmd.Flags().StringP("upstream", "u", "eth0", "upstream interface")
-	StartCmd.Flags().StringP("vip", "v", "", "Virtual IP address to expose as the service address")
-	StartCmd.Flags().BoolP("gc", "g", false, "enable conntrack GC")
-	StartCmd.Flags().DurationP("gc-time", "t", time.Hour, "lifetime of conntrack entries")
+	StartCmd.Flags().StringP("vip", "v", "", "Virtual IP address to expose as the default service address for all ports and protocols(optional)")
+	StartCmd.Flags().StringP("scheduler", "s", "rr", "backend scheduling algorithm of the default service(expected value is rr/maglev/lc)")
+	StartCmd.Flags().BoolP("gc", "g", false, "enable idle timeouts of TCP conntrack entries")
+	StartCmd.Flags().DurationP("gc-time", "t", time.Hour, "idle timeout of UDP conntrack entries. enforced without --gc(0 means no timeout)")
+	StartCmd.Flags().Duration("gc-tcp-opening", time.Minute, "idle timeout of TCP conntrack entries in SynSent and SynRecv states(0 means no timeout)")
+	StartCmd.Flags().Duration("gc-tcp-established", time.Hour, "idle timeout of TCP conntrack entries in Established state(0 means no timeout)")
+	StartCmd.Flags().Duration("gc-tcp-closing", 2*time.Minute, "idle timeout of TCP conntrack entries in FinWait, CloseWait and LastAck states(0 means no timeout)")
+	StartCmd.Flags().Duration("gc-tcp-time-wait", 2*time.Minute, "idle timeout of TCP conntrack entries in TimeWait state. enforced without --gc(0 means no timeout)")
+	StartCmd.Flags().Duration("healthcheck-interval", 5*time.Second, "interval of backend health checks")
+	StartCmd.Flags().Duration("healthcheck-timeout", time.Second, "timeout of each backend health check")
+	StartCmd.Flags().Uint32("healthcheck-rise", 2, "number of consecutive successes to mark a backend healthy")
+	StartCmd.Flags().Uint32("healthcheck-fall", 3, "number of consecutive failures to mark a backend unhealthy")
+	StartCmd.Flags().Bool("persist", false, "pin BPF maps and XDP links under /sys/fs/bpf/scmlb and restore the previous state from them")
+	StartCmd.Flags().Uint32("conntrack-max-entries", constants.CONNTRACK_MAX_SIZE, "maximum number of conntrack entries")
+	StartCmd.Flags().Bool("conntrack-lru", false, "evict the least recently used conntrack entry instead of refusing new flows when the conntrack map is full")
+	StartCmd.Flags().Uint32("backend-max-entries", constants.BACKEND_MAX_SIZE, "maximum number of load balancer backends")
+	StartCmd.Flags().Uint32("firewall-max-entries", constants.FIRE_WALL_RULE_MAX_SIZE, "maximum number of fire wall rules")
+	StartCmd.Flags().String("sync-peer", "", "API address(host:port) of the peer scmlbd to replicate conntrack entries to(optional)")
+	StartCmd.Flags().String("flow-collector", "", "address(host:port) of the IPFIX collector to export conntrack flow records to(optional)")
+	StartCmd.Flags().Duration("flow-active-timeout", time.Minute, "interval of exporting flow records of long-lived flows")
+	StartCmd.Flags().Uint32("flow-observation-domain", 0, "observation domain id of exported IPFIX messages")
+	StartCmd.Flags().String("config", "", "path of the YAML file describing services, backends, fire wall rules and DoS protection policies(optional). it is reloaded on SIGHUP")
 }
 
 // start サブコマンドの実体
@@ -43,7 +65,20 @@ var StartCmd = cobra.Command{
 		if err != nil {
 			log.Fatal(err)
 		}
-		vip, err := netip.ParseAddr(vipStr)
+		// --vip が指定されていない場合はデフォルトのサービスを作成しません。
+		// サービスは scmlb service set で追加します。
+		var vip netip.Addr
+		if vipStr != "" {
+			vip, err = netip.ParseAddr(vipStr)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		schedulerStr, err := cmd.Flags().GetString("scheduler")
+		if err != nil {
+			log.Fatal(err)
+		}
+		scheduler, err := loadbalancer.SchedulerFromString(schedulerStr)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -55,12 +90,140 @@ var StartCmd = cobra.Command{
 		if err != nil {
 			log.Fatal(err)
 		}
+		gcTcpOpening, err := cmd.Flags().GetDuration("gc-tcp-opening")
+		if err != nil {
+			log.Fatal(err)
+		}
+		gcTcpEstablished, err := cmd.Flags().GetDuration("gc-tcp-established")
+		if err != nil {
+			log.Fatal(err)
+		}
+		gcTcpClosing, err := cmd.Flags().GetDuration("gc-tcp-closing")
+		if err != nil {
+			log.Fatal(err)
+		}
+		gcTcpTimeWait, err := cmd.Flags().GetDuration("gc-tcp-time-wait")
+		if err != nil {
+			log.Fatal(err)
+		}
+		gcConfig := loadbalancer.GCConfig{
+			Enabled:        gc,
+			TcpOpening:     gcTcpOpening,
+			TcpEstablished: gcTcpEstablished,
+			TcpClosing:     gcTcpClosing,
+			TcpTimeWait:    gcTcpTimeWait,
+			Udp:            gcTime,
+		}
+		if gcTime < 0 || gcTcpOpening < 0 || gcTcpEstablished < 0 || gcTcpClosing < 0 || gcTcpTimeWait < 0 {
+			log.Fatal("conntrack gc timeouts must not be negative")
+		}
+
+		hcInterval, err := cmd.Flags().GetDuration("healthcheck-interval")
+		if err != nil {
+			log.Fatal(err)
+		}
+		hcTimeout, err := cmd.Flags().GetDuration("healthcheck-timeout")
+		if err != nil {
+			log.Fatal(err)
+		}
+		hcRise, err := cmd.Flags().GetUint32("healthcheck-rise")
+		if err != nil {
+			log.Fatal(err)
+		}
+		hcFall, err := cmd.Flags().GetUint32("healthcheck-fall")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if hcInterval <= 0 || hcTimeout <= 0 {
+			log.Fatal("health check interval and timeout must be greater than 0")
+		}
+		if hcRise == 0 || hcFall == 0 {
+			log.Fatal("health check rise and fall must be greater than 0")
+		}
+		hcConfig := loadbalancer.HealthCheckConfig{
+			Interval: hcInterval,
+			Timeout:  hcTimeout,
+			Rise:     hcRise,
+			Fall:     hcFall,
+		}
+
+		persist, err := cmd.Flags().GetBool("persist")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		conntrackMaxEntries, err := cmd.Flags().GetUint32("conntrack-max-entries")
+		if err != nil {
+			log.Fatal(err)
+		}
+		conntrackLRU, err := cmd.Flags().GetBool("conntrack-lru")
+		if err != nil {
+			log.Fatal(err)
+		}
+		backendMaxEntries, err := cmd.Flags().GetUint32("backend-max-entries")
+		if err != nil {
+			log.Fatal(err)
+		}
+		fwMaxEntries, err := cmd.Flags().GetUint32("firewall-max-entries")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if conntrackMaxEntries == 0 || backendMaxEntries == 0 || fwMaxEntries == 0 {
+			log.Fatal("max entries of maps must be greater than 0")
+		}
+		mapConfig := loader.MapConfig{
+			ConntrackMaxEntries: conntrackMaxEntries,
+			ConntrackLRU:        conntrackLRU,
+			BackendMaxEntries:   backendMaxEntries,
+			FireWallMaxEntries:  fwMaxEntries,
+		}
+
+		syncPeer, err := cmd.Flags().GetString("sync-peer")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if syncPeer != "" {
+			if _, _, err := net.SplitHostPort(syncPeer); err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		flowCollector, err := cmd.Flags().GetString("flow-collector")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if flowCollector != "" {
+			if _, _, err := net.SplitHostPort(flowCollector); err != nil {
+				log.Fatal(err)
+			}
+		}
+		flowActiveTimeout, err := cmd.Flags().GetDuration("flow-active-timeout")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if flowActiveTimeout <= 0 {
+			log.Fatal("flow active timeout must be greater than 0")
+		}
+		flowObservationDomain, err := cmd.Flags().GetUint32("flow-observation-domain")
+		if err != nil {
+			log.Fatal(err)
+		}
+		flowExportConfig := daemon.FlowExportConfig{
+			Collector:           flowCollector,
+			ActiveTimeout:       flowActiveTimeout,
+			ObservationDomainId: flowObservationDomain,
+		}
+
+		configPath, err := cmd.Flags().GetString("config")
+		if err != nil {
+			log.Fatal(err)
+		}
 
-		daemon, err := daemon.New(apiAddr, apiPort, upstream)
+		daemon, err := daemon.New(apiAddr, apiPort, upstream, persist, mapConfig, syncPeer, flowExportConfig, configPath)
 		if err != nil {
 			log.Fatal(err)
 		}
 		// daemon のループを開始
-		return daemon.Run(vip, gc, gcTime)
+		return daemon.Run(vip, scheduler, gcConfig, hcConfig)
 	},
 }
@@ -0,0 +1,445 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/dosprotector"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/firewall"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/loadbalancer"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
+	"gopkg.in/yaml.v3"
+)
+
+// 省略された値は scmlb fw set と scmlb dos set のフラグのデフォルト値と同じ値にします。
+const (
+	defaultFireWallPriority = 1000
+	defaultDoSLimit         = 256
+)
+
+// scmlbd start --config で指定する設定ファイルの内容です。
+// scmlbd は起動時と SIGHUP を受け取ったときに、ロードバランサー、fire wall、DoS protector をこの内容に合わせます。
+type Config struct {
+	// XDP プログラムをアタッチするデバイスです。空の場合は --upstream に従います。
+	Upstream string
+	// 登録するサービスです。vip を指定した場合はすべてのポートとプロトコルを対象とするデフォルトのサービスを含みます。
+	Services []Service
+	// 設定ファイルから削除されたバックエンドを drain してから削除するまでの期限です。0 の場合は conntrack のエントリーがなくなるまで待ちます。
+	DrainDeadline time.Duration
+	FireWallRules []firewall.FWRule
+	DoSPolicies   []dosprotector.Policy
+}
+
+type Service struct {
+	Vip       netip.Addr
+	Port      uint32
+	Protocol  protocols.TransportProtocol
+	Scheduler loadbalancer.Scheduler
+	// ForwardingModeUnspecified の場合は NAT です。
+	Forwarding      loadbalancer.ForwardingMode
+	AffinityTimeout time.Duration
+	Backends        []Backend
+}
+
+type Backend struct {
+	Name        string
+	Address     netip.Addr
+	HealthCheck string
+	// 0 の場合は 1 です。
+	Weight uint32
+	// ForwardingModeUnspecified の場合はサービスの転送方式に従います。
+	Forwarding loadbalancer.ForwardingMode
+	// MAC アドレスとデバイスはバックエンドを登録するときにのみ利用します。
+	// 省略した場合はロードバランサーで解決します。
+	MacAddress net.HardwareAddr
+	Device     string
+}
+
+func (s *Service) String() string {
+	return fmt.Sprintf("%s:%d/%s", s.Vip, s.Port, s.Protocol)
+}
+
+// 以下は YAML の構造に対応する構造体です。
+// 文字列で記述された値は Load で検証して Config の型に変換します。
+
+type config struct {
+	Upstream      string          `yaml:"upstream"`
+	Vip           string          `yaml:"vip"`
+	Scheduler     string          `yaml:"scheduler"`
+	DrainDeadline time.Duration   `yaml:"drain_deadline"`
+	Backends      []backend       `yaml:"backends"`
+	Services      []service       `yaml:"services"`
+	FireWall      []fireWallRule  `yaml:"firewall"`
+	DoSProtection []dosProtection `yaml:"dos_protection"`
+}
+
+type service struct {
+	Vip             string        `yaml:"vip"`
+	Port            uint32        `yaml:"port"`
+	Protocol        string        `yaml:"protocol"`
+	Scheduler       string        `yaml:"scheduler"`
+	Forwarding      string        `yaml:"forwarding"`
+	AffinityTimeout time.Duration `yaml:"affinity_timeout"`
+	Backends        []backend     `yaml:"backends"`
+}
+
+type backend struct {
+	Name        string `yaml:"name"`
+	Address     string `yaml:"address"`
+	HealthCheck string `yaml:"healthcheck"`
+	Weight      uint32 `yaml:"weight"`
+	Forwarding  string `yaml:"forwarding"`
+	MacAddress  string `yaml:"mac_address"`
+	Device      string `yaml:"device"`
+}
+
+type fireWallRule struct {
+	SrcNetwork string  `yaml:"src_network"`
+	DstNetwork string  `yaml:"dst_network"`
+	Protocol   string  `yaml:"protocol"`
+	SrcPort    string  `yaml:"src_port"`
+	DstPort    string  `yaml:"dst_port"`
+	Action     string  `yaml:"action"`
+	Priority   *uint32 `yaml:"priority"`
+}
+
+type dosProtection struct {
+	Protocol string `yaml:"protocol"`
+	Type     string `yaml:"type"`
+	Limit    uint64 `yaml:"limit"`
+}
+
+// 設定ファイルを読み込んで検証します。
+// vip と scheduler が省略されている場合は、引数の vip と scheduler(scmlbd start の --vip と --scheduler)に従います。
+func Load(path string, vip netip.Addr, scheduler loadbalancer.Scheduler) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// 記述の誤りに気づけるように、未知のキーはエラーにします。
+	var c config
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	// 空のファイルは何も登録しない設定として扱います。
+	if err := decoder.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	cfg, err := c.convert(vip, scheduler)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// scmlb fw apply -f で指定するファイルを読み込んで fire wall のルールに変換します。
+// ルールは scmlbd の設定ファイルの firewall と同じ形式で記述します。
+func LoadFireWallRules(path string) ([]firewall.FWRule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var c struct {
+		FireWall []fireWallRule `yaml:"firewall"`
+	}
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	rules := make([]firewall.FWRule, 0, len(c.FireWall))
+	var errs []error
+	for i := range c.FireWall {
+		r, err := c.FireWall[i].convert()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("firewall[%d]: %w", i, err))
+			continue
+		}
+		rules = append(rules, *r)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid fire wall rules %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+func (c *config) convert(vip netip.Addr, scheduler loadbalancer.Scheduler) (*Config, error) {
+	if c.DrainDeadline < 0 {
+		return nil, fmt.Errorf("drain_deadline must not be negative: %s", c.DrainDeadline)
+	}
+	cfg := &Config{
+		Upstream:      c.Upstream,
+		DrainDeadline: c.DrainDeadline,
+	}
+
+	// トップレベルの vip、scheduler、backends はデフォルトのサービスを表します。
+	if c.Vip != "" {
+		v, err := netip.ParseAddr(c.Vip)
+		if err != nil {
+			return nil, err
+		}
+		vip = v
+	}
+	if c.Scheduler != "" {
+		s, err := loadbalancer.SchedulerFromString(c.Scheduler)
+		if err != nil {
+			return nil, err
+		}
+		scheduler = s
+	}
+	services := c.Services
+	if vip.IsValid() {
+		services = append([]service{{
+			Vip:       vip.String(),
+			Protocol:  protocols.TransportProtocolAny.String(),
+			Scheduler: scheduler.String(),
+			Backends:  c.Backends,
+		}}, services...)
+	} else if len(c.Backends) > 0 {
+		return nil, fmt.Errorf("backends require vip of the default service")
+	}
+
+	var errs []error
+	keys := make(map[string]struct{})
+	for i := range services {
+		s, err := services[i].convert()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("services[%d]: %w", i, err))
+			continue
+		}
+		if _, ok := keys[s.String()]; ok {
+			errs = append(errs, fmt.Errorf("services[%d]: service %s is duplicated", i, s))
+			continue
+		}
+		keys[s.String()] = struct{}{}
+		cfg.Services = append(cfg.Services, *s)
+	}
+
+	for i := range c.FireWall {
+		r, err := c.FireWall[i].convert()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("firewall[%d]: %w", i, err))
+			continue
+		}
+		cfg.FireWallRules = append(cfg.FireWallRules, *r)
+	}
+
+	for i := range c.DoSProtection {
+		p, err := c.DoSProtection[i].convert()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("dos_protection[%d]: %w", i, err))
+			continue
+		}
+		cfg.DoSPolicies = append(cfg.DoSPolicies, *p)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (s *service) convert() (*Service, error) {
+	vip, err := netip.ParseAddr(s.Vip)
+	if err != nil {
+		return nil, err
+	}
+	if s.Port > 65535 {
+		return nil, fmt.Errorf("invalid port: %d", s.Port)
+	}
+	if s.Protocol == "" {
+		s.Protocol = protocols.TransportProtocolAny.String()
+	}
+	protocol, err := protocols.TransportProtocolFromString(s.Protocol)
+	if err != nil {
+		return nil, err
+	}
+	switch protocol {
+	case protocols.TransportProtocolAny, protocols.TransportProtocolTcp, protocols.TransportProtocolUdp:
+	default:
+		return nil, fmt.Errorf("unsupported protocol for service: %s", protocol)
+	}
+	if s.Scheduler == "" {
+		s.Scheduler = loadbalancer.SchedulerRoundRobin.String()
+	}
+	scheduler, err := loadbalancer.SchedulerFromString(s.Scheduler)
+	if err != nil {
+		return nil, err
+	}
+	forwarding, err := loadbalancer.ForwardingModeFromString(s.Forwarding)
+	if err != nil {
+		return nil, err
+	}
+	if s.AffinityTimeout < 0 || (s.AffinityTimeout != 0 && s.AffinityTimeout < time.Second) {
+		return nil, fmt.Errorf("affinity_timeout must be 0 or greater than or equal to 1s: %s", s.AffinityTimeout)
+	}
+
+	svc := &Service{
+		Vip:             vip,
+		Port:            s.Port,
+		Protocol:        protocol,
+		Scheduler:       scheduler,
+		Forwarding:      forwarding,
+		AffinityTimeout: s.AffinityTimeout.Truncate(time.Second),
+	}
+
+	// 同じサービスに同じアドレスのバックエンドは登録できません。
+	addrs := make(map[netip.Addr]struct{})
+	for i := range s.Backends {
+		b, err := s.Backends[i].convert()
+		if err != nil {
+			return nil, fmt.Errorf("backends[%d]: %w", i, err)
+		}
+		if b.Address.Is4() != vip.Is4() {
+			return nil, fmt.Errorf("backends[%d]: address family of backend %s does not match the service vip %s", i, b.Address, vip)
+		}
+		if _, ok := addrs[b.Address]; ok {
+			return nil, fmt.Errorf("backends[%d]: backend %s is duplicated", i, b.Address)
+		}
+		addrs[b.Address] = struct{}{}
+		svc.Backends = append(svc.Backends, *b)
+	}
+	return svc, nil
+}
+
+func (b *backend) convert() (*Backend, error) {
+	addr, err := netip.ParseAddr(b.Address)
+	if err != nil {
+		return nil, err
+	}
+	forwarding, err := loadbalancer.ForwardingModeFromString(b.Forwarding)
+	if err != nil {
+		return nil, err
+	}
+	weight := b.Weight
+	if weight == 0 {
+		weight = 1
+	}
+	backend := &Backend{
+		Name:        b.Name,
+		Address:     addr,
+		HealthCheck: b.HealthCheck,
+		Weight:      weight,
+		Forwarding:  forwarding,
+		Device:      b.Device,
+	}
+	if b.MacAddress != "" {
+		mac, err := net.ParseMAC(b.MacAddress)
+		if err != nil {
+			return nil, err
+		}
+		backend.MacAddress = mac
+	}
+	return backend, nil
+}
+
+func (r *fireWallRule) convert() (*firewall.FWRule, error) {
+	if r.SrcNetwork == "" {
+		r.SrcNetwork = "0.0.0.0/0"
+	}
+	prefix, err := netip.ParsePrefix(r.SrcNetwork)
+	if err != nil {
+		return nil, err
+	}
+	var dstPrefix netip.Prefix
+	if r.DstNetwork != "" {
+		dstPrefix, err = netip.ParsePrefix(r.DstNetwork)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r.Protocol == "" {
+		r.Protocol = protocols.TransportProtocolAny.String()
+	}
+	protocol, err := protocols.TransportProtocolFromString(r.Protocol)
+	if err != nil {
+		return nil, err
+	}
+	fromSrc, toSrc, err := parsePortRange(r.SrcPort)
+	if err != nil {
+		return nil, err
+	}
+	fromDst, toDst, err := parsePortRange(r.DstPort)
+	if err != nil {
+		return nil, err
+	}
+	action, err := firewall.ActionFromString(r.Action)
+	if err != nil {
+		return nil, err
+	}
+	priority := uint32(defaultFireWallPriority)
+	if r.Priority != nil {
+		priority = *r.Priority
+	}
+	return &firewall.FWRule{
+		Prefix:      prefix.Masked(),
+		FromSrcPort: fromSrc,
+		ToSrcPort:   toSrc,
+		FromDstPort: fromDst,
+		ToDstPort:   toDst,
+		Protocol:    protocol,
+		Priority:    priority,
+		Action:      action,
+		DstPrefix:   dstPrefix.Masked(),
+	}, nil
+}
+
+// 22 や 5000-6000 の形式のポートの範囲を解釈します。空文字列の場合はすべてのポートを表す 0 です。
+func parsePortRange(s string) (uint32, uint32, error) {
+	if s == "" {
+		return 0, 0, nil
+	}
+	from, to, found := strings.Cut(s, "-")
+	if !found {
+		to = from
+	}
+	f, err := strconv.ParseUint(from, 10, 16)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port range: %s", s)
+	}
+	t, err := strconv.ParseUint(to, 10, 16)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port range: %s", s)
+	}
+	if f > t {
+		return 0, 0, fmt.Errorf("invalid port range: %s", s)
+	}
+	return uint32(f), uint32(t), nil
+}
+
+func (p *dosProtection) convert() (*dosprotector.Policy, error) {
+	protocol, err := protocols.TransportProtocolFromString(p.Protocol)
+	if err != nil {
+		return nil, err
+	}
+	switch protocol {
+	case protocols.TransportProtocolIcmp, protocols.TransportProtocolTcp, protocols.TransportProtocolUdp:
+	default:
+		return nil, fmt.Errorf("invalid protocol: %s", protocol)
+	}
+	typ, err := protocols.TcpFlagFromString(p.Type)
+	if err != nil {
+		return nil, err
+	}
+	limit := p.Limit
+	if limit == 0 {
+		limit = defaultDoSLimit
+	}
+	return &dosprotector.Policy{
+		Protocol: protocol,
+		Type:     typ,
+		Limit:    limit,
+	}, nil
+}
@@ -12,7 +12,42 @@ const (
 )
 
 const (
-	ADVANCED_FIRE_WALL_MAX_SIZE_PER_NETWORK = 16
+	// bpf/include/maps.h の FIRE_WALL_RULE_MAX_SIZE_PER_NETWORK に対応しています。
+	ADVANCED_FIRE_WALL_MAX_SIZE_PER_NETWORK = 512
+)
+
+const (
+	// bpf/include/maps.h の RR_TABLE_MAX_SIZE に対応しています。
+	RR_TABLE_MAX_SIZE = 256
+	// bpf/include/maps.h の MAGLEV_TABLE_SIZE に対応しています。
+	MAGLEV_TABLE_SIZE = 65537
+	// bpf/include/maps.h の SERVICE_MAX_SIZE に対応しています。
+	SERVICE_MAX_SIZE = 16
+)
+
+const (
+	// bpf/include/maps.h の CONNTRACK_MAX_SIZE に対応しています。
+	// scmlbd start の --conntrack-max-entries のデフォルト値です。
+	CONNTRACK_MAX_SIZE = 2056
+	// bpf/include/maps.h の BACKEND_MAX_SIZE に対応しています。
+	// scmlbd start の --backend-max-entries のデフォルト値です。
+	BACKEND_MAX_SIZE = 16
+	// bpf/include/maps.h の FIRE_WALL_RULE_MAX_SIZE に対応しています。
+	// scmlbd start の --firewall-max-entries のデフォルト値です。
+	FIRE_WALL_RULE_MAX_SIZE = 1028
+)
+
+const (
+	// bpf/include/scmlb.h の BACKEND_NAME_MAX_SIZE に対応しています。
+	BACKEND_NAME_MAX_SIZE = 64
+	// bpf/include/scmlb.h の BACKEND_HEALTHCHECK_MAX_SIZE に対応しています。
+	BACKEND_HEALTHCHECK_MAX_SIZE = 128
+)
+
+const (
+	// bpf/xdp.c の GUE_PORT に対応しています。
+	// GUE でカプセル化されたパケットを受け取るバックエンドではこのポートで fou を待ち受ける必要があります。
+	GUE_PORT = 6080
 )
 
 var (
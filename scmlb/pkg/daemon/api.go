@@ -3,7 +3,9 @@ package daemon
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/netip"
+	"time"
 
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/dosprotector"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/firewall"
@@ -65,31 +67,59 @@ func (d *Daemon) Stat(ctx context.Context, in *rpc.StatRequest) (*rpc.StatRespon
 
 func (d *Daemon) FireWallRuleSet(ctx context.Context, in *rpc.FireWallRuleSetRqeust) (*emptypb.Empty, error) {
 
-	proto, err := protocols.NewTransportProtocol(uint32(in.Rule.Protocol))
+	rule, err := fireWallRuleFromRPC(in.Rule)
 	if err != nil {
 		return nil, err
 	}
 
-	prefix, err := netip.ParsePrefix(in.Rule.Prefix)
+	d.logger.InfoCtx(ctx, "add fire wall rule", slog.Any("rule", rule))
+	if _, err := d.fw.Set(rule); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func fireWallRuleFromRPC(r *rpc.FireWallRule) (*firewall.FWRule, error) {
+
+	proto, err := protocols.NewTransportProtocol(uint32(r.Protocol))
 	if err != nil {
 		return nil, err
 	}
 
-	rule := &firewall.FWRule{
-		Prefix:      prefix,
-		FromSrcPort: uint32(in.Rule.FromSrcPort),
-		ToSrcPort:   uint32(in.Rule.ToSrcPort),
-		FromDstPort: uint32(in.Rule.FromDstPort),
-		ToDstPort:   uint32(in.Rule.ToDstPort),
-		Protocol:    proto,
+	prefix, err := netip.ParsePrefix(r.Prefix)
+	if err != nil {
+		return nil, err
 	}
 
-	d.logger.InfoCtx(ctx, "add fire wall rule", slog.Any("rule", rule))
-	if _, err := d.fw.Set(rule); err != nil {
+	// 宛先のプレフィックスは省略可能で、空の場合はすべての宛先が対象です。
+	var dstPrefix netip.Prefix
+	if r.DstPrefix != "" {
+		dstPrefix, err = netip.ParsePrefix(r.DstPrefix)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	action, err := firewall.ActionFromString(r.Action)
+	if err != nil {
 		return nil, err
 	}
+	if r.Priority < 0 {
+		return nil, fmt.Errorf("priority must not be negative: %d", r.Priority)
+	}
 
-	return &emptypb.Empty{}, nil
+	return &firewall.FWRule{
+		Prefix:      prefix,
+		FromSrcPort: uint32(r.FromSrcPort),
+		ToSrcPort:   uint32(r.ToSrcPort),
+		FromDstPort: uint32(r.FromDstPort),
+		ToDstPort:   uint32(r.ToDstPort),
+		Protocol:    proto,
+		Priority:    uint32(r.Priority),
+		Action:      action,
+		DstPrefix:   dstPrefix,
+	}, nil
 }
 
 func (d *Daemon) FireWallRuleGet(ctx context.Context, in *rpc.FireWallRuleGetRequest) (*rpc.FireWallRuleGetResponse, error) {
@@ -103,6 +133,10 @@ func (d *Daemon) FireWallRuleGet(ctx context.Context, in *rpc.FireWallRuleGetReq
 		return nil, err
 	}
 	for _, r := range rr {
+		var dstPrefix string
+		if r.DstPrefix.IsValid() {
+			dstPrefix = r.DstPrefix.String()
+		}
 		rules = append(rules, &rpc.FireWallRule{
 			Id:          int32(r.Id),
 			Prefix:      r.Prefix.String(),
@@ -112,6 +146,9 @@ func (d *Daemon) FireWallRuleGet(ctx context.Context, in *rpc.FireWallRuleGetReq
 			ToDstPort:   int32(r.ToDstPort),
 			Protocol:    int32(r.Protocol),
 			Count:       int64(r.Count),
+			Priority:    int32(r.Priority),
+			Action:      r.Action.String(),
+			DstPrefix:   dstPrefix,
 		})
 	}
 
@@ -130,6 +167,54 @@ func (d *Daemon) FireWallRuleDelete(ctx context.Context, in *rpc.FireWallRuleDel
 	return &emptypb.Empty{}, nil
 }
 
+// 複数のルールの追加と削除を XDP プログラムから見て一度に反映します。
+// replace の場合は、同じ内容のルールは id とマッチしたパケットの数を引き継いで、DoS protector が追加したルール以外を rules に置き換えます。
+func (d *Daemon) FireWallRuleApply(ctx context.Context, in *rpc.FireWallRuleApplyRequest) (*rpc.FireWallRuleApplyResponse, error) {
+
+	rules := make([]firewall.FWRule, 0, len(in.Rules))
+	for i, r := range in.Rules {
+		rule, err := fireWallRuleFromRPC(r)
+		if err != nil {
+			return nil, fmt.Errorf("rules[%d]: %w", i, err)
+		}
+		rules = append(rules, *rule)
+	}
+
+	deletes := make([]uint32, 0, len(in.DeleteIds))
+	for _, id := range in.DeleteIds {
+		deletes = append(deletes, uint32(id))
+	}
+	if in.Replace && len(deletes) != 0 {
+		return nil, fmt.Errorf("delete ids can not be specified with replace")
+	}
+
+	d.logger.InfoCtx(ctx, "apply fire wall rules", slog.Bool("replace", in.Replace), slog.Int("rules", len(rules)), slog.Int("delete", len(deletes)))
+	var (
+		added, deleted []uint32
+		err            error
+	)
+	if in.Replace {
+		added, deleted, err = d.replaceFireWallRules(rules)
+	} else {
+		added, deleted, err = d.fw.Apply(rules, deletes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := &rpc.FireWallRuleApplyResponse{
+		AddedIds:   make([]int32, 0, len(added)),
+		DeletedIds: make([]int32, 0, len(deleted)),
+	}
+	for _, id := range added {
+		res.AddedIds = append(res.AddedIds, int32(id))
+	}
+	for _, id := range deleted {
+		res.DeletedIds = append(res.DeletedIds, int32(id))
+	}
+	return res, nil
+}
+
 func (d *Daemon) DoSProtectionPolicySet(ctx context.Context, in *rpc.DoSProtectionPolicySetRequest) (*emptypb.Empty, error) {
 	protocol, err := protocols.NewTransportProtocol(uint32(in.Policy.Protocol))
 	if err != nil {
@@ -197,10 +282,55 @@ func (d *Daemon) LoadBalancerSet(ctx context.Context, in *rpc.LoadBalancerSetReq
 		return nil, err
 	}
 
+	serviceId := uint32(in.ServiceId)
+	if serviceId == 0 {
+		// サービスが指定されていない場合、サービスがひとつだけ存在するときはそのサービスに登録します。
+		services := d.lb.GetServices()
+		if len(services) != 1 {
+			err := fmt.Errorf("service id is required")
+			d.logger.ErrorCtx(ctx, "failed to determine the service of the backend", err, slog.Int("services", len(services)))
+			return nil, err
+		}
+		serviceId = services[0].Id
+	}
+
+	// 転送方式が指定されていない場合はサービスの転送方式に従います。
+	forwarding, err := loadbalancer.ForwardingModeFromString(in.Forwarding)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Weight < 0 {
+		err := fmt.Errorf("weight must not be negative: %d", in.Weight)
+		d.logger.ErrorCtx(ctx, "invalid weight of the backend", err, slog.Int("weight", int(in.Weight)))
+		return nil, err
+	}
+
 	backend := &loadbalancer.Backend{
+		ServiceId:   serviceId,
 		Name:        in.Name,
 		Address:     addr,
 		HealthCheck: in.Healthcheck,
+		Weight:      uint32(in.Weight),
+		Forwarding:  forwarding,
+	}
+
+	// MAC アドレスとデバイスが指定されていない場合はロードバランサーで解決します。
+	if in.MacAddr != "" {
+		mac, err := net.ParseMAC(in.MacAddr)
+		if err != nil {
+			d.logger.ErrorCtx(ctx, "failed to parse mac address", err, slog.String("mac", in.MacAddr))
+			return nil, err
+		}
+		backend.MacAddress = mac
+	}
+	if in.Device != "" {
+		iface, err := netlink.LinkByName(in.Device)
+		if err != nil {
+			d.logger.ErrorCtx(ctx, "failed to find device", err, slog.String("device", in.Device))
+			return nil, err
+		}
+		backend.Iface = iface
 	}
 
 	if err := d.lb.Set(backend); err != nil {
@@ -223,13 +353,18 @@ func (d *Daemon) LoadBalancerGet(ctx context.Context, in *rpc.LoadBalancerGetReq
 	for _, b := range backends {
 		d.logger.DebugCtx(ctx, "list backends", slog.Any("backend", b))
 		protoBackends = append(protoBackends, &rpc.LoadBalancerBackend{
-			Id:          int32(b.Id),
-			Name:        b.Name,
-			DevName:     b.Iface.Attrs().Name,
-			IpAddr:      b.Address.String(),
-			MacAddr:     b.MacAddress.String(),
-			Healthcheck: b.HealthCheck,
-			Status:      int32(b.Status),
+			Id:                int32(b.Id),
+			ServiceId:         int32(b.ServiceId),
+			Name:              b.Name,
+			DevName:           b.Iface.Attrs().Name,
+			IpAddr:            b.Address.String(),
+			MacAddr:           b.MacAddress.String(),
+			Healthcheck:       b.HealthCheck,
+			Status:            int32(b.Status),
+			Health:            int32(b.Health),
+			Weight:            int32(b.Weight),
+			ActiveConnections: int32(b.ActiveConnections),
+			Forwarding:        b.Forwarding.String(),
 		})
 	}
 
@@ -249,37 +384,416 @@ func (d *Daemon) LoadBalancerDelete(ctx context.Context, in *rpc.LoadBalancerDel
 
 func (d *Daemon) LoadBalancerDrain(ctx context.Context, in *rpc.LoadBalancerDrainRequest) (*emptypb.Empty, error) {
 
-	if err := d.lb.Drain(uint32(in.Id)); err != nil {
+	if in.Deadline < 0 {
+		return nil, fmt.Errorf("invalid drain deadline: %d", in.Deadline)
+	}
+
+	if err := d.lb.Drain(uint32(in.Id), time.Duration(in.Deadline)*time.Second); err != nil {
 		return nil, err
 	}
 
 	return &emptypb.Empty{}, nil
 }
 
+// drain 中のバックエンドの残りのコネクション数を drain が完了するまで一定間隔で送信します。
+func (d *Daemon) LoadBalancerDrainWait(in *rpc.LoadBalancerDrainWaitRequest, stream rpc.ScmLbApi_LoadBalancerDrainWaitServer) error {
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		status, err := d.lb.DrainStatus(uint32(in.Id))
+		if err != nil {
+			d.logger.ErrorCtx(stream.Context(), "failed to get drain status", err, slog.Int("id", int(in.Id)))
+			return err
+		}
+
+		res := &rpc.LoadBalancerDrainStatus{
+			Id:        int32(status.Id),
+			Remaining: int32(status.Remaining),
+			Completed: status.Completed,
+		}
+		if !status.Deadline.IsZero() {
+			res.Deadline = timestamppb.New(status.Deadline)
+		}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+		if status.Completed {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
 func (d *Daemon) LoadBalancerConntrackGet(ctx context.Context, in *rpc.LoadBalancerConntrackGetRequest) (*rpc.LoadBalancerConntrackGetResponse, error) {
 
-	entries, err := d.lb.GetConntrackEntries()
+	query, err := conntrackQueryFromProto(in)
+	if err != nil {
+		d.logger.ErrorCtx(ctx, "invalid conntrack query", err, slog.Any("request", in))
+		return nil, err
+	}
+
+	result, err := d.lb.QueryConntrackEntries(query)
+	if err != nil {
+		return nil, err
+	}
+
+	protoEntries := make([]*rpc.ConntrackEntry, 0, len(result.Entries))
+
+	for _, e := range result.Entries {
+		protoEntries = append(protoEntries, conntrackEntryToProto(&e))
+	}
+
+	return &rpc.LoadBalancerConntrackGetResponse{
+		Entries:       protoEntries,
+		NextPageToken: result.NextPageToken,
+		Total:         int32(result.Total),
+	}, nil
+}
+
+// リクエストの検索条件を検証して ConntrackQuery に変換します。
+func conntrackQueryFromProto(in *rpc.LoadBalancerConntrackGetRequest) (*loadbalancer.ConntrackQuery, error) {
+
+	if in.BackendId < 0 {
+		return nil, fmt.Errorf("invalid backend id: %d", in.BackendId)
+	}
+	protocol, err := protocols.NewTransportProtocol(uint32(in.Protocol))
+	if err != nil {
+		return nil, err
+	}
+	states := make([]loadbalancer.ConnectionState, 0, len(in.States))
+	for _, s := range in.States {
+		state := loadbalancer.ConnectionState(s)
+		if s < 0 || state > loadbalancer.ConnectionStateClosed {
+			return nil, fmt.Errorf("invalid connection state: %d", s)
+		}
+		states = append(states, state)
+	}
+	var prefix netip.Prefix
+	if in.SrcPrefix != "" {
+		prefix, err = netip.ParsePrefix(in.SrcPrefix)
+		if err != nil {
+			return nil, err
+		}
+		prefix = prefix.Masked()
+	}
+	if in.DstPort < 0 || in.DstPort > 65535 {
+		return nil, fmt.Errorf("invalid destination port: %d", in.DstPort)
+	}
+	if in.MinIdle < 0 {
+		return nil, fmt.Errorf("minimum idle time must not be negative: %d", in.MinIdle)
+	}
+	sortBy, err := loadbalancer.ConntrackSortKeyFromString(in.SortBy)
 	if err != nil {
 		return nil, err
 	}
+	if in.PageSize < 0 {
+		return nil, fmt.Errorf("page size must not be negative: %d", in.PageSize)
+	}
 
-	protoEntries := make([]*rpc.ConntrackEntry, 0, len(entries))
+	return &loadbalancer.ConntrackQuery{
+		Filter: loadbalancer.ConntrackFilter{
+			BackendId: uint32(in.BackendId),
+			Protocol:  protocol,
+			States:    states,
+			SrcPrefix: prefix,
+			DstPort:   uint32(in.DstPort),
+			MinIdle:   time.Duration(in.MinIdle) * time.Second,
+		},
+		SortBy:    sortBy,
+		Reverse:   in.Reverse,
+		PageSize:  uint32(in.PageSize),
+		PageToken: in.PageToken,
+	}, nil
+}
+
+func conntrackEntryToProto(e *loadbalancer.ConntrackEntry) *rpc.ConntrackEntry {
+	return &rpc.ConntrackEntry{
+		SrcAddr:       e.SrcAddr.String(),
+		DstAddr:       e.DstAddr.String(),
+		SrcPort:       int32(e.SrcPort),
+		DstPort:       int32(e.DstPort),
+		Protocol:      int32(e.Protocol),
+		Status:        int32(e.State),
+		Timestamp:     timestamppb.New(e.Timestamp),
+		BackendId:     int32(e.BackendId),
+		Counter:       e.Counter,
+		EgressPackets: e.EgressPackets,
+		IngressBytes:  e.IngressBytes,
+		EgressBytes:   e.EgressBytes,
+		FirstSeen:     timestamppb.New(e.FirstSeen),
+	}
+}
+
+// conntrack のエントリーの作成、状態の変化、削除のイベントをクライアントが切断するまで送信し続けます。
+func (d *Daemon) LoadBalancerConntrackWatch(in *rpc.LoadBalancerConntrackWatchRequest, stream rpc.ScmLbApi_LoadBalancerConntrackWatchServer) error {
+
+	if in.BackendId < 0 {
+		return fmt.Errorf("invalid backend id: %d", in.BackendId)
+	}
+
+	events, cancel := d.lb.WatchConntrack(uint32(in.BackendId))
+	defer cancel()
+
+	d.logger.InfoCtx(stream.Context(), "start watching conntrack", slog.Int("backend id", int(in.BackendId)))
+
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return fmt.Errorf("conntrack events are dropped because the watcher is too slow")
+			}
+			if err := stream.Send(&rpc.ConntrackEvent{
+				Type:       int32(e.Type),
+				Entry:      conntrackEntryToProto(&e.Entry),
+				PrevStatus: int32(e.PrevState),
+				Reason:     e.Reason,
+				Timestamp:  timestamppb.New(e.Timestamp),
+			}); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			d.logger.InfoCtx(stream.Context(), "stop watching conntrack", slog.Int("backend id", int(in.BackendId)))
+			return nil
+		}
+	}
+}
 
+// conntrack のエントリーを削除します。
+// 5-tuple が指定されている場合はそのエントリーを、指定されていない場合は条件に一致するエントリーを削除します。
+// 条件を指定せずにすべてのエントリーを削除する場合は all を指定する必要があります。
+func (d *Daemon) LoadBalancerConntrackDelete(ctx context.Context, in *rpc.LoadBalancerConntrackDeleteRequest) (*rpc.LoadBalancerConntrackDeleteResponse, error) {
+
+	d.logger.DebugCtx(ctx, "delete conntrack entries", slog.Any("request", in))
+
+	if in.Tuple != nil {
+		if in.BackendId != 0 || in.SrcPrefix != "" || in.Protocol != 0 || in.All {
+			return nil, fmt.Errorf("tuple can not be combined with other conditions")
+		}
+		srcAddr, err := netip.ParseAddr(in.Tuple.SrcAddr)
+		if err != nil {
+			return nil, err
+		}
+		dstAddr, err := netip.ParseAddr(in.Tuple.DstAddr)
+		if err != nil {
+			return nil, err
+		}
+		if in.Tuple.SrcPort < 0 || in.Tuple.DstPort < 0 {
+			return nil, fmt.Errorf("invalid port: %d, %d", in.Tuple.SrcPort, in.Tuple.DstPort)
+		}
+		protocol, err := protocols.NewTransportProtocol(uint32(in.Tuple.Protocol))
+		if err != nil {
+			return nil, err
+		}
+		deleted, err := d.lb.DeleteConntrackTuple(loadbalancer.ConntrackTuple{
+			SrcAddr:  srcAddr,
+			DstAddr:  dstAddr,
+			SrcPort:  uint32(in.Tuple.SrcPort),
+			DstPort:  uint32(in.Tuple.DstPort),
+			Protocol: protocol,
+		})
+		if err != nil {
+			d.logger.ErrorCtx(ctx, "failed to delete a conntrack entry", err, slog.Any("tuple", in.Tuple))
+			return nil, err
+		}
+		return &rpc.LoadBalancerConntrackDeleteResponse{Deleted: int32(deleted)}, nil
+	}
+
+	if in.BackendId < 0 {
+		return nil, fmt.Errorf("invalid backend id: %d", in.BackendId)
+	}
+	protocol, err := protocols.NewTransportProtocol(uint32(in.Protocol))
+	if err != nil {
+		return nil, err
+	}
+	var prefix netip.Prefix
+	if in.SrcPrefix != "" {
+		prefix, err = netip.ParsePrefix(in.SrcPrefix)
+		if err != nil {
+			return nil, err
+		}
+		prefix = prefix.Masked()
+	}
+	// 誤ってすべてのエントリーを削除しないように、条件がない場合は明示的な指定を要求します。
+	if in.BackendId == 0 && !prefix.IsValid() && protocol == protocols.TransportProtocolAny && !in.All {
+		return nil, fmt.Errorf("specify a tuple or conditions of entries to delete, or all to delete every entry")
+	}
+
+	deleted, err := d.lb.DeleteConntrackEntries(loadbalancer.ConntrackFilter{
+		BackendId: uint32(in.BackendId),
+		Protocol:  protocol,
+		SrcPrefix: prefix,
+	})
+	if err != nil {
+		d.logger.ErrorCtx(ctx, "failed to delete conntrack entries", err, slog.Any("request", in))
+		return nil, err
+	}
+	return &rpc.LoadBalancerConntrackDeleteResponse{Deleted: int32(deleted)}, nil
+}
+
+// conntrack の GC の設定と、コネクションの状態ごとに GC で削除したエントリーの数を返します。
+func (d *Daemon) LoadBalancerConntrackStat(ctx context.Context, in *rpc.LoadBalancerConntrackStatRequest) (*rpc.LoadBalancerConntrackStatResponse, error) {
+
+	config, evictions := d.lb.GetConntrackEvictions()
+
+	counter := func(protocol protocols.TransportProtocol, state loadbalancer.ConnectionState, timeout time.Duration, evicted uint64) *rpc.ConntrackEvictionCounter {
+		return &rpc.ConntrackEvictionCounter{
+			Protocol: int32(protocol),
+			Status:   int32(state),
+			Timeout:  int64(timeout / time.Second),
+			Evicted:  evicted,
+		}
+	}
+
+	counters := make([]*rpc.ConntrackEvictionCounter, 0, len(loadbalancer.TcpConnectionStates)+1)
+	// Closed のエントリーはタイムアウトに関わらず conntrack の同期ごとに削除されるので、タイムアウトは 0 になります。
+	for _, state := range loadbalancer.TcpConnectionStates {
+		counters = append(counters, counter(protocols.TransportProtocolTcp, state, config.TcpTimeout(state), evictions.Tcp[state]))
+	}
+	counters = append(counters, counter(protocols.TransportProtocolUdp, loadbalancer.ConnectionStateNotTcp, config.Udp, evictions.Udp))
+
+	return &rpc.LoadBalancerConntrackStatResponse{
+		GcEnabled: config.Enabled,
+		Counters:  counters,
+	}, nil
+}
+
+func (d *Daemon) ServiceSet(ctx context.Context, in *rpc.ServiceSetRequest) (*emptypb.Empty, error) {
+
+	d.logger.DebugCtx(ctx, "set a load balancer service", slog.Any("service", in.Service))
+
+	if in.Service == nil {
+		return nil, fmt.Errorf("service is required")
+	}
+
+	vip, err := netip.ParseAddr(in.Service.Vip)
+	if err != nil {
+		d.logger.ErrorCtx(ctx, "failed to parse vip", err, slog.String("vip", in.Service.Vip))
+		return nil, err
+	}
+
+	protocol, err := protocols.NewTransportProtocol(uint32(in.Service.Protocol))
+	if err != nil {
+		return nil, err
+	}
+
+	scheduler, err := loadbalancer.SchedulerFromString(in.Service.Scheduler)
+	if err != nil {
+		return nil, err
+	}
+
+	forwarding, err := loadbalancer.ForwardingModeFromString(in.Service.Forwarding)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Service.Port < 0 || in.Service.Port > 65535 {
+		return nil, fmt.Errorf("invalid port: %d", in.Service.Port)
+	}
+
+	service := &loadbalancer.Service{
+		Vip:        vip,
+		Port:       uint32(in.Service.Port),
+		Protocol:   protocol,
+		Scheduler:  scheduler,
+		Forwarding: forwarding,
+	}
+
+	if _, err := d.lb.SetService(service); err != nil {
+		d.logger.ErrorCtx(ctx, "failed to set a service", err, slog.String("service", service.String()))
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (d *Daemon) ServiceGet(ctx context.Context, in *rpc.ServiceGetRequest) (*rpc.ServiceGetResponse, error) {
+
+	services := d.lb.GetServices()
+
+	protoServices := make([]*rpc.Service, 0, len(services))
+	for _, s := range services {
+		protoServices = append(protoServices, &rpc.Service{
+			Id:              int32(s.Id),
+			Vip:             s.Vip.String(),
+			Port:            int32(s.Port),
+			Protocol:        int32(s.Protocol),
+			Scheduler:       s.Scheduler.String(),
+			AffinityTimeout: int64(s.AffinityTimeout / time.Second),
+			Forwarding:      s.Forwarding.String(),
+		})
+	}
+
+	return &rpc.ServiceGetResponse{
+		Services: protoServices,
+	}, nil
+}
+
+func (d *Daemon) ServiceDelete(ctx context.Context, in *rpc.ServiceDeleteRequest) (*emptypb.Empty, error) {
+
+	if err := d.lb.DeleteService(uint32(in.Id)); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (d *Daemon) LoadBalancerAffinitySet(ctx context.Context, in *rpc.LoadBalancerAffinitySetRequest) (*emptypb.Empty, error) {
+
+	d.logger.DebugCtx(ctx, "set session affinity", slog.Int("service id", int(in.ServiceId)), slog.Bool("enabled", in.Enabled), slog.Int64("timeout", in.Timeout))
+
+	// 無効にする場合はタイムアウトを 0 とします。
+	timeout := time.Duration(0)
+	if in.Enabled {
+		if in.Timeout <= 0 {
+			return nil, fmt.Errorf("affinity timeout must be positive: %d", in.Timeout)
+		}
+		timeout = time.Duration(in.Timeout) * time.Second
+	}
+
+	if err := d.lb.SetAffinity(uint32(in.ServiceId), timeout); err != nil {
+		d.logger.ErrorCtx(ctx, "failed to set session affinity", err, slog.Int("service id", int(in.ServiceId)))
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (d *Daemon) LoadBalancerAffinityGet(ctx context.Context, in *rpc.LoadBalancerAffinityGetRequest) (*rpc.LoadBalancerAffinityGetResponse, error) {
+
+	entries, err := d.lb.GetAffinity(uint32(in.ServiceId))
+	if err != nil {
+		return nil, err
+	}
+
+	protoEntries := make([]*rpc.AffinityEntry, 0, len(entries))
 	for _, e := range entries {
-		protoEntries = append(protoEntries, &rpc.ConntrackEntry{
-			SrcAddr:   e.SrcAddr.String(),
-			DstAddr:   e.DstAddr.String(),
-			SrcPort:   int32(e.SrcPort),
-			DstPort:   int32(e.DstPort),
-			Protocol:  int32(e.Protocol),
-			Status:    int32(e.State),
-			Timestamp: timestamppb.New(e.Timestamp),
-			BackendId: int32(e.BackendId),
-			Counter:   e.Counter,
+		protoEntries = append(protoEntries, &rpc.AffinityEntry{
+			ServiceId:  int32(e.ServiceId),
+			ClientAddr: e.ClientAddr.String(),
+			BackendId:  int32(e.BackendId),
+			Idle:       int64(e.Idle / time.Second),
+			Expired:    e.Expired,
 		})
 	}
 
-	return &rpc.LoadBalancerConntrackGetResponse{
+	return &rpc.LoadBalancerAffinityGetResponse{
 		Entries: protoEntries,
 	}, nil
 }
+
+func (d *Daemon) LoadBalancerAffinityFlush(ctx context.Context, in *rpc.LoadBalancerAffinityFlushRequest) (*emptypb.Empty, error) {
+
+	if err := d.lb.FlushAffinity(uint32(in.ServiceId)); err != nil {
+		d.logger.ErrorCtx(ctx, "failed to flush session affinity entries", err, slog.Int("service id", int(in.ServiceId)))
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
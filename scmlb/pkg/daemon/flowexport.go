@@ -0,0 +1,146 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/ipfix"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/loadbalancer"
+	"golang.org/x/exp/slog"
+)
+
+// conntrack のイベントの購読が解除されたときに購読し直すまでの間隔です。
+const flowExportRetryInterval = time.Second
+
+// conntrack のエントリーのフローレコードを IPFIX で送信する設定です。
+type FlowExportConfig struct {
+	// フローレコードを送信するコレクターのアドレス(host:port)です。空の場合は送信しません。
+	Collector string
+	// 長時間続いているフローのフローレコードを送信する間隔です。
+	ActiveTimeout time.Duration
+	// IPFIX のメッセージヘッダーの Observation Domain ID です。
+	ObservationDomainId uint32
+}
+
+// --flow-collector で指定したコレクターに conntrack のエントリーのフローレコードを送信し続けます。
+// 購読が解除された場合は購読し直します。その間に削除されたエントリーのフローレコードは送信されません。
+func (d *Daemon) runFlowExport(ctx context.Context, exporter *ipfix.Exporter) {
+
+	d.logger.InfoCtx(ctx, "start flow export", slog.String("collector", d.flowExportConfig.Collector))
+
+	for {
+		if err := d.exportFlows(ctx, exporter); err != nil {
+			d.logger.ErrorCtx(ctx, "flow export is interrupted", err, slog.String("collector", d.flowExportConfig.Collector))
+		}
+		select {
+		case <-ctx.Done():
+			d.logger.InfoCtx(ctx, "stop flow export", slog.String("collector", d.flowExportConfig.Collector))
+			return
+		case <-time.After(flowExportRetryInterval):
+		}
+	}
+}
+
+// conntrack のエントリーが削除されたときにフローレコードを送信します。
+// また、ActiveTimeout ごとに、それより長く続いていて前回から通信のあったフローのフローレコードを送信します。
+func (d *Daemon) exportFlows(ctx context.Context, exporter *ipfix.Exporter) error {
+
+	events, cancel := d.lb.WatchConntrack(0)
+	defer cancel()
+
+	ticker := time.NewTicker(d.flowExportConfig.ActiveTimeout)
+	defer ticker.Stop()
+
+	lastExport := time.Now()
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return fmt.Errorf("conntrack events are dropped because the flow export is too slow")
+			}
+			// GC では一度に多くのエントリーが削除されるので、溜まっているイベントをまとめて送信します。
+			records := appendFlowRecord(nil, &e)
+		drain:
+			for {
+				select {
+				case e, ok := <-events:
+					if !ok {
+						break drain
+					}
+					records = appendFlowRecord(records, &e)
+				default:
+					break drain
+				}
+			}
+			if err := exporter.Export(records); err != nil {
+				d.logger.ErrorCtx(ctx, "failed to export flow records", err, slog.Int("records", len(records)))
+			}
+		case now := <-ticker.C:
+			records := d.activeFlowRecords(now, lastExport)
+			lastExport = now
+			if err := exporter.Export(records); err != nil {
+				d.logger.ErrorCtx(ctx, "failed to export flow records", err, slog.Int("records", len(records)))
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// 削除されたエントリーのイベントをフローレコードとして追加します。
+// peer から同期されたままこのホストでパケットを処理していないエントリーは送信しません。
+func appendFlowRecord(records []ipfix.FlowRecord, e *loadbalancer.ConntrackEvent) []ipfix.FlowRecord {
+	if e.Type != loadbalancer.ConntrackEventDeleted || e.Entry.Replicated {
+		return records
+	}
+	return append(records, flowRecordFromEvent(e, flowEndReason(e)))
+}
+
+// ActiveTimeout より長く続いていて、since 以降にパケットを処理したエントリーのフローレコードを返します。
+func (d *Daemon) activeFlowRecords(now, since time.Time) []ipfix.FlowRecord {
+	var records []ipfix.FlowRecord
+	for _, e := range d.lb.ConntrackSnapshot() {
+		if now.Sub(e.Entry.FirstSeen) < d.flowExportConfig.ActiveTimeout || e.Entry.Timestamp.Before(since) {
+			continue
+		}
+		records = append(records, flowRecordFromEvent(&e, ipfix.FlowEndReasonActiveTimeout))
+	}
+	return records
+}
+
+// エントリーが削除された理由を IPFIX の flowEndReason に変換します。
+func flowEndReason(e *loadbalancer.ConntrackEvent) ipfix.FlowEndReason {
+	switch e.Reason {
+	case loadbalancer.ConntrackDeleteReasonClosed:
+		return ipfix.FlowEndReasonEndOfFlow
+	case loadbalancer.ConntrackDeleteReasonTimeout:
+		// TimeWait のエントリーは終了処理が完了したコネクションです。
+		if e.Entry.State == loadbalancer.ConnectionStateTimeWait {
+			return ipfix.FlowEndReasonEndOfFlow
+		}
+		return ipfix.FlowEndReasonIdleTimeout
+	case loadbalancer.ConntrackDeleteReasonEvicted:
+		return ipfix.FlowEndReasonLackOfResources
+	default:
+		return ipfix.FlowEndReasonForcedEnd
+	}
+}
+
+func flowRecordFromEvent(e *loadbalancer.ConntrackEvent, reason ipfix.FlowEndReason) ipfix.FlowRecord {
+	return ipfix.FlowRecord{
+		SrcAddr:        e.Entry.SrcAddr,
+		DstAddr:        e.Entry.DstAddr,
+		SrcPort:        uint16(e.Entry.SrcPort),
+		DstPort:        uint16(e.Entry.DstPort),
+		Protocol:       uint8(e.Entry.Protocol),
+		BackendAddr:    e.BackendAddr,
+		Packets:        e.Entry.Counter,
+		Bytes:          e.Entry.IngressBytes,
+		ReversePackets: e.Entry.EgressPackets,
+		ReverseBytes:   e.Entry.EgressBytes,
+		Start:          e.Entry.FirstSeen,
+		End:            e.Entry.Timestamp,
+		EndReason:      reason,
+	}
+}
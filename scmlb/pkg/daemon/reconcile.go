@@ -0,0 +1,398 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/config"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/dosprotector"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/firewall"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/loadbalancer"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/exp/slog"
+)
+
+// SIGHUP を受け取ったときに --config の設定ファイルを読み込み直して差分を適用します。
+// 読み込みに失敗した場合は何も変更しません。
+func (d *Daemon) reloadConfig(ctx context.Context, vip netip.Addr, scheduler loadbalancer.Scheduler) {
+
+	d.logger.InfoCtx(ctx, "reload config", slog.String("path", d.configPath))
+
+	cfg, err := config.Load(d.configPath, vip, scheduler)
+	if err != nil {
+		d.logger.ErrorCtx(ctx, "failed to reload config", err, slog.String("path", d.configPath))
+		return
+	}
+	// XDP プログラムをアタッチし直す必要があるので upstream の変更は再起動するまで反映しません。
+	if cfg.Upstream != "" && cfg.Upstream != d.upstream {
+		d.logger.WarnCtx(ctx, "restart scmlbd to change the upstream interface", slog.String("current", d.upstream), slog.String("config", cfg.Upstream))
+	}
+	if err := d.reconcile(ctx, cfg); err != nil {
+		d.logger.ErrorCtx(ctx, "failed to reconcile to the config", err, slog.String("path", d.configPath))
+	}
+}
+
+// ロードバランサー、fire wall、DoS protector を設定ファイルの内容に合わせます。
+// 差分のみを適用して、変更のないサービス、バックエンド、ルール、ポリシーはそのままにします。
+// 途中でエラーが発生しても残りの差分の適用を続けて、すべてのエラーをまとめて返します。
+func (d *Daemon) reconcile(ctx context.Context, cfg *config.Config) error {
+
+	var errs []error
+	if err := d.reconcileLoadBalancer(ctx, cfg); err != nil {
+		errs = append(errs, err)
+	}
+	// DoS protector が追加した fire wall ルールを区別できるように、ポリシーを先に合わせます。
+	if err := d.reconcileDoSProtector(ctx, cfg); err != nil {
+		errs = append(errs, err)
+	}
+	if err := d.reconcileFirewall(ctx, cfg); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// サービスは VIP、ポート、プロトコルの組で、バックエンドはサービスとアドレスの組で対応付けます。
+// 設定ファイルから削除されたバックエンドは drain してから削除します。
+// 設定ファイルから削除されたサービスは、バックエンドを drain せずに削除してからサービスを削除します。
+func (d *Daemon) reconcileLoadBalancer(ctx context.Context, cfg *config.Config) error {
+
+	backends, err := d.lb.Get()
+	if err != nil {
+		return err
+	}
+	services := make(map[string]loadbalancer.Service)
+	for _, s := range d.lb.GetServices() {
+		services[s.String()] = s
+	}
+
+	var errs []error
+	desired := make(map[string]struct{}, len(cfg.Services))
+	for i := range cfg.Services {
+		s := &cfg.Services[i]
+		desired[s.String()] = struct{}{}
+
+		id, err := d.reconcileService(ctx, s, services)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("service %s: %w", s, err))
+			continue
+		}
+		// サービスの転送方式を変更するとそれに従うバックエンドの転送方式も変わるので、変更後のバックエンドと比較します。
+		current, err := d.lb.Get()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("service %s: %w", s, err))
+			continue
+		}
+		if err := d.reconcileBackends(ctx, id, s, current, cfg); err != nil {
+			errs = append(errs, fmt.Errorf("service %s: %w", s, err))
+		}
+	}
+
+	for key, s := range services {
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		if err := d.deleteService(ctx, &s, backends); err != nil {
+			errs = append(errs, fmt.Errorf("service %s: %w", key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// サービスが存在しない場合は作成して、選択方式、転送方式、セッション維持の設定が異なる場合は更新します。
+func (d *Daemon) reconcileService(ctx context.Context, s *config.Service, services map[string]loadbalancer.Service) (uint32, error) {
+
+	forwarding := s.Forwarding
+	if forwarding == loadbalancer.ForwardingModeUnspecified {
+		forwarding = loadbalancer.ForwardingModeNat
+	}
+
+	current, ok := services[s.String()]
+	id := current.Id
+	if !ok || current.Scheduler != s.Scheduler || current.Forwarding != forwarding {
+		d.logger.InfoCtx(ctx, "reconcile a service", slog.String("service", s.String()), slog.String("scheduler", s.Scheduler.String()), slog.String("forwarding", forwarding.String()))
+		newId, err := d.lb.SetService(&loadbalancer.Service{
+			Vip:        s.Vip,
+			Port:       s.Port,
+			Protocol:   s.Protocol,
+			Scheduler:  s.Scheduler,
+			Forwarding: forwarding,
+		})
+		if err != nil {
+			return 0, err
+		}
+		id = newId
+	}
+
+	if current.AffinityTimeout != s.AffinityTimeout {
+		if err := d.lb.SetAffinity(id, s.AffinityTimeout); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+// サービスのバックエンドを設定ファイルに合わせます。
+// drain 中のバックエンドは drain が完了して削除されるまで登録し直すことができないので、設定ファイルに含まれていても変更しません。
+func (d *Daemon) reconcileBackends(ctx context.Context, serviceId uint32, s *config.Service, backends []loadbalancer.Backend, cfg *config.Config) error {
+
+	current := make(map[netip.Addr]loadbalancer.Backend)
+	for _, b := range backends {
+		if b.ServiceId == serviceId {
+			current[b.Address] = b
+		}
+	}
+
+	serviceForwarding := s.Forwarding
+	if serviceForwarding == loadbalancer.ForwardingModeUnspecified {
+		serviceForwarding = loadbalancer.ForwardingModeNat
+	}
+
+	var errs []error
+	desired := make(map[netip.Addr]struct{}, len(s.Backends))
+	for i := range s.Backends {
+		b := &s.Backends[i]
+		desired[b.Address] = struct{}{}
+
+		cur, ok := current[b.Address]
+		if !ok {
+			if err := d.addBackend(ctx, serviceId, b); err != nil {
+				errs = append(errs, fmt.Errorf("backend %s: %w", b.Address, err))
+			}
+			continue
+		}
+		if cur.Status == loadbalancer.BackenStatusUnavailable {
+			d.logger.WarnCtx(ctx, "backend is being drained. reload the config again after it is deleted", slog.Int("id", int(cur.Id)), slog.String("address", b.Address.String()))
+			continue
+		}
+
+		// Get はバックエンドに実際に適用されている転送方式を返すので、設定ファイルの転送方式も同様に解決して比較します。
+		forwarding := b.Forwarding
+		if forwarding == loadbalancer.ForwardingModeUnspecified {
+			forwarding = serviceForwarding
+		}
+		// 転送方式を変更すると既存のコネクションが切断されるので、drain して削除されたあとに新しい転送方式で登録し直します。
+		if cur.Forwarding != forwarding {
+			d.logger.WarnCtx(ctx, "drain a backend to change the forwarding mode. reload the config again after it is deleted", slog.Int("id", int(cur.Id)), slog.String("address", b.Address.String()), slog.String("current", cur.Forwarding.String()), slog.String("config", forwarding.String()), slog.Duration("deadline", cfg.DrainDeadline))
+			if err := d.lb.Drain(cur.Id, cfg.DrainDeadline); err != nil {
+				errs = append(errs, fmt.Errorf("backend %s: %w", b.Address, err))
+			}
+			continue
+		}
+		if cur.Name == b.Name && cur.HealthCheck == b.HealthCheck && cur.Weight == b.Weight {
+			continue
+		}
+		if err := d.lb.Update(&loadbalancer.Backend{
+			Id:          cur.Id,
+			Name:        b.Name,
+			HealthCheck: b.HealthCheck,
+			Weight:      b.Weight,
+			Forwarding:  b.Forwarding,
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("backend %s: %w", b.Address, err))
+		}
+	}
+
+	for addr, b := range current {
+		if _, ok := desired[addr]; ok || b.Status == loadbalancer.BackenStatusUnavailable {
+			continue
+		}
+		d.logger.InfoCtx(ctx, "drain a backend removed from the config", slog.Int("id", int(b.Id)), slog.String("address", addr.String()), slog.Duration("deadline", cfg.DrainDeadline))
+		if err := d.lb.Drain(b.Id, cfg.DrainDeadline); err != nil {
+			errs = append(errs, fmt.Errorf("backend %s: %w", addr, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (d *Daemon) addBackend(ctx context.Context, serviceId uint32, b *config.Backend) error {
+
+	backend := &loadbalancer.Backend{
+		ServiceId:   serviceId,
+		Name:        b.Name,
+		Address:     b.Address,
+		HealthCheck: b.HealthCheck,
+		Weight:      b.Weight,
+		Forwarding:  b.Forwarding,
+		MacAddress:  b.MacAddress,
+	}
+	if b.Device != "" {
+		iface, err := netlink.LinkByName(b.Device)
+		if err != nil {
+			return err
+		}
+		backend.Iface = iface
+	}
+
+	d.logger.InfoCtx(ctx, "add a backend in the config", slog.Int("service id", int(serviceId)), slog.String("address", b.Address.String()))
+	return d.lb.Set(backend)
+}
+
+// サービスに所属するバックエンドを削除してからサービスを削除します。
+// 一部のバックエンドの削除に失敗しても残りのバックエンドの削除を続けて、すべてのエラーをまとめて返します。
+// バックエンドが残っている場合はサービスを削除しません。
+func (d *Daemon) deleteService(ctx context.Context, s *loadbalancer.Service, backends []loadbalancer.Backend) error {
+
+	d.logger.InfoCtx(ctx, "delete a service removed from the config", slog.Int("id", int(s.Id)), slog.String("service", s.String()))
+
+	var errs []error
+	for _, b := range backends {
+		if b.ServiceId != s.Id {
+			continue
+		}
+		// 削除する前に Unavailable にする必要があります。
+		if err := d.lb.Drain(b.Id, 0); err != nil {
+			errs = append(errs, fmt.Errorf("backend %s: %w", b.Address, err))
+			continue
+		}
+		if err := d.lb.Delete(b.Id); err != nil {
+			// drain 済みのバックエンドは次の再読み込みでは変更されないので、サービスの削除をやり直すために再読み込みが必要であることを知らせます。
+			d.logger.WarnCtx(ctx, "backend is drained but not deleted. reload the config again to delete the service", slog.Int("id", int(b.Id)), slog.String("address", b.Address.String()), slog.String("service", s.String()))
+			errs = append(errs, fmt.Errorf("backend %s: %w", b.Address, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return d.lb.DeleteService(s.Id)
+}
+
+// 同じ内容のポリシーは変更せずに、設定ファイルにないポリシーを削除して、足りないポリシーを追加します。
+func (d *Daemon) reconcileDoSProtector(ctx context.Context, cfg *config.Config) error {
+
+	policies, err := d.dosProtector.Get()
+	if err != nil {
+		return err
+	}
+
+	type policyKey struct {
+		protocol protocols.TransportProtocol
+		typ      protocols.TcpFlag
+		limit    uint64
+	}
+	// 同じ内容のポリシーが複数記述されている場合も数を合わせます。
+	current := make(map[policyKey][]uint32)
+	for _, p := range policies {
+		key := policyKey{protocol: p.Protocol, typ: p.Type, limit: p.Limit}
+		current[key] = append(current[key], p.Id)
+	}
+
+	var missing []dosprotector.Policy
+	for _, p := range cfg.DoSPolicies {
+		key := policyKey{protocol: p.Protocol, typ: p.Type, limit: p.Limit}
+		if ids := current[key]; len(ids) > 0 {
+			current[key] = ids[1:]
+			continue
+		}
+		missing = append(missing, p)
+	}
+
+	var errs []error
+	for _, ids := range current {
+		for _, id := range ids {
+			d.logger.InfoCtx(ctx, "delete a DoS protection policy removed from the config", slog.Int("id", int(id)))
+			if err := d.dosProtector.Delete(id); err != nil {
+				errs = append(errs, fmt.Errorf("DoS protection policy %d: %w", id, err))
+			}
+		}
+	}
+	for i := range missing {
+		p := &missing[i]
+		d.logger.InfoCtx(ctx, "add a DoS protection policy in the config", slog.String("protocol", p.Protocol.String()), slog.String("type", p.Type.String()), slog.Int("limit", int(p.Limit)))
+		if _, err := d.dosProtector.Set(ctx, p); err != nil {
+			errs = append(errs, fmt.Errorf("DoS protection policy %s/%s: %w", p.Protocol, p.Type, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// 同じ内容のルールは変更せずに、設定ファイルにないルールを削除して、足りないルールを追加します。
+// ルールの追加と削除は Apply で XDP プログラムから見て一度に反映します。
+func (d *Daemon) reconcileFirewall(ctx context.Context, cfg *config.Config) error {
+
+	added, deleted, err := d.replaceFireWallRules(cfg.FireWallRules)
+	if err != nil {
+		return fmt.Errorf("fire wall rules: %w", err)
+	}
+	if len(added) > 0 || len(deleted) > 0 {
+		d.logger.InfoCtx(ctx, "applied fire wall rules in the config", slog.Any("added", added), slog.Any("deleted", deleted))
+	}
+	return nil
+}
+
+// DoS protector が追加したルール以外のルールを desired に置き換えて、追加したルールと削除したルールの id を返します。
+// 同時に置き換えると同じ差分を二重に適用してしまうので、差分の計算から適用までを fwReplaceMu で排他します。
+// DoS protector はポリシーのロックを取得したまま FwManager にルールを追加するので、FwManager のロックの中で DoS protection policy を参照せずにここでロックします。
+func (d *Daemon) replaceFireWallRules(desired []firewall.FWRule) ([]uint32, []uint32, error) {
+
+	d.fwReplaceMu.Lock()
+	defer d.fwReplaceMu.Unlock()
+
+	adds, deletes, err := d.diffFireWallRules(desired)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d.fw.Apply(adds, deletes)
+}
+
+// 現在のルールを desired に置き換えるために追加するルールと削除するルールの id を返します。
+// DoS protector が追加したルールは対象外です。
+// DoS protector は追加したルールの id をポリシーに記録するまでポリシーのロックを保持するので、先にルールを取得してからポリシーを取得すれば、
+// 取得したルールのうち DoS protector が追加したルールはすべてポリシーの FwRuleIds に含まれています。
+func (d *Daemon) diffFireWallRules(desired []firewall.FWRule) ([]firewall.FWRule, []uint32, error) {
+
+	rules, err := d.fw.Get()
+	if err != nil {
+		return nil, nil, err
+	}
+	policies, err := d.dosProtector.Get()
+	if err != nil {
+		return nil, nil, err
+	}
+	dosRules := make(map[uint32]struct{})
+	for _, p := range policies {
+		for _, id := range p.FwRuleIds {
+			dosRules[id] = struct{}{}
+		}
+	}
+
+	// 同じ内容のルールが複数記述されている場合も数を合わせます。
+	current := make(map[firewall.FWRule][]uint32)
+	for _, r := range rules {
+		if _, ok := dosRules[r.Id]; ok {
+			continue
+		}
+		key := fireWallRuleKey(r)
+		current[key] = append(current[key], r.Id)
+	}
+
+	var missing []firewall.FWRule
+	for _, r := range desired {
+		key := fireWallRuleKey(r)
+		if ids := current[key]; len(ids) > 0 {
+			current[key] = ids[1:]
+			continue
+		}
+		missing = append(missing, r)
+	}
+
+	var deletes []uint32
+	for _, ids := range current {
+		deletes = append(deletes, ids...)
+	}
+	return missing, deletes, nil
+}
+
+// ルールの内容を比較するためのキーです。id とマッチしたパケットの数は比較しません。
+func fireWallRuleKey(r firewall.FWRule) firewall.FWRule {
+	r.Id = 0
+	r.Count = 0
+	r.Prefix = r.Prefix.Masked()
+	r.DstPrefix = r.DstPrefix.Masked()
+	return r
+}
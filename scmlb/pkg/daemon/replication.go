@@ -0,0 +1,188 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/loadbalancer"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/rpc"
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// peer との接続が切れたときに再接続するまでの間隔です。
+const conntrackSyncRetryInterval = 5 * time.Second
+
+// conntrack のエントリーの変更を --sync-peer で指定した peer の scmlbd に送信し続けます。
+// 接続が切れた場合は再接続して、現在のエントリーを送信し直します。
+func (d *Daemon) runConntrackSync(ctx context.Context) {
+
+	d.logger.InfoCtx(ctx, "start conntrack sync", slog.String("peer", d.syncPeer))
+
+	for {
+		if err := d.syncConntrack(ctx); err != nil {
+			d.logger.ErrorCtx(ctx, "conntrack sync is interrupted", err, slog.String("peer", d.syncPeer))
+		}
+		select {
+		case <-ctx.Done():
+			d.logger.InfoCtx(ctx, "stop conntrack sync", slog.String("peer", d.syncPeer))
+			return
+		case <-time.After(conntrackSyncRetryInterval):
+		}
+	}
+}
+
+// peer に接続して、現在のエントリーを送信したあと変更を送信し続けます。
+func (d *Daemon) syncConntrack(ctx context.Context) error {
+
+	conn, err := grpc.DialContext(ctx, d.syncPeer, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stream, err := rpc.NewScmLbApiClient(conn).LoadBalancerConntrackSync(ctx)
+	if err != nil {
+		return err
+	}
+
+	// 現在のエントリーを取得する前に購読を開始して、その間の変更を取りこぼさないようにします。
+	// 重複して送信されたエントリーは peer で上書きされるだけです。
+	events, cancel := d.lb.WatchConntrack(0)
+	defer cancel()
+
+	snapshot := d.lb.ConntrackSnapshot()
+	d.logger.InfoCtx(ctx, "send current conntrack entries to the peer", slog.String("peer", d.syncPeer), slog.Int("entries", len(snapshot)))
+	for i := range snapshot {
+		if err := stream.Send(conntrackEventToReplica(&snapshot[i])); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return fmt.Errorf("conntrack events are dropped because the sync is too slow")
+			}
+			// peer から同期されたエントリーの変更を送り返さないようにします。
+			if e.Entry.Replicated || !e.BackendAddr.IsValid() {
+				continue
+			}
+			if err := stream.Send(conntrackEventToReplica(&e)); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func conntrackEventToReplica(e *loadbalancer.ConntrackEvent) *rpc.ConntrackReplica {
+	return &rpc.ConntrackReplica{
+		Type: int32(e.Type),
+		Tuple: &rpc.ConntrackTuple{
+			SrcAddr:  e.Entry.SrcAddr.String(),
+			DstAddr:  e.Entry.DstAddr.String(),
+			SrcPort:  int32(e.Entry.SrcPort),
+			DstPort:  int32(e.Entry.DstPort),
+			Protocol: int32(e.Entry.Protocol),
+		},
+		Status:      int32(e.Entry.State),
+		BackendAddr: e.BackendAddr.String(),
+		SrcMacaddr:  e.Entry.SrcMacAddr.String(),
+	}
+}
+
+func conntrackReplicaFromProto(in *rpc.ConntrackReplica) (*loadbalancer.ConntrackReplica, error) {
+	if in.Tuple == nil {
+		return nil, fmt.Errorf("tuple is required")
+	}
+	srcAddr, err := netip.ParseAddr(in.Tuple.SrcAddr)
+	if err != nil {
+		return nil, err
+	}
+	dstAddr, err := netip.ParseAddr(in.Tuple.DstAddr)
+	if err != nil {
+		return nil, err
+	}
+	if in.Tuple.SrcPort < 0 || in.Tuple.SrcPort > 65535 || in.Tuple.DstPort < 0 || in.Tuple.DstPort > 65535 {
+		return nil, fmt.Errorf("invalid port: %d, %d", in.Tuple.SrcPort, in.Tuple.DstPort)
+	}
+	protocol, err := protocols.NewTransportProtocol(uint32(in.Tuple.Protocol))
+	if err != nil {
+		return nil, err
+	}
+	r := &loadbalancer.ConntrackReplica{
+		Type: loadbalancer.ConntrackEventType(in.Type),
+		Tuple: loadbalancer.ConntrackTuple{
+			SrcAddr:  srcAddr,
+			DstAddr:  dstAddr,
+			SrcPort:  uint32(in.Tuple.SrcPort),
+			DstPort:  uint32(in.Tuple.DstPort),
+			Protocol: protocol,
+		},
+		State: loadbalancer.ConnectionState(in.Status),
+	}
+	// 削除の場合はバックエンドと MAC アドレスは利用しません。
+	if r.Type == loadbalancer.ConntrackEventDeleted {
+		return r, nil
+	}
+	r.BackendAddr, err = netip.ParseAddr(in.BackendAddr)
+	if err != nil {
+		return nil, err
+	}
+	r.SrcMacAddr, err = net.ParseMAC(in.SrcMacaddr)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// peer の scmlbd から conntrack のエントリーの変更を受け取って、conntrack マップに反映します。
+// 反映できない変更があってもログを出力して同期を続けます。
+func (d *Daemon) LoadBalancerConntrackSync(stream rpc.ScmLbApi_LoadBalancerConntrackSyncServer) error {
+
+	// API サーバーはロードバランサーのセットアップより先に起動するので、peer が先に接続してきた場合は拒否して再接続を待ちます。
+	if d.lb == nil {
+		return fmt.Errorf("load balancer is not ready")
+	}
+
+	d.logger.InfoCtx(stream.Context(), "start receiving conntrack entries from the peer")
+
+	d.lb.BeginReplication()
+	defer d.lb.EndReplication()
+
+	var applied, failed uint64
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			d.logger.InfoCtx(stream.Context(), "finish receiving conntrack entries from the peer", slog.Uint64("applied", applied), slog.Uint64("failed", failed))
+			return stream.SendAndClose(&rpc.LoadBalancerConntrackSyncResponse{
+				Applied: applied,
+				Failed:  failed,
+			})
+		}
+		if err != nil {
+			d.logger.ErrorCtx(stream.Context(), "conntrack sync from the peer is interrupted", err)
+			return err
+		}
+
+		r, err := conntrackReplicaFromProto(in)
+		if err == nil {
+			err = d.lb.ApplyConntrackReplica(r)
+		}
+		if err != nil {
+			failed += 1
+			d.logger.ErrorCtx(stream.Context(), "failed to apply a conntrack entry from the peer", err, slog.Any("replica", in))
+			continue
+		}
+		applied += 1
+	}
+}
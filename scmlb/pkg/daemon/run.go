@@ -7,16 +7,19 @@ import (
 	"net/netip"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
-	"time"
 
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/config"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/counter"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/dosprotector"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/firewall"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/ipfix"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/loadbalancer"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/loader"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/logger"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/rpc"
 	"golang.org/x/exp/slog"
 	"google.golang.org/grpc"
@@ -29,15 +32,27 @@ type Daemon struct {
 	apiPort   int32
 	apiServer *grpc.Server
 	upstream  string
+	// bpf マップと link をピン留めして、再起動時に前回の状態を引き継ぐかどうかを表します。
+	persist bool
+	// ロード時に変更するマップの大きさと種類です。
+	mapConfig loader.MapConfig
+	// conntrack のエントリーの変更を送信する peer の scmlbd の API のアドレスです。空の場合は送信しません。
+	syncPeer string
+	// conntrack のエントリーのフローレコードを IPFIX で送信する設定です。
+	flowExportConfig FlowExportConfig
+	// サービス、バックエンド、fire wall ルール、DoS protection policy を記述した設定ファイルのパスです。空の場合は利用しません。
+	configPath string
 	rpc.UnimplementedScmLbApiServer
 
 	counter      *counter.Counter
 	fw           *firewall.FwManager
 	dosProtector *dosprotector.DoSProtector
 	lb           *loadbalancer.LbBackendManager
+	// fire wall のルールを置き換えるときに、現在のルールとの差分の計算と適用の間に他の置き換えが割り込まないようにするロックです。
+	fwReplaceMu *sync.Mutex
 }
 
-func New(apiAddr string, apiPort int32, upstreamInterface string) (*Daemon, error) {
+func New(apiAddr string, apiPort int32, upstreamInterface string, persist bool, mapConfig loader.MapConfig, syncPeer string, flowExportConfig FlowExportConfig, configPath string) (*Daemon, error) {
 	out, err := logger.Output(constants.LogOutput)
 	if err != nil {
 		return nil, err
@@ -45,15 +60,21 @@ func New(apiAddr string, apiPort int32, upstreamInterface string) (*Daemon, erro
 	logger := logger.SetUpLogger(constants.LogFormat, out, logger.ValidateLevel(constants.LogLevel))
 
 	daemon := &Daemon{
-		logger:    logger,
-		apiPort:   apiPort,
-		apiServer: grpc.NewServer(),
-		upstream:  upstreamInterface,
+		logger:           logger,
+		apiPort:          apiPort,
+		apiServer:        grpc.NewServer(),
+		upstream:         upstreamInterface,
+		persist:          persist,
+		mapConfig:        mapConfig,
+		syncPeer:         syncPeer,
+		flowExportConfig: flowExportConfig,
+		configPath:       configPath,
+		fwReplaceMu:      &sync.Mutex{},
 	}
 	return daemon, nil
 }
 
-func (d *Daemon) Run(vip netip.Addr, gc bool, gcTime time.Duration) error {
+func (d *Daemon) Run(vip netip.Addr, scheduler loadbalancer.Scheduler, gcConfig loadbalancer.GCConfig, hcConfig loadbalancer.HealthCheckConfig) error {
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -67,6 +88,27 @@ func (d *Daemon) Run(vip netip.Addr, gc bool, gcTime time.Duration) error {
 		syscall.SIGINT,
 		syscall.SIGTERM,
 	)
+	// SIGHUP で設定ファイルを読み込み直します
+	reloadCh := make(chan os.Signal, 1)
+	signal.Notify(reloadCh, syscall.SIGHUP)
+
+	// --config が指定されている場合は設定ファイルを読み込みます
+	// デフォルトのサービスは設定ファイルの内容に合わせるときに作成するので、ここでは作成しません
+	var cfg *config.Config
+	if d.configPath != "" {
+		c, err := config.Load(d.configPath, vip, scheduler)
+		if err != nil {
+			return err
+		}
+		if c.Upstream != "" {
+			d.upstream = c.Upstream
+		}
+		cfg = c
+	}
+	defaultVip := vip
+	if cfg != nil {
+		defaultVip = netip.Addr{}
+	}
 
 	// CLI クライアント(scmlb コマンド) と通信するための gRPC サーバーを起動しています
 	rpc.RegisterScmLbApiServer(d.apiServer, d)
@@ -87,7 +129,9 @@ func (d *Daemon) Run(vip netip.Addr, gc bool, gcTime time.Duration) error {
 	d.logger.InfoCtx(ctx, "load XDP components")
 
 	// bpf/xdp.c に定義された XDP プログラムをロードしています
-	loader, err := loader.Load(*d.logger)
+	// --persist が指定されている場合は前回ピン留めしたマップを再利用します
+	// マップの大きさと conntrack マップの種類はフラグの指定に従って変更します
+	loader, err := loader.Load(*d.logger, d.persist, d.mapConfig)
 	if err != nil {
 		return err
 	}
@@ -113,22 +157,41 @@ func (d *Daemon) Run(vip netip.Addr, gc bool, gcTime time.Duration) error {
 	}
 
 	d.logger.InfoCtx(ctx, "setup Load balancer")
-	if err := d.setupLoadBalancer(ctx, loader, vip, gc, gcTime); err != nil {
+	if err := d.setupLoadBalancer(ctx, loader, defaultVip, scheduler, gcConfig, hcConfig); err != nil {
 		return err
 	}
 
 	// ロードしたプログラムを NIC にアタッチします
+	// --persist が指定されていて前回アタッチした link が残っている場合は、デタッチせずにプログラムを置き換えます
 	if err := loader.Attach(d.upstream); err != nil {
 		return err
 	}
 
+	// --sync-peer が指定されている場合は conntrack のエントリーの変更を peer の scmlbd に送信します
+	if d.syncPeer != "" {
+		go d.runConntrackSync(ctx)
+	}
+
+	// --flow-collector が指定されている場合は conntrack のエントリーのフローレコードを IPFIX でコレクターに送信します
+	if d.flowExportConfig.Collector != "" {
+		exporter, err := ipfix.New(d.flowExportConfig.Collector, d.flowExportConfig.ObservationDomainId)
+		if err != nil {
+			return err
+		}
+		defer exporter.Close()
+		go d.runFlowExport(ctx, exporter)
+	}
+
 	// defer に登録された関数は登録元の関数(この場合は Run() )から抜けるときに実行されます。
 	// ここではロードした XDP プログラムと eBPF マップを削除して、アタッチしている NIC からもプログラムを外しています。
 	defer func() {
 
 		d.logger.InfoCtx(ctx, "stopping scmlbd")
 		// ロードバランサーのバックエンドにアタッチしている XDP プログラムもでタッチします。
-		if err := d.lb.DeleteAll(); err != nil {
+		// --persist が指定されている場合は次回の起動時に引き継ぐので、バックエンドを削除せずに XDP プログラムもアタッチしたままにします。
+		if d.persist {
+			d.logger.InfoCtx(ctx, "keep XDP programs and maps pinned")
+		} else if err := d.lb.DeleteAll(); err != nil {
 			d.logger.ErrorCtx(ctx, "failed to finalize XDP from backends", err)
 		}
 
@@ -138,10 +201,29 @@ func (d *Daemon) Run(vip netip.Addr, gc bool, gcTime time.Duration) error {
 		d.logger.InfoCtx(ctx, "finished stopping lb")
 	}()
 
+	// ロードバランサー、fire wall、DoS protector を設定ファイルの内容に合わせます
+	// --persist で復元した状態とも差分のみを適用します
+	if cfg != nil {
+		d.logger.InfoCtx(ctx, "reconcile to the config", slog.String("path", d.configPath))
+		if err := d.reconcile(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
 	// Ctrl-C を待ち受けています
 	// シグナルを受け取ると即時リターンしますが、その前に defer で登録した関数が実行されます
-	<-signalCh
-	return nil
+	for {
+		select {
+		case <-signalCh:
+			return nil
+		case <-reloadCh:
+			if d.configPath == "" {
+				d.logger.WarnCtx(ctx, "ignore SIGHUP because --config is not specified")
+				continue
+			}
+			d.reloadConfig(ctx, vip, scheduler)
+		}
+	}
 }
 
 // ingress_counter 機能のセットアップを行います
@@ -183,10 +265,24 @@ func (d *Daemon) setupFirewall(l *loader.Loader) error {
 	if !ok {
 		return fmt.Errorf("failed to find adv_rules")
 	}
+	arl, ok := l.Maps[loader.MAP_NAME_ADV_RULE_LISTS]
+	if !ok {
+		return fmt.Errorf("failed to find adv_rule_lists")
+	}
+	fg, ok := l.Maps[loader.MAP_NAME_FW_GENERATION]
+	if !ok {
+		return fmt.Errorf("failed to find fw_generation")
+	}
 
-	f := firewall.NewManager(d.logger, p, rm, dm, arm, ar)
+	f := firewall.NewManager(d.logger, p, rm, dm, arm, ar, arl, fg)
 	d.fw = f
 
+	if d.persist {
+		if err := d.fw.Restore(); err != nil {
+			return fmt.Errorf("failed to restore fire wall rules: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -197,13 +293,29 @@ func (d *Daemon) setupDoSProtector(ctx context.Context, l *loader.Loader, fwMana
 		return fmt.Errorf("failed to find policies map")
 	}
 
-	p, err := dosprotector.New(fwManager, counter)
+	policies, ok := l.Maps[loader.MAP_NAME_DOSP_POLICIES]
+	if !ok {
+		return fmt.Errorf("failed to find dosp_policies map")
+	}
+	fwRules, ok := l.Maps[loader.MAP_NAME_DOSP_FW_RULES]
+	if !ok {
+		return fmt.Errorf("failed to find dosp_fw_rules map")
+	}
+
+	p, err := dosprotector.New(fwManager, counter, policies, fwRules)
 	if err != nil {
 		return err
 	}
 
 	d.dosProtector = p
 
+	// ループを開始する前に復元しないと、再起動前に計測されたパケット数で制限を超えたと判断されてしまいます。
+	if d.persist {
+		if err := d.dosProtector.Restore(); err != nil {
+			return fmt.Errorf("failed to restore DoS protection policies: %w", err)
+		}
+	}
+
 	d.logger.InfoCtx(ctx, "start DoS protector loop")
 	go func() {
 		if err := d.dosProtector.Run(ctx); err != nil {
@@ -214,7 +326,7 @@ func (d *Daemon) setupDoSProtector(ctx context.Context, l *loader.Loader, fwMana
 	return nil
 }
 
-func (d *Daemon) setupLoadBalancer(ctx context.Context, l *loader.Loader, vip netip.Addr, gc bool, gcTime time.Duration) error {
+func (d *Daemon) setupLoadBalancer(ctx context.Context, l *loader.Loader, vip netip.Addr, scheduler loadbalancer.Scheduler, gcConfig loadbalancer.GCConfig, hcConfig loadbalancer.HealthCheckConfig) error {
 	entry, ok := l.Programs[loader.PROG_NAME_ENTRYPOINY]
 	if !ok {
 		return fmt.Errorf("failed to find entrypoint program")
@@ -245,14 +357,54 @@ func (d *Daemon) setupLoadBalancer(ctx context.Context, l *loader.Loader, vip ne
 	if !ok {
 		return fmt.Errorf("failed to find rr_table map")
 	}
+	maglevTableMap, ok := l.Maps[loader.MAP_NAME_MAGLEV_TABLE]
+	if !ok {
+		return fmt.Errorf("failed to find maglev_table map")
+	}
+	servicesMap, ok := l.Maps[loader.MAP_NAME_SERVICES]
+	if !ok {
+		return fmt.Errorf("failed to find services map")
+	}
+	reverseServiceMap, ok := l.Maps[loader.MAP_NAME_REVERSE_SERVICE]
+	if !ok {
+		return fmt.Errorf("failed to find reverse_service map")
+	}
+	affinityMap, ok := l.Maps[loader.MAP_NAME_AFFINITY]
+	if !ok {
+		return fmt.Errorf("failed to find affinity map")
+	}
+	backendMetaMap, ok := l.Maps[loader.MAP_NAME_BACKEND_META]
+	if !ok {
+		return fmt.Errorf("failed to find backend_meta map")
+	}
 
-	lbm, err := loadbalancer.New(vip, d.upstream, entry, redirectMap, backendInfoMap, backendIfindexMap, upstreamMap, conntrack, rrTableMap, gc, gcTime)
+	lbm, err := loadbalancer.New(d.upstream, entry, redirectMap, backendInfoMap, backendIfindexMap, upstreamMap, conntrack, rrTableMap, maglevTableMap, servicesMap, reverseServiceMap, affinityMap, backendMetaMap, gcConfig, hcConfig, d.persist)
 	if err != nil {
 		return err
 	}
 
 	d.lb = lbm
 
+	if d.persist {
+		if err := d.lb.Restore(); err != nil {
+			return fmt.Errorf("failed to restore load balancer: %w", err)
+		}
+	}
+
+	// --vip が指定されている場合はすべてのポートとプロトコルを対象とするサービスを作成します。
+	if vip.IsValid() {
+		id, err := d.lb.SetService(&loadbalancer.Service{
+			Vip:       vip,
+			Port:      0,
+			Protocol:  protocols.TransportProtocolAny,
+			Scheduler: scheduler,
+		})
+		if err != nil {
+			return err
+		}
+		d.logger.InfoCtx(ctx, "create a default service", slog.Int("id", int(id)), slog.String("vip", vip.String()), slog.String("scheduler", scheduler.String()))
+	}
+
 	d.logger.InfoCtx(ctx, "start Load balancer loop")
 	go func() {
 		if err := d.lb.Run(ctx); err != nil {
@@ -2,6 +2,9 @@ package dosprotector
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -19,13 +22,17 @@ type DoSProtector struct {
 	logger     *slog.Logger
 	mu         *sync.Mutex
 	counterMap *ebpf.Map
-	counter    map[identifier]uint64
-	policies   map[uint32]*Policy
-	nextId     uint32
-	fwManager  *firewall.FwManager
+	// ポリシーと、ポリシーが追加した fire wall ルールを保存するマップです。
+	// XDP プログラムからは参照せず、scmlbd の再起動時にポリシーを復元するために利用します。
+	policyMap *ebpf.Map
+	fwRuleMap *ebpf.Map
+	counter   map[identifier]uint64
+	policies  map[uint32]*Policy
+	nextId    uint32
+	fwManager *firewall.FwManager
 }
 
-func New(fwManager *firewall.FwManager, counterMap *ebpf.Map) (*DoSProtector, error) {
+func New(fwManager *firewall.FwManager, counterMap, policyMap, fwRuleMap *ebpf.Map) (*DoSProtector, error) {
 	out, err := logger.Output(constants.LogOutput)
 	if err != nil {
 		return nil, err
@@ -37,6 +44,8 @@ func New(fwManager *firewall.FwManager, counterMap *ebpf.Map) (*DoSProtector, er
 		logger:     logger,
 		mu:         &sync.Mutex{},
 		counterMap: counterMap,
+		policyMap:  policyMap,
+		fwRuleMap:  fwRuleMap,
 		counter:    make(map[identifier]uint64),
 		policies:   make(map[uint32]*Policy),
 		nextId:     1,
@@ -81,9 +90,32 @@ func (p PolicyStatus) String() string {
 // dosp_protection_identifier bpf map に対応する構造体です。
 // この構造体を通して bpf プログラムとやり取りします。
 type identifier struct {
-	Address  uint32
+	// IPv4 アドレスは IPv4-mapped IPv6 アドレスとして格納されています。
+	Address  [16]byte
 	Protocol uint8
 	Type     uint8
+	// C の構造体のアラインメントに合わせるためのパディングです。
+	_ [2]uint8
+}
+
+// dosp_policies bpf map の値に対応する構造体です。
+// bpf/include/scmlb.h の dosp_policy 構造体に対応しています。
+type policyInfo struct {
+	Limit    uint64
+	Protocol uint32
+	Type     uint32
+	Status   uint32
+	_        uint32
+}
+
+// dosp_policies bpf map にポリシーを保存します。
+func (d *DoSProtector) savePolicy(policy *Policy) error {
+	return d.policyMap.Update(policy.Id, policyInfo{
+		Limit:    policy.Limit,
+		Protocol: uint32(policy.Protocol),
+		Type:     uint32(policy.Type),
+		Status:   uint32(policy.Status),
+	}, ebpf.UpdateAny)
 }
 
 // ポリシーをセットします
@@ -99,6 +131,9 @@ func (d *DoSProtector) Set(ctx context.Context, policy *Policy) (uint32, error)
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
+	if err := d.savePolicy(policy); err != nil {
+		return 0, err
+	}
 	d.policies[policy.Id] = policy
 
 	return policy.Id, nil
@@ -147,8 +182,14 @@ func (d *DoSProtector) Delete(id uint32) error {
 		if err := d.fwManager.Delete(fwId); err != nil {
 			return err
 		}
+		if err := d.fwRuleMap.Delete(fwId); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			return err
+		}
 	}
 
+	if err := d.policyMap.Delete(id); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+		return err
+	}
 	delete(d.policies, id)
 
 	return nil
@@ -201,12 +242,8 @@ func (d *DoSProtector) Run(ctx context.Context) error {
 						if policy.Limit < value-prevCount {
 							// 制限を超えていたときは fire wall にルールを追加してパケットをドロップするようにする.
 							d.logger.InfoCtx(ctx, "exceeded the limit. trigger DoS protection", slog.Any("policy", policy), slog.Uint64("received count", value))
-							addr, err := protocols.IpAddrFromLe(key.Address)
-							if err != nil {
-								d.logger.ErrorCtx(ctx, "failed to parse IP address", err, slog.Uint64("address", uint64(key.Address)))
-								continue
-							}
-							prefix, err := addr.Prefix(32)
+							addr := protocols.IpAddrFrom16(key.Address)
+							prefix, err := addr.Prefix(addr.BitLen())
 							if err != nil {
 								d.logger.ErrorCtx(ctx, "failed to get prefix", err, slog.String("address", addr.String()))
 								continue
@@ -229,6 +266,13 @@ func (d *DoSProtector) Run(ctx context.Context) error {
 							// パケットの制限をかけたのでルール id を記録して、ポリシーのステータスを変更します。
 							policy.FwRuleIds = append(policy.FwRuleIds, id)
 							policy.Status = PolicyStatusTriggered
+							// 再起動後もポリシーが追加したルールを削除できるように記録しておきます。
+							if err := d.fwRuleMap.Update(id, policy.Id, ebpf.UpdateAny); err != nil {
+								d.logger.ErrorCtx(ctx, "failed to record a fire wall rule of the policy", err, slog.Any("policy", policy), slog.Int("rule id", int(id)))
+							}
+							if err := d.savePolicy(policy); err != nil {
+								d.logger.ErrorCtx(ctx, "failed to save the policy", err, slog.Any("policy", policy))
+							}
 						}
 					}
 					policy.mu.Unlock()
@@ -245,3 +289,69 @@ func (d *DoSProtector) Run(ctx context.Context) error {
 		}
 	}
 }
+
+// ピン留めされた bpf マップからポリシーと、ポリシーが追加した fire wall ルールの id を復元します。
+// fire wall のルールを先に復元しておく必要があります。
+func (d *DoSProtector) Restore() error {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var (
+		id   uint32
+		info policyInfo
+	)
+	policies := d.policyMap.Iterate()
+	for policies.Next(&id, &info) {
+		protocol, err := protocols.NewTransportProtocol(info.Protocol)
+		if err != nil {
+			return err
+		}
+		policy := &Policy{
+			mu:        &sync.Mutex{},
+			Id:        id,
+			Protocol:  protocol,
+			Type:      protocols.TcpFlag(info.Type),
+			Limit:     info.Limit,
+			Status:    PolicyStatus(info.Status),
+			FwRuleIds: make([]uint32, 0),
+		}
+		d.logger.Info("restore a DoS protection policy", slog.Any("policy", policy))
+		d.policies[id] = policy
+		if id >= d.nextId {
+			d.nextId = id + 1
+		}
+	}
+	if err := policies.Err(); err != nil {
+		return err
+	}
+
+	var fwId, policyId uint32
+	rules := d.fwRuleMap.Iterate()
+	for rules.Next(&fwId, &policyId) {
+		policy, ok := d.policies[policyId]
+		if !ok {
+			return fmt.Errorf("policy %d of fire wall rule %d is not found", policyId, fwId)
+		}
+		policy.FwRuleIds = append(policy.FwRuleIds, fwId)
+	}
+	if err := rules.Err(); err != nil {
+		return err
+	}
+	for _, policy := range d.policies {
+		sort.Slice(policy.FwRuleIds, func(i, j int) bool {
+			return policy.FwRuleIds[i] < policy.FwRuleIds[j]
+		})
+	}
+
+	// 再起動前までに計測されたパケット数を前回の値として記録しておかないと、最初の計測で制限を超えたと判断されてしまいます。
+	var (
+		key   identifier
+		value uint64
+	)
+	entries := d.counterMap.Iterate()
+	for entries.Next(&key, &value) {
+		d.counter[key] = value
+	}
+	return entries.Err()
+}
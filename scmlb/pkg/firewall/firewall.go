@@ -2,7 +2,10 @@ package firewall
 
 import (
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"net/netip"
+	"sort"
 	"sync"
 
 	"github.com/cilium/ebpf"
@@ -19,13 +22,62 @@ type FWRule struct {
 	FromDstPort uint32
 	ToDstPort   uint32
 	Protocol    protocols.TransportProtocol
-	Count       uint64
+	// 値が小さいルールから評価して、最初にマッチしたルールの Action に従います。
+	Priority uint32
+	Action   Action
+	// 宛先アドレスのプレフィックスです。ゼロ値の場合はすべての宛先が対象です。
+	DstPrefix netip.Prefix
+	// ルールにマッチしたパケットの数です。
+	Count uint64
+}
+
+// fire wall のルールにマッチしたパケットの扱いです。
+// bpf/include/scmlb.h の enum FireWallAction に対応しています。
+type Action uint32
+
+const (
+	// パケットをドロップします。
+	ActionDeny = Action(0)
+	// 以降のルールを評価せずにパケットを通過させます。
+	ActionAllow = Action(1)
+)
+
+// 空文字列の場合は ActionDeny を返します。
+func ActionFromString(s string) (Action, error) {
+	switch s {
+	case "", "deny":
+		return ActionDeny, nil
+	case "allow":
+		return ActionAllow, nil
+	default:
+		return Action(255), fmt.Errorf("unknown fire wall action: %s", s)
+	}
+}
+
+func (a Action) String() string {
+	switch a {
+	case ActionDeny:
+		return "deny"
+	case ActionAllow:
+		return "allow"
+	default:
+		return fmt.Sprintf("unknown(%d)", a)
+	}
 }
 
 // この構造体は bpf/include/scmlb.h の同名の構造体に対応しています。
+// IPv4 のプレフィックスは IPv4-mapped IPv6 アドレスとして格納します。
 type network struct {
 	prefixLen uint32
-	address   uint32
+	address   [16]byte
+}
+
+// この構造体は bpf/include/scmlb.h の fw_matcher_key 構造体に対応しています。
+// 世代が一致するエントリーだけがマッチするように、prefixLen には generation の 32 ビットを含めます。
+type matcherKey struct {
+	prefixLen  uint32
+	generation uint32
+	address    [16]byte
 }
 
 // この構造体は bpf/include/scmlb.h の同名の構造体に対応しています。
@@ -36,6 +88,9 @@ type fwRule struct {
 	fromDstPort uint16
 	toDstPort   uint16
 	protocol    uint32
+	priority    uint32
+	action      uint32
+	dst         network
 }
 
 type FwManager struct {
@@ -48,9 +103,16 @@ type FwManager struct {
 	dropCounter    *ebpf.Map
 	advRuleMatcher *ebpf.Map
 	advRuleMap     *ebpf.Map
+	advRuleLists   *ebpf.Map
+	fwGeneration   *ebpf.Map
+	// XDP プログラムが参照している rule matcher の世代です。
+	generation uint32
+	// rule matcher のプレフィックスごとのルール id の列を格納している adv_rule_lists の領域です。
+	lists     map[netip.Prefix]ruleList
+	allocator *listAllocator
 }
 
-func NewManager(logger *slog.Logger, p *ebpf.Program, ruleMap, dropCounter, advRuleMatcher, advRuleMap *ebpf.Map) *FwManager {
+func NewManager(logger *slog.Logger, p *ebpf.Program, ruleMap, dropCounter, advRuleMatcher, advRuleMap, advRuleLists, fwGeneration *ebpf.Map) *FwManager {
 	return &FwManager{
 		logger:         logger,
 		mu:             &sync.Mutex{},
@@ -60,60 +122,86 @@ func NewManager(logger *slog.Logger, p *ebpf.Program, ruleMap, dropCounter, advR
 		dropCounter:    dropCounter,
 		advRuleMatcher: advRuleMatcher,
 		advRuleMap:     advRuleMap,
+		advRuleLists:   advRuleLists,
+		fwGeneration:   fwGeneration,
+		lists:          make(map[netip.Prefix]ruleList),
+		allocator:      newListAllocator(advRuleLists.MaxEntries()),
 	}
 }
 
 func (f *FwManager) Set(rule *FWRule) (uint32, error) {
 
-	rule.Id = f.nextId
-	f.nextId += 1
-
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	// ここで eBPF マップにルールを追加します
-
-	f.logger.Info("set a fire wall rule", slog.String("network", rule.Prefix.String()), slog.String("protocol", rule.Protocol.String()), slog.Any("from_dst", rule.FromDstPort), slog.Any("to_dst", rule.ToDstPort))
-	nw, r := rule.splitKeyValue()
-	f.logger.Debug("splitted rule", slog.Any("from_dst", r.fromDstPort), slog.Any("to_dst", r.toDstPort))
-	if err := f.ruleMap.Update(nw, r, ebpf.UpdateAny); err != nil {
-		f.logger.Error("failed to update rule map", err, slog.Int("id", int(r.id)), slog.String("network", rule.Prefix.String()))
+	if err := rule.validate(); err != nil {
 		return 0, err
 	}
 
-	// port や protocol を考慮した fire wall のためのコード
-	var ids [constants.ADVANCED_FIRE_WALL_MAX_SIZE_PER_NETWORK]uint16
+	rule.Id = f.nextId
 
-	if err := f.advRuleMatcher.Lookup(nw, &ids); err != nil {
-		f.logger.Error("failed to lookup rule matcher", err, slog.Any("rule", rule))
-	}
+	// ここで eBPF マップにルールを追加します
 
-	f.logger.Debug("ids", slog.Any("ids", ids))
+	f.logger.Info("set a fire wall rule", slog.String("network", rule.Prefix.String()), slog.String("protocol", rule.Protocol.String()), slog.Any("from_dst", rule.FromDstPort), slog.Any("to_dst", rule.ToDstPort), slog.String("action", rule.Action.String()), slog.Int("priority", int(rule.Priority)))
 
-	for i := 0; i < constants.ADVANCED_FIRE_WALL_MAX_SIZE_PER_NETWORK; i++ {
-		if ids[i] == 0 {
-			ids[i] = uint16(rule.Id)
-			break
-		}
+	// ルールを追加したあとの rule matcher の値と adv_rule_lists の領域を先に計算して、
+	// ルールの数や領域が上限を超える場合はマップを変更せずにエラーを返します。
+	f.rules[rule.Id] = *rule
+	matchers, err := f.buildRuleMatchers(rule.matcherPrefix())
+	if err != nil {
+		delete(f.rules, rule.Id)
+		return 0, err
+	}
+	lists, err := f.allocateRuleLists(matchers)
+	if err != nil {
+		delete(f.rules, rule.Id)
+		return 0, err
 	}
 
-	f.logger.Debug("update rule matcher", slog.String("network", rule.Prefix.String()), slog.Any("ids", ids))
-	if err := f.advRuleMatcher.Update(nw, &ids, ebpf.UpdateAny); err != nil {
-		f.logger.Error("failed to update rule matcher", err, slog.Any("rule", rule), slog.Any("ids", ids))
+	// 同じプレフィックスのルールが既にある場合は上書きするので、失敗したときに元に戻せるように前の値を取っておきます。
+	nw, r := rule.splitKeyValue()
+	var prev fwRule
+	hasPrev := f.ruleMap.Lookup(nw, &prev) == nil
+	f.logger.Debug("splitted rule", slog.Any("from_dst", r.fromDstPort), slog.Any("to_dst", r.toDstPort))
+	if err := f.ruleMap.Update(nw, r, ebpf.UpdateAny); err != nil {
+		f.logger.Error("failed to update rule map", err, slog.Int("id", int(r.id)), slog.String("network", rule.Prefix.String()))
+		delete(f.rules, rule.Id)
+		f.releaseRuleLists(lists)
 		return 0, err
 	}
 
+	// port や protocol を考慮した fire wall のためのコード
+	// rule matcher から参照される前にルールの本体を登録します。
 	f.logger.Debug("update rule", slog.String("network", rule.Prefix.String()), slog.Any("rule", r))
 	if err := f.advRuleMap.Update(rule.Id, r, ebpf.UpdateAny); err != nil {
 		f.logger.Error("failed to update advanced rule map", err, slog.Int("id", int(r.id)), slog.String("network", rule.Prefix.String()))
+		delete(f.rules, rule.Id)
+		f.releaseRuleLists(lists)
+		f.restoreRuleMap(nw, prev, hasPrev)
 		return 0, err
 	}
 
-	f.rules[rule.Id] = *rule
+	if err := f.updateRuleMatchers(matchers, lists); err != nil {
+		// 一部のプレフィックスの rule matcher は切り替わっているので、ルールを除いた列に戻してからルールの本体を削除します。
+		delete(f.rules, rule.Id)
+		f.revertRuleMatchers(rule)
+		if err := f.advRuleMap.Delete(rule.Id); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			f.logger.Error("failed to delete advanced rule map", err, slog.Int("id", int(rule.Id)), slog.String("network", rule.Prefix.String()))
+		}
+		// 切り替わっている間にマッチしたパケットの数が残らないように、id を使い回す前に削除します。
+		if err := f.dropCounter.Delete(rule.Id); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			f.logger.Error("failed to delete drop counter", err, slog.Int("id", int(rule.Id)))
+		}
+		f.restoreRuleMap(nw, prev, hasPrev)
+		return 0, err
+	}
+	f.nextId += 1
 
 	return rule.Id, nil
 }
 
+// ルールを評価する順に並べて返します。
+// プライオリティの値が小さいルールから評価して、同じプライオリティのルールは先に追加されたルールから評価します。
 func (f *FwManager) Get() ([]FWRule, error) {
 
 	rules := make([]FWRule, 0, len(f.rules))
@@ -135,6 +223,10 @@ func (f *FwManager) Get() ([]FWRule, error) {
 		rules = append(rules, v)
 	}
 
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].evaluatedBefore(&rules[j])
+	})
+
 	return rules, nil
 }
 
@@ -147,10 +239,26 @@ func (f *FwManager) Delete(id uint32) error {
 		return nil
 	}
 
+	// ルールを削除しても rule matcher のルールの数は増えず、列を格納している領域も使い回せるので、ここでエラーになることはありません。
+	delete(f.rules, id)
+	matchers, err := f.buildRuleMatchers(rule.matcherPrefix())
+	if err != nil {
+		f.rules[id] = rule
+		return err
+	}
+	lists, err := f.allocateRuleLists(matchers)
+	if err != nil {
+		f.rules[id] = rule
+		return err
+	}
+
 	nw, _ := rule.splitKeyValue()
 
 	// ここで eBPF マップから指定された id のルールを削除します
-	if err := f.ruleMap.Delete(nw.toUint64()); err != nil {
+	// 同じプレフィックスのルールが複数ある場合は既に削除されていることがあります。
+	if err := f.ruleMap.Delete(nw); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+		f.rules[id] = rule
+		f.releaseRuleLists(lists)
 		return err
 	}
 	if err := f.dropCounter.Delete(id); err != nil {
@@ -158,48 +266,394 @@ func (f *FwManager) Delete(id uint32) error {
 	}
 
 	// port と protocol を考慮した fire wall のためのコード
-	var ids [constants.ADVANCED_FIRE_WALL_MAX_SIZE_PER_NETWORK]uint16
+	// ルールの本体を削除する前に rule matcher から取り除きます。
+	if err := f.updateRuleMatchers(matchers, lists); err != nil {
+		return err
+	}
+
+	f.logger.Info("delete rule", slog.Int("id", int(id)), slog.Any("rule", rule))
+	if err := f.advRuleMap.Delete(rule.Id); err != nil {
+		f.logger.Error("failed to update advanced rule map", err, slog.Int("id", int(id)), slog.String("network", rule.Prefix.String()))
+		return err
+	}
+
+	return nil
+}
+
+// adds のルールの追加と deletes の id のルールの削除を、XDP プログラムから見て一度に反映します。
+// Set と Delete はプレフィックスごとに rule matcher を書き換えるので、複数のルールを変更すると途中の状態のルールでパケットが評価されます。
+// Apply は適用後のすべてのプレフィックスの rule matcher を次の世代のエントリーとして書き込んでから fw_generation を切り替えるので、
+// XDP プログラムは適用前か適用後のどちらかのルールでパケットを評価します。
+// 追加したルールの id と、削除したルールの id を返します。存在しない id は無視します。
+func (f *FwManager) Apply(adds []FWRule, deletes []uint32) ([]uint32, []uint32, error) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i := range adds {
+		if err := adds[i].validate(); err != nil {
+			return nil, nil, err
+		}
+	}
 
-	if err := f.advRuleMatcher.Lookup(nw, &ids); err != nil {
-		f.logger.Error("failed to lookup rule matcher", err, slog.Any("rule", rule))
+	// 適用後のルールを計算します。
+	rules := make(map[uint32]FWRule, len(f.rules)+len(adds))
+	for id, r := range f.rules {
+		rules[id] = r
+	}
+	deleted := make([]uint32, 0, len(deletes))
+	for _, id := range deletes {
+		if _, ok := rules[id]; !ok {
+			continue
+		}
+		delete(rules, id)
+		deleted = append(deleted, id)
+	}
+	added := make([]uint32, 0, len(adds))
+	for _, r := range adds {
+		r.Id = f.nextId + uint32(len(added))
+		r.Count = 0
+		rules[r.Id] = r
+		added = append(added, r.Id)
+	}
+	if len(added) == 0 && len(deleted) == 0 {
+		return added, deleted, nil
 	}
 
-	f.logger.Debug("ids", slog.Any("ids", ids))
+	// ルールの数や領域が上限を超える場合はマップを変更せずにエラーを返します。
+	// 切り替えるまでは現在の世代の列も参照されるので、すべての列を使用中の領域とは別の領域に割り当てます。
+	matchers := make(map[netip.Prefix][]uint32)
+	for _, r := range rules {
+		matchers[r.matcherPrefix()] = nil
+	}
+	if err := fillRuleMatchers(matchers, rules); err != nil {
+		return nil, nil, err
+	}
+	lists := make(map[netip.Prefix]ruleList, len(matchers))
+	for p, ids := range matchers {
+		l, ok := f.allocator.alloc(uint32(len(ids)))
+		if !ok {
+			for _, l := range lists {
+				f.allocator.release(l)
+			}
+			return nil, nil, fmt.Errorf("no space left in adv_rule_lists to store %d fire wall rules applied to %s(%d of %d entries are available)", len(ids), unmapPrefix(p), f.allocator.available(), f.allocator.size)
+		}
+		lists[p] = l
+	}
 
-	var newIds [constants.ADVANCED_FIRE_WALL_MAX_SIZE_PER_NETWORK]uint16
-	index := 0
+	f.logger.Info("apply fire wall rules", slog.Int("added", len(added)), slog.Int("deleted", len(deleted)), slog.Int("generation", int(f.generation+1)))
 
-	for i := 0; i < constants.ADVANCED_FIRE_WALL_MAX_SIZE_PER_NETWORK; i++ {
-		if ids[i] != uint16(id) && ids[i] != 0 {
-			newIds[index] = ids[i]
-			index += 1
+	// 次の世代のエントリーは fw_generation を切り替えるまで参照されないので、途中で失敗した場合は書き込んだエントリーを削除すれば元に戻ります。
+	generation := f.generation + 1
+	var written []matcherKey
+	rollback := func() {
+		for _, key := range written {
+			if err := f.advRuleMatcher.Delete(key); err != nil {
+				f.logger.Warn("failed to delete a rule matcher of the next generation", slog.String("network", unmapPrefix(key.prefix()).String()))
+			}
+		}
+		for _, id := range added {
+			if err := f.advRuleMap.Delete(id); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+				f.logger.Warn("failed to delete an added rule", slog.Int("id", int(id)))
+			}
+		}
+		for _, l := range lists {
+			f.allocator.release(l)
 		}
 	}
 
-	f.logger.Debug("update rule matcher", slog.String("network", rule.Prefix.String()), slog.Any("ids", ids))
-	if err := f.advRuleMatcher.Update(nw, &ids, ebpf.UpdateAny); err != nil {
-		f.logger.Error("failed to update rule matcher", err, slog.Any("rule", rule), slog.Any("ids", ids))
-		return err
+	// rule matcher から参照される前にルールの本体を登録します。
+	for _, id := range added {
+		rule := rules[id]
+		_, r := rule.splitKeyValue()
+		if err := f.advRuleMap.Update(id, r, ebpf.UpdateAny); err != nil {
+			f.logger.Error("failed to update advanced rule map", err, slog.Int("id", int(id)))
+			rollback()
+			return nil, nil, err
+		}
+	}
+	for p, ids := range matchers {
+		l := lists[p]
+		for i, id := range ids {
+			if err := f.advRuleLists.Update(l.offset+uint32(i), id, ebpf.UpdateAny); err != nil {
+				f.logger.Error("failed to update rule list", err, slog.String("network", unmapPrefix(p).String()), slog.Int("index", int(l.offset)+i))
+				rollback()
+				return nil, nil, err
+			}
+		}
+		key := newMatcherKey(p, generation)
+		if err := f.advRuleMatcher.Update(key, l, ebpf.UpdateAny); err != nil {
+			f.logger.Error("failed to update rule matcher", err, slog.String("network", unmapPrefix(p).String()), slog.Int("generation", int(generation)))
+			rollback()
+			return nil, nil, err
+		}
+		written = append(written, key)
 	}
 
-	f.logger.Info("delete rule", slog.Int("id", int(id)), slog.Any("rule", rule))
-	if err := f.advRuleMap.Delete(rule.Id); err != nil {
-		f.logger.Error("failed to update advanced rule map", err, slog.Int("id", int(id)), slog.String("network", rule.Prefix.String()))
-		return err
+	if err := f.fwGeneration.Update(uint32(0), generation, ebpf.UpdateAny); err != nil {
+		f.logger.Error("failed to switch fire wall generation", err, slog.Int("generation", int(generation)))
+		rollback()
+		return nil, nil, err
 	}
 
-	delete(f.rules, id)
+	// ここから先は XDP プログラムが新しい世代を参照しているので、古い世代のエントリーと削除したルールの後始末に失敗してもエラーにはしません。
+	for p, l := range f.lists {
+		if err := f.advRuleMatcher.Delete(newMatcherKey(p, f.generation)); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			f.logger.Error("failed to delete rule matcher of the previous generation", err, slog.String("network", unmapPrefix(p).String()))
+		}
+		f.allocator.release(l)
+	}
+	f.lists = lists
+	f.generation = generation
+
+	for _, id := range deleted {
+		rule := f.rules[id]
+		nw, _ := rule.splitKeyValue()
+		if err := f.ruleMap.Delete(nw); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			f.logger.Error("failed to delete rule map", err, slog.Int("id", int(id)), slog.String("network", rule.Prefix.String()))
+		}
+		if err := f.dropCounter.Delete(id); err != nil {
+			f.logger.Warn("failed to delete a drop counter entry", slog.Int("id", int(id)))
+		}
+		if err := f.advRuleMap.Delete(id); err != nil {
+			f.logger.Error("failed to delete advanced rule map", err, slog.Int("id", int(id)), slog.String("network", rule.Prefix.String()))
+		}
+	}
+	// 同じプレフィックスのルールを削除したときに消えないように、rules マップへの追加は削除のあとに行います。
+	for _, id := range added {
+		rule := rules[id]
+		nw, r := rule.splitKeyValue()
+		if err := f.ruleMap.Update(nw, r, ebpf.UpdateAny); err != nil {
+			f.logger.Error("failed to update rule map", err, slog.Int("id", int(id)), slog.String("network", rule.Prefix.String()))
+		}
+	}
+
+	f.rules = rules
+	f.nextId += uint32(len(added))
+
+	return added, deleted, nil
+}
+
+// changed に含まれるプレフィックスごとに rule matcher に登録するルール id の列を計算します。
+// LPM Trie では最も長くマッチしたプレフィックスの値しか参照できないので、
+// 各プレフィックスの値にはそのプレフィックスを含むすべてのプレフィックスのルールを評価する順に並べます。
+// ルールが 1 つもなくなったプレフィックスの値は空の列になります。
+// この関数はロックを取得した状態で呼び出す必要があります。
+func (f *FwManager) buildRuleMatchers(changed netip.Prefix) (map[netip.Prefix][]uint32, error) {
+
+	matchers := make(map[netip.Prefix][]uint32)
+	matchers[changed] = nil
+	for _, r := range f.rules {
+		p := r.matcherPrefix()
+		if changed.Bits() <= p.Bits() && changed.Contains(p.Addr()) {
+			matchers[p] = nil
+		}
+	}
+
+	if err := fillRuleMatchers(matchers, f.rules); err != nil {
+		return nil, err
+	}
+	return matchers, nil
+}
 
+// matchers のプレフィックスごとに、ruleSet のうちそのプレフィックスを含むプレフィックスのルールの id を評価する順に並べて格納します。
+func fillRuleMatchers(matchers map[netip.Prefix][]uint32, ruleSet map[uint32]FWRule) error {
+
+	rules := make([]FWRule, 0, len(ruleSet))
+	for _, r := range ruleSet {
+		rules = append(rules, r)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].evaluatedBefore(&rules[j])
+	})
+
+	for p := range matchers {
+		// プレフィックス自体にルールが残っていない場合は値を空にして、updateRuleMatchers でマップから削除します。
+		owned := false
+		var ids []uint32
+		for _, r := range rules {
+			rp := r.matcherPrefix()
+			if rp.Bits() <= p.Bits() && rp.Contains(p.Addr()) {
+				ids = append(ids, r.Id)
+				owned = owned || rp == p
+			}
+		}
+		if !owned {
+			continue
+		}
+		if len(ids) > constants.ADVANCED_FIRE_WALL_MAX_SIZE_PER_NETWORK {
+			return fmt.Errorf("too many fire wall rules are applied to %s: %d rules(max %d)", unmapPrefix(p), len(ids), constants.ADVANCED_FIRE_WALL_MAX_SIZE_PER_NETWORK)
+		}
+		matchers[p] = ids
+	}
+	return nil
+}
+
+// matchers のプレフィックスごとにルール id の列を格納する adv_rule_lists の領域を割り当てます。
+// 列を書き換えている間も XDP プログラムが古い列を参照できるように、使用中の領域とは別の領域を割り当てます。
+// 空き領域が足りない場合、列が短くなるプレフィックスは使用中の領域をそのまま使うので、ルールの削除で割り当てに失敗することはありません。
+// 割り当てに失敗した場合は、それまでに割り当てた領域を解放してエラーを返します。
+// この関数はロックを取得した状態で呼び出す必要があります。
+func (f *FwManager) allocateRuleLists(matchers map[netip.Prefix][]uint32) (map[netip.Prefix]ruleList, error) {
+	lists := make(map[netip.Prefix]ruleList, len(matchers))
+	for p, ids := range matchers {
+		n := uint32(len(ids))
+		if n == 0 {
+			continue
+		}
+		if l, ok := f.allocator.alloc(n); ok {
+			lists[p] = l
+			continue
+		}
+		if cur, ok := f.lists[p]; ok && n <= cur.len {
+			lists[p] = ruleList{offset: cur.offset, len: n}
+			continue
+		}
+		f.releaseRuleLists(lists)
+		return nil, fmt.Errorf("no space left in adv_rule_lists to store %d fire wall rules applied to %s(%d of %d entries are available)", n, unmapPrefix(p), f.allocator.available(), f.allocator.size)
+	}
+	return lists, nil
+}
+
+// allocateRuleLists で割り当てた領域のうち、使用中の領域とは別に割り当てた領域を解放します。
+func (f *FwManager) releaseRuleLists(lists map[netip.Prefix]ruleList) {
+	for p, l := range lists {
+		if cur, ok := f.lists[p]; ok && cur.offset == l.offset {
+			continue
+		}
+		f.allocator.release(l)
+	}
+}
+
+// buildRuleMatchers で計算したルール id の列を allocateRuleLists で割り当てた領域に書き込んでから、rule matcher の値を新しい領域に切り替えます。
+// 空の列のプレフィックスはマップから削除します。
+// 使われなくなった領域は rule matcher を切り替えたあとに解放します。
+func (f *FwManager) updateRuleMatchers(matchers map[netip.Prefix][]uint32, lists map[netip.Prefix]ruleList) error {
+	for p, ids := range matchers {
+		key := newMatcherKey(p, f.generation)
+		cur, exists := f.lists[p]
+		if len(ids) == 0 {
+			f.logger.Debug("delete rule matcher", slog.String("network", unmapPrefix(p).String()))
+			if err := f.advRuleMatcher.Delete(key); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+				f.logger.Error("failed to delete rule matcher", err, slog.String("network", unmapPrefix(p).String()))
+				return err
+			}
+			if exists {
+				f.allocator.release(cur)
+				delete(f.lists, p)
+			}
+			continue
+		}
+
+		l := lists[p]
+		delete(lists, p)
+		for i, id := range ids {
+			if err := f.advRuleLists.Update(l.offset+uint32(i), id, ebpf.UpdateAny); err != nil {
+				f.logger.Error("failed to update rule list", err, slog.String("network", unmapPrefix(p).String()), slog.Int("index", int(l.offset)+i))
+				lists[p] = l
+				f.releaseRuleLists(lists)
+				return err
+			}
+		}
+		f.logger.Debug("update rule matcher", slog.String("network", unmapPrefix(p).String()), slog.Int("offset", int(l.offset)), slog.Any("ids", ids))
+		if err := f.advRuleMatcher.Update(key, l, ebpf.UpdateAny); err != nil {
+			f.logger.Error("failed to update rule matcher", err, slog.String("network", unmapPrefix(p).String()), slog.Any("ids", ids))
+			lists[p] = l
+			f.releaseRuleLists(lists)
+			return err
+		}
+		f.lists[p] = l
+
+		// 使用中の領域をそのまま使った場合は、短くなった分だけ解放します。
+		switch {
+		case !exists:
+		case cur.offset != l.offset:
+			f.allocator.release(cur)
+		case cur.len > l.len:
+			f.allocator.release(ruleList{offset: cur.offset + l.len, len: cur.len - l.len})
+		}
+	}
 	return nil
 }
 
+// IPv4 のアドレスから IPv6 のアドレスへのパケットは存在しないので、マッチすることのないルールはエラーにします。
+func (r *FWRule) validate() error {
+	if r.DstPrefix.IsValid() && r.Prefix.Addr().Is4() && !r.DstPrefix.Addr().Is4() {
+		return fmt.Errorf("destination network %s must be IPv4 when source network %s is IPv4", r.DstPrefix, r.Prefix)
+	}
+	return nil
+}
+
+// Set で追加に失敗したルールを rule matcher から取り除きます。
+// ルールを f.rules から削除したあとに呼び出します。ルールの数は増えないので、ここで列の計算や領域の割り当てに失敗することはありません。
+// この関数はロックを取得した状態で呼び出す必要があります。
+func (f *FwManager) revertRuleMatchers(rule *FWRule) {
+	matchers, err := f.buildRuleMatchers(rule.matcherPrefix())
+	if err != nil {
+		f.logger.Error("failed to build rule matchers to revert", err, slog.Int("id", int(rule.Id)), slog.String("network", rule.Prefix.String()))
+		return
+	}
+	lists, err := f.allocateRuleLists(matchers)
+	if err != nil {
+		f.logger.Error("failed to allocate rule lists to revert", err, slog.Int("id", int(rule.Id)), slog.String("network", rule.Prefix.String()))
+		return
+	}
+	if err := f.updateRuleMatchers(matchers, lists); err != nil {
+		f.logger.Error("failed to revert rule matchers", err, slog.Int("id", int(rule.Id)), slog.String("network", rule.Prefix.String()))
+	}
+}
+
+// Set で上書きした rules マップのエントリーを元に戻します。前の値がない場合は削除します。
+func (f *FwManager) restoreRuleMap(nw network, prev fwRule, hasPrev bool) {
+	var err error
+	if hasPrev {
+		err = f.ruleMap.Update(nw, prev, ebpf.UpdateAny)
+	} else {
+		err = f.ruleMap.Delete(nw)
+	}
+	if err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+		f.logger.Error("failed to restore rule map", err, slog.String("network", unmapPrefix(netip.PrefixFrom(netip.AddrFrom16(nw.address), int(nw.prefixLen))).String()))
+	}
+}
+
+// r が other より先に評価されるかどうかを返します。
+func (r *FWRule) evaluatedBefore(other *FWRule) bool {
+	if r.Priority != other.Priority {
+		return r.Priority < other.Priority
+	}
+	return r.Id < other.Id
+}
+
+// rule matcher のキーとなる IPv6 のプレフィックスを返します。
+// IPv4 のプレフィックスは ::ffff:0:0/96 の中に埋め込むので、IPv6 のプレフィックスとの包含関係もこの値で判定します。
+func (r *FWRule) matcherPrefix() netip.Prefix {
+	return mapPrefix(r.Prefix)
+}
+
+// IPv4 のプレフィックスを ::ffff:0:0/96 の中に埋め込んだ IPv6 のプレフィックスに変換します。
+func mapPrefix(p netip.Prefix) netip.Prefix {
+	prefix := p.Masked()
+	bits := prefix.Bits()
+	if prefix.Addr().Is4() {
+		bits += 96
+	}
+	return netip.PrefixFrom(netip.AddrFrom16(prefix.Addr().As16()), bits)
+}
+
+// matcherPrefix の逆の変換で、ログやエラーに表示するために IPv4 のプレフィックスを元に戻します。
+func unmapPrefix(p netip.Prefix) netip.Prefix {
+	if p.Addr().Is4In6() && p.Bits() >= 96 {
+		return netip.PrefixFrom(p.Addr().Unmap(), p.Bits()-96)
+	}
+	return p
+}
+
 func (r *FWRule) splitKeyValue() (network, fwRule) {
-	addr := r.Prefix.Addr().As4()
-	// ここはリトルエンディアンで格納します
-	addrN := binary.LittleEndian.Uint32(addr[:])
+	// IPv4 のプレフィックスは ::ffff:0:0/96 の中に埋め込むのでプレフィックス長に 96 を足します。
+	prefix := r.matcherPrefix()
 	nw := network{
-		prefixLen: uint32(r.Prefix.Bits()),
-		address:   addrN,
+		prefixLen: uint32(prefix.Bits()),
+		address:   prefix.Addr().As16(),
 	}
 
 	rule := fwRule{
@@ -209,11 +663,202 @@ func (r *FWRule) splitKeyValue() (network, fwRule) {
 		fromDstPort: uint16(r.FromDstPort),
 		toDstPort:   uint16(r.ToDstPort),
 		protocol:    uint32(r.Protocol),
+		priority:    r.Priority,
+		action:      uint32(r.Action),
+	}
+	// 宛先のプレフィックスも送信元と同じように IPv4 のプレフィックスを埋め込んで、マスクしたアドレスを格納します。
+	// 指定されていない場合はプレフィックス長が 0 のままなので、すべての宛先にマッチします。
+	if r.DstPrefix.IsValid() {
+		dst := mapPrefix(r.DstPrefix)
+		rule.dst = network{
+			prefixLen: uint32(dst.Bits()),
+			address:   dst.Addr().As16(),
+		}
 	}
 
 	return nw, rule
 }
 
-func (n network) toUint64() uint64 {
-	return (uint64(n.address) << 32) + uint64(n.prefixLen)
+// rule matcher のプレフィックス p の generation の世代のエントリーのキーを返します。
+func newMatcherKey(p netip.Prefix, generation uint32) matcherKey {
+	return matcherKey{
+		prefixLen:  32 + uint32(p.Bits()),
+		generation: generation,
+		address:    p.Addr().As16(),
+	}
+}
+
+// キーが表す rule matcher のプレフィックスを返します。
+func (k *matcherKey) prefix() netip.Prefix {
+	return netip.PrefixFrom(netip.AddrFrom16(k.address), int(k.prefixLen)-32)
+}
+
+// matcherKey 構造体はフィールドがエクスポートされていないので、bpf マップから読み出すときはこの関数でデコードします。
+func (k *matcherKey) UnmarshalBinary(data []byte) error {
+	if len(data) < 24 {
+		return fmt.Errorf("fw_matcher_key requires 24 bytes: got %d", len(data))
+	}
+	k.prefixLen = binary.LittleEndian.Uint32(data[0:4])
+	k.generation = binary.LittleEndian.Uint32(data[4:8])
+	copy(k.address[:], data[8:24])
+	return nil
+}
+
+// network 構造体はフィールドがエクスポートされていないので、bpf マップから読み出すときはこの関数でデコードします。
+func (n *network) UnmarshalBinary(data []byte) error {
+	if len(data) < 20 {
+		return fmt.Errorf("network requires 20 bytes: got %d", len(data))
+	}
+	n.prefixLen = binary.LittleEndian.Uint32(data[0:4])
+	copy(n.address[:], data[4:20])
+	return nil
+}
+
+// fwRule 構造体はフィールドがエクスポートされていないので、bpf マップから読み出すときはこの関数でデコードします。
+func (r *fwRule) UnmarshalBinary(data []byte) error {
+	if len(data) < 44 {
+		return fmt.Errorf("fw_rule requires 44 bytes: got %d", len(data))
+	}
+	r.id = binary.LittleEndian.Uint32(data[0:4])
+	r.fromSrcPort = binary.LittleEndian.Uint16(data[4:6])
+	r.toSrcPort = binary.LittleEndian.Uint16(data[6:8])
+	r.fromDstPort = binary.LittleEndian.Uint16(data[8:10])
+	r.toDstPort = binary.LittleEndian.Uint16(data[10:12])
+	r.protocol = binary.LittleEndian.Uint32(data[12:16])
+	r.priority = binary.LittleEndian.Uint32(data[16:20])
+	r.action = binary.LittleEndian.Uint32(data[20:24])
+	return r.dst.UnmarshalBinary(data[24:44])
+}
+
+// network 構造体と fwRule 構造体から FWRule を組み立てます。splitKeyValue の逆の変換です。
+func joinKeyValue(nw network, r fwRule) (FWRule, error) {
+	addr := netip.AddrFrom16(nw.address)
+	bits := int(nw.prefixLen)
+	if addr.Is4In6() && bits >= 96 {
+		// IPv4 のプレフィックスは ::ffff:0:0/96 の中に埋め込まれているので元に戻します。
+		addr = addr.Unmap()
+		bits -= 96
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return FWRule{}, err
+	}
+	// プレフィックス長が 0 の宛先は指定されていないルールとして復元します。
+	var dstPrefix netip.Prefix
+	if r.dst.prefixLen != 0 {
+		dstPrefix = unmapPrefix(netip.PrefixFrom(netip.AddrFrom16(r.dst.address), int(r.dst.prefixLen)))
+	}
+	return FWRule{
+		Id:          r.id,
+		Prefix:      prefix,
+		FromSrcPort: uint32(r.fromSrcPort),
+		ToSrcPort:   uint32(r.toSrcPort),
+		FromDstPort: uint32(r.fromDstPort),
+		ToDstPort:   uint32(r.toDstPort),
+		Protocol:    protocols.TransportProtocol(r.protocol),
+		Priority:    r.priority,
+		Action:      Action(r.action),
+		DstPrefix:   dstPrefix,
+	}, nil
+}
+
+// ピン留めされた bpf マップから fire wall のルールを復元します。
+// scmlbd を --persist で起動したときに、前回の起動時に追加されたルールを管理できるようにするための関数です。
+func (f *FwManager) Restore() error {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.fwGeneration.Lookup(uint32(0), &f.generation); err != nil {
+		return fmt.Errorf("failed to lookup fire wall generation: %w", err)
+	}
+
+	var (
+		key matcherKey
+		l   ruleList
+	)
+
+	// ルールはそのプレフィックスに含まれるより長いプレフィックスの列にも並んでいるので、
+	// ルールごとに最も短いプレフィックスをそのルールのプレフィックスとして復元します。
+	// あわせて、列を格納している adv_rule_lists の領域を使用中にします。
+	// Apply の途中で停止した場合に残っている、XDP プログラムが参照していない世代のエントリーは削除します。
+	owners := make(map[uint32]network)
+	var stale []matcherKey
+	entries := f.advRuleMatcher.Iterate()
+	for entries.Next(&key, &l) {
+		if key.generation != f.generation {
+			stale = append(stale, key)
+			continue
+		}
+		p := key.prefix()
+		if !f.allocator.reserve(l) {
+			return fmt.Errorf("rule list of %s overlaps with other rule lists: offset %d, length %d", unmapPrefix(p), l.offset, l.len)
+		}
+		f.lists[p] = l
+		nw := network{
+			prefixLen: uint32(p.Bits()),
+			address:   key.address,
+		}
+		for i := uint32(0); i < l.len; i++ {
+			var id uint32
+			if err := f.advRuleLists.Lookup(l.offset+i, &id); err != nil {
+				return fmt.Errorf("failed to lookup rule list of %s: %w", unmapPrefix(p), err)
+			}
+			if owner, ok := owners[id]; !ok || nw.prefixLen < owner.prefixLen {
+				owners[id] = nw
+			}
+		}
+	}
+	if err := entries.Err(); err != nil {
+		return err
+	}
+	for _, key := range stale {
+		f.logger.Info("delete a rule matcher of an unfinished apply", slog.String("network", unmapPrefix(key.prefix()).String()), slog.Int("generation", int(key.generation)))
+		if err := f.advRuleMatcher.Delete(key); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			return fmt.Errorf("failed to delete rule matcher of %s: %w", unmapPrefix(key.prefix()), err)
+		}
+	}
+
+	// どの列からも参照されていないルールは、ルールの追加や削除の途中で停止したときに残ったルールなので削除します。
+	var (
+		id     uint32
+		r      fwRule
+		orphan []uint32
+	)
+	rules := f.advRuleMap.Iterate()
+	for rules.Next(&id, &r) {
+		if _, ok := owners[id]; !ok {
+			orphan = append(orphan, id)
+		}
+	}
+	if err := rules.Err(); err != nil {
+		return err
+	}
+	for _, id := range orphan {
+		f.logger.Info("delete a fire wall rule of an unfinished apply", slog.Int("id", int(id)))
+		if err := f.advRuleMap.Delete(id); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			return fmt.Errorf("failed to delete fire wall rule %d: %w", id, err)
+		}
+		if err := f.dropCounter.Delete(id); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			return fmt.Errorf("failed to delete drop counter of fire wall rule %d: %w", id, err)
+		}
+	}
+
+	for id, owner := range owners {
+		var r fwRule
+		if err := f.advRuleMap.Lookup(id, &r); err != nil {
+			return fmt.Errorf("failed to lookup fire wall rule %d: %w", id, err)
+		}
+		rule, err := joinKeyValue(owner, r)
+		if err != nil {
+			return err
+		}
+		f.logger.Info("restore a fire wall rule", slog.Int("id", int(rule.Id)), slog.String("network", rule.Prefix.String()), slog.String("protocol", rule.Protocol.String()), slog.String("action", rule.Action.String()), slog.Int("priority", int(rule.Priority)))
+		f.rules[rule.Id] = rule
+		if rule.Id >= f.nextId {
+			f.nextId = rule.Id + 1
+		}
+	}
+
+	return nil
 }
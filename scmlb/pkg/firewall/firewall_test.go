@@ -0,0 +1,80 @@
+package firewall
+
+import (
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
+)
+
+func TestFillRuleMatchers(t *testing.T) {
+	nw8 := mapPrefix(netip.MustParsePrefix("10.0.0.0/8"))
+	nw16 := mapPrefix(netip.MustParsePrefix("10.1.0.0/16"))
+	nw24 := mapPrefix(netip.MustParsePrefix("10.1.1.0/24"))
+	other16 := mapPrefix(netip.MustParsePrefix("10.2.0.0/16"))
+	all := mapPrefix(netip.MustParsePrefix("0.0.0.0/0"))
+
+	ruleSet := map[uint32]FWRule{
+		1: {Id: 1, Prefix: netip.MustParsePrefix("10.0.0.0/8"), Priority: 10},
+		2: {Id: 2, Prefix: netip.MustParsePrefix("10.1.0.0/16"), Priority: 5},
+		3: {Id: 3, Prefix: netip.MustParsePrefix("10.1.1.0/24"), Priority: 10},
+		4: {Id: 4, Prefix: netip.MustParsePrefix("192.168.0.0/16"), Priority: 1},
+		5: {Id: 5, Prefix: netip.MustParsePrefix("10.1.0.0/16"), Priority: 5},
+		6: {Id: 6, Prefix: netip.MustParsePrefix("0.0.0.0/0"), Priority: 20},
+		// より長いプレフィックスのルールでもプライオリティの値が大きければあとに評価します。
+		7: {Id: 7, Prefix: netip.MustParsePrefix("10.1.1.0/24"), Priority: 30},
+	}
+	matchers := map[netip.Prefix][]uint32{
+		all:     nil,
+		nw8:     nil,
+		nw16:    nil,
+		nw24:    nil,
+		other16: nil,
+	}
+
+	if err := fillRuleMatchers(matchers, ruleSet); err != nil {
+		t.Fatal(err)
+	}
+
+	// 最初にマッチしたルールに従うので、各プレフィックスの列はそのプレフィックスを含むすべてのプレフィックスのルールを
+	// プライオリティ、id の順に並べたものになります。
+	want := map[netip.Prefix][]uint32{
+		all:  {6},
+		nw8:  {1, 6},
+		nw16: {2, 5, 1, 6},
+		nw24: {2, 5, 1, 3, 6, 7},
+		// ルールを持たないプレフィックスは空のままにして、rule matcher から削除します。
+		other16: nil,
+	}
+	if !reflect.DeepEqual(matchers, want) {
+		t.Fatalf("got %v, want %v", matchers, want)
+	}
+}
+
+func TestFillRuleMatchersTooManyRules(t *testing.T) {
+	ruleSet := make(map[uint32]FWRule)
+	for i := 1; i <= constants.ADVANCED_FIRE_WALL_MAX_SIZE_PER_NETWORK; i++ {
+		ruleSet[uint32(i)] = FWRule{Id: uint32(i), Prefix: netip.MustParsePrefix("10.0.0.0/8"), Priority: uint32(i)}
+	}
+	nw24 := mapPrefix(netip.MustParsePrefix("10.0.1.0/24"))
+
+	// 外側のプレフィックスのルールが上限ちょうどの場合は登録できます。
+	matchers := map[netip.Prefix][]uint32{mapPrefix(netip.MustParsePrefix("10.0.0.0/8")): nil}
+	if err := fillRuleMatchers(matchers, ruleSet); err != nil {
+		t.Fatal(err)
+	}
+
+	// 内側のプレフィックスには外側のルールも含まれるので、ひとつ追加すると上限を超えます。
+	id := uint32(constants.ADVANCED_FIRE_WALL_MAX_SIZE_PER_NETWORK + 1)
+	ruleSet[id] = FWRule{Id: id, Prefix: netip.MustParsePrefix("10.0.1.0/24")}
+	matchers = map[netip.Prefix][]uint32{nw24: nil}
+	err := fillRuleMatchers(matchers, ruleSet)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.HasPrefix(err.Error(), "too many fire wall rules are applied to 10.0.1.0/24") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package firewall
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// adv_rule_lists の連続した領域を表します。
+// この構造体は bpf/include/scmlb.h の fw_rule_list 構造体に対応していて、adv_rulematcher の値として格納します。
+type ruleList struct {
+	offset uint32
+	len    uint32
+}
+
+// ruleList 構造体はフィールドがエクスポートされていないので、bpf マップから読み出すときはこの関数でデコードします。
+func (l *ruleList) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return fmt.Errorf("fw_rule_list requires 8 bytes: got %d", len(data))
+	}
+	l.offset = binary.LittleEndian.Uint32(data[0:4])
+	l.len = binary.LittleEndian.Uint32(data[4:8])
+	return nil
+}
+
+// adv_rule_lists の領域を割り当てるアロケーターです。
+// 空いている領域を offset の順に保持して、先頭から順に探して最初に見つかった十分な大きさの領域を割り当てます。
+type listAllocator struct {
+	// adv_rule_lists の要素数です。
+	size uint32
+	free []ruleList
+}
+
+func newListAllocator(size uint32) *listAllocator {
+	return &listAllocator{
+		size: size,
+		free: []ruleList{{offset: 0, len: size}},
+	}
+}
+
+// 長さ n の領域を割り当てます。十分な大きさの空き領域がない場合は false を返します。
+func (a *listAllocator) alloc(n uint32) (ruleList, bool) {
+	for i, f := range a.free {
+		if f.len < n {
+			continue
+		}
+		if f.len == n {
+			a.free = append(a.free[:i], a.free[i+1:]...)
+		} else {
+			a.free[i] = ruleList{offset: f.offset + n, len: f.len - n}
+		}
+		return ruleList{offset: f.offset, len: n}, true
+	}
+	return ruleList{}, false
+}
+
+// 領域を解放して、前後の空き領域と連続している場合は結合します。
+func (a *listAllocator) release(l ruleList) {
+	if l.len == 0 {
+		return
+	}
+	i := sort.Search(len(a.free), func(i int) bool {
+		return a.free[i].offset > l.offset
+	})
+	a.free = append(a.free, ruleList{})
+	copy(a.free[i+1:], a.free[i:])
+	a.free[i] = l
+
+	if i+1 < len(a.free) && a.free[i].offset+a.free[i].len == a.free[i+1].offset {
+		a.free[i].len += a.free[i+1].len
+		a.free = append(a.free[:i+1], a.free[i+2:]...)
+	}
+	if i > 0 && a.free[i-1].offset+a.free[i-1].len == a.free[i].offset {
+		a.free[i-1].len += a.free[i].len
+		a.free = append(a.free[:i], a.free[i+1:]...)
+	}
+}
+
+// 復元した領域を使用中にします。空き領域に含まれない場合は false を返します。
+func (a *listAllocator) reserve(l ruleList) bool {
+	if l.len == 0 {
+		return true
+	}
+	for i, f := range a.free {
+		if l.offset < f.offset || l.offset+l.len > f.offset+f.len {
+			continue
+		}
+		rest := make([]ruleList, 0, 2)
+		if l.offset > f.offset {
+			rest = append(rest, ruleList{offset: f.offset, len: l.offset - f.offset})
+		}
+		if end := l.offset + l.len; end < f.offset+f.len {
+			rest = append(rest, ruleList{offset: end, len: f.offset + f.len - end})
+		}
+		a.free = append(a.free[:i], append(rest, a.free[i+1:]...)...)
+		return true
+	}
+	return false
+}
+
+// 空き領域の合計の大きさを返します。
+func (a *listAllocator) available() uint32 {
+	var n uint32
+	for _, f := range a.free {
+		n += f.len
+	}
+	return n
+}
@@ -0,0 +1,307 @@
+package firewall
+
+import (
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestListAllocatorAllocRelease(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   uint32
+		allocs []uint32
+		// allocs で割り当てた領域のうち、解放する領域のインデックスを解放する順に並べます。
+		releases []int
+		want     []ruleList
+	}{
+		{
+			name:   "allocate from the head",
+			size:   10,
+			allocs: []uint32{3, 3},
+			want:   []ruleList{{offset: 6, len: 4}},
+		},
+		{
+			name:   "allocate the whole region",
+			size:   10,
+			allocs: []uint32{4, 6},
+			want:   []ruleList{},
+		},
+		{
+			name:     "release without merging",
+			size:     10,
+			allocs:   []uint32{3, 3, 3},
+			releases: []int{1},
+			want:     []ruleList{{offset: 3, len: 3}, {offset: 9, len: 1}},
+		},
+		{
+			name:     "merge with the next free region",
+			size:     10,
+			allocs:   []uint32{3, 3, 3},
+			releases: []int{1, 0},
+			want:     []ruleList{{offset: 0, len: 6}, {offset: 9, len: 1}},
+		},
+		{
+			name:     "merge with the previous free region",
+			size:     10,
+			allocs:   []uint32{3, 3, 3},
+			releases: []int{0, 1},
+			want:     []ruleList{{offset: 0, len: 6}, {offset: 9, len: 1}},
+		},
+		{
+			name:     "merge with both free regions",
+			size:     10,
+			allocs:   []uint32{3, 3, 3},
+			releases: []int{0, 2, 1},
+			want:     []ruleList{{offset: 0, len: 10}},
+		},
+		{
+			name:     "release all regions in the reverse order",
+			size:     10,
+			allocs:   []uint32{4, 6},
+			releases: []int{1, 0},
+			want:     []ruleList{{offset: 0, len: 10}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newListAllocator(tt.size)
+			lists := make([]ruleList, 0, len(tt.allocs))
+			for _, n := range tt.allocs {
+				l, ok := a.alloc(n)
+				if !ok {
+					t.Fatalf("failed to allocate %d entries", n)
+				}
+				lists = append(lists, l)
+			}
+			for _, i := range tt.releases {
+				a.release(lists[i])
+			}
+			if !reflect.DeepEqual(a.free, tt.want) {
+				t.Fatalf("free regions: got %v, want %v", a.free, tt.want)
+			}
+		})
+	}
+}
+
+func TestListAllocatorFirstFit(t *testing.T) {
+	a := newListAllocator(10)
+	lists := make([]ruleList, 0, 3)
+	for i := 0; i < 3; i++ {
+		l, ok := a.alloc(3)
+		if !ok {
+			t.Fatal("failed to allocate 3 entries")
+		}
+		lists = append(lists, l)
+	}
+	a.release(lists[0])
+
+	// 空き領域の合計は足りていても、連続した領域がなければ割り当てられません。
+	if l, ok := a.alloc(4); ok {
+		t.Fatalf("allocated %v from fragmented free regions %v", l, a.free)
+	}
+	// 先頭から探して最初に見つかった十分な大きさの領域を割り当てます。
+	l, ok := a.alloc(1)
+	if !ok {
+		t.Fatal("failed to allocate 1 entry")
+	}
+	if want := (ruleList{offset: 0, len: 1}); l != want {
+		t.Fatalf("got %v, want %v", l, want)
+	}
+	if got := a.available(); got != 3 {
+		t.Fatalf("available: got %d, want 3", got)
+	}
+}
+
+func TestListAllocatorReserve(t *testing.T) {
+	tests := []struct {
+		name     string
+		reserves []ruleList
+		want     []bool
+		free     []ruleList
+	}{
+		{
+			name:     "middle of a free region",
+			reserves: []ruleList{{offset: 3, len: 2}},
+			want:     []bool{true},
+			free:     []ruleList{{offset: 0, len: 3}, {offset: 5, len: 5}},
+		},
+		{
+			name:     "head of a free region",
+			reserves: []ruleList{{offset: 0, len: 4}},
+			want:     []bool{true},
+			free:     []ruleList{{offset: 4, len: 6}},
+		},
+		{
+			name:     "tail of a free region",
+			reserves: []ruleList{{offset: 6, len: 4}},
+			want:     []bool{true},
+			free:     []ruleList{{offset: 0, len: 6}},
+		},
+		{
+			name:     "whole free region",
+			reserves: []ruleList{{offset: 0, len: 10}},
+			want:     []bool{true},
+			free:     []ruleList{},
+		},
+		{
+			name:     "adjacent regions",
+			reserves: []ruleList{{offset: 2, len: 2}, {offset: 4, len: 2}},
+			want:     []bool{true, true},
+			free:     []ruleList{{offset: 0, len: 2}, {offset: 6, len: 4}},
+		},
+		{
+			name:     "overlap with a reserved region",
+			reserves: []ruleList{{offset: 3, len: 2}, {offset: 4, len: 2}},
+			want:     []bool{true, false},
+			free:     []ruleList{{offset: 0, len: 3}, {offset: 5, len: 5}},
+		},
+		{
+			name:     "same region twice",
+			reserves: []ruleList{{offset: 3, len: 2}, {offset: 3, len: 2}},
+			want:     []bool{true, false},
+			free:     []ruleList{{offset: 0, len: 3}, {offset: 5, len: 5}},
+		},
+		{
+			name:     "beyond the size",
+			reserves: []ruleList{{offset: 8, len: 4}},
+			want:     []bool{false},
+			free:     []ruleList{{offset: 0, len: 10}},
+		},
+		{
+			name:     "empty region",
+			reserves: []ruleList{{offset: 20, len: 0}},
+			want:     []bool{true},
+			free:     []ruleList{{offset: 0, len: 10}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newListAllocator(10)
+			for i, l := range tt.reserves {
+				if got := a.reserve(l); got != tt.want[i] {
+					t.Fatalf("reserve(%v): got %v, want %v", l, got, tt.want[i])
+				}
+			}
+			if !reflect.DeepEqual(a.free, tt.free) {
+				t.Fatalf("free regions: got %v, want %v", a.free, tt.free)
+			}
+		})
+	}
+}
+
+func ruleIds(n int) []uint32 {
+	ids := make([]uint32, n)
+	for i := range ids {
+		ids[i] = uint32(i + 1)
+	}
+	return ids
+}
+
+func TestAllocateRuleLists(t *testing.T) {
+	nw1 := mapPrefix(netip.MustParsePrefix("10.0.1.0/24"))
+	nw2 := mapPrefix(netip.MustParsePrefix("10.0.2.0/24"))
+
+	tests := []struct {
+		name     string
+		size     uint32
+		current  map[netip.Prefix]ruleList
+		matchers map[netip.Prefix][]uint32
+		want     map[netip.Prefix]ruleList
+		wantErr  string
+		// 割り当てたあとの空き領域の合計です。
+		available uint32
+	}{
+		{
+			name:      "allocate new regions",
+			size:      8,
+			current:   map[netip.Prefix]ruleList{},
+			matchers:  map[netip.Prefix][]uint32{nw1: ruleIds(8)},
+			want:      map[netip.Prefix]ruleList{nw1: {offset: 0, len: 8}},
+			available: 0,
+		},
+		{
+			name:      "skip empty lists",
+			size:      8,
+			current:   map[netip.Prefix]ruleList{},
+			matchers:  map[netip.Prefix][]uint32{nw1: ruleIds(3), nw2: nil},
+			want:      map[netip.Prefix]ruleList{nw1: {offset: 0, len: 3}},
+			available: 5,
+		},
+		{
+			name:      "reuse the current region when shrinking",
+			size:      8,
+			current:   map[netip.Prefix]ruleList{nw1: {offset: 0, len: 6}},
+			matchers:  map[netip.Prefix][]uint32{nw1: ruleIds(4)},
+			want:      map[netip.Prefix]ruleList{nw1: {offset: 0, len: 4}},
+			available: 2,
+		},
+		{
+			name:      "allocate another region when it fits",
+			size:      8,
+			current:   map[netip.Prefix]ruleList{nw1: {offset: 0, len: 4}},
+			matchers:  map[netip.Prefix][]uint32{nw1: ruleIds(3)},
+			want:      map[netip.Prefix]ruleList{nw1: {offset: 4, len: 3}},
+			available: 1,
+		},
+		{
+			name:      "no space left",
+			size:      8,
+			current:   map[netip.Prefix]ruleList{},
+			matchers:  map[netip.Prefix][]uint32{nw1: ruleIds(9)},
+			wantErr:   "no space left in adv_rule_lists to store 9 fire wall rules applied to 10.0.1.0/24(8 of 8 entries are available)",
+			available: 8,
+		},
+		{
+			name:      "no space left when growing the current region",
+			size:      8,
+			current:   map[netip.Prefix]ruleList{nw1: {offset: 0, len: 6}},
+			matchers:  map[netip.Prefix][]uint32{nw1: ruleIds(7)},
+			wantErr:   "no space left in adv_rule_lists to store 7 fire wall rules applied to 10.0.1.0/24(2 of 8 entries are available)",
+			available: 2,
+		},
+		{
+			name:     "release allocated regions on failure",
+			size:     8,
+			current:  map[netip.Prefix]ruleList{},
+			matchers: map[netip.Prefix][]uint32{nw1: ruleIds(5), nw2: ruleIds(5)},
+			// どちらのプレフィックスで失敗するかはマップの順序によるので、メッセージの先頭のみを検証します。
+			wantErr:   "no space left in adv_rule_lists to store 5 fire wall rules",
+			available: 8,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &FwManager{
+				lists:     tt.current,
+				allocator: newListAllocator(tt.size),
+			}
+			for _, l := range tt.current {
+				if !f.allocator.reserve(l) {
+					t.Fatalf("failed to reserve %v", l)
+				}
+			}
+
+			lists, err := f.allocateRuleLists(tt.matchers)
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", lists)
+				}
+				if !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %q, want %q", err, tt.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(lists, tt.want) {
+					t.Fatalf("got %v, want %v", lists, tt.want)
+				}
+			}
+			if got := f.allocator.available(); got != tt.available {
+				t.Fatalf("available: got %d, want %d", got, tt.available)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package ipfix
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+const (
+	// IPFIX(RFC 7011) のバージョン番号です。
+	version = 10
+
+	messageHeaderLen = 16
+	setHeaderLen     = 4
+
+	// テンプレートセットのセット ID です。
+	templateSetId = 2
+	// アドレスが IPv4 のフローレコードと IPv6 のフローレコードのテンプレート ID です。
+	templateIdIPv4 = 256
+	templateIdIPv6 = 257
+
+	// IP フラグメントを避けるために 1 つのメッセージをこの大きさ以下に分割します。
+	maxMessageSize = 1400
+
+	// UDP ではコレクターがテンプレートを受け取り損ねることがあるので、この間隔でテンプレートを再送します。
+	templateRefreshInterval = time.Minute
+
+	// RFC 5103 の逆方向の情報要素を表すエンタープライズ番号です。
+	reverseEnterpriseNumber = 29305
+	enterpriseBit           = 0x8000
+)
+
+// フローが終了した理由です。
+// 値は IANA の flowEndReason(136) に対応しています。
+type FlowEndReason uint8
+
+const (
+	FlowEndReasonIdleTimeout     = FlowEndReason(1)
+	FlowEndReasonActiveTimeout   = FlowEndReason(2)
+	FlowEndReasonEndOfFlow       = FlowEndReason(3)
+	FlowEndReasonForcedEnd       = FlowEndReason(4)
+	FlowEndReasonLackOfResources = FlowEndReason(5)
+)
+
+// コレクターに送信するフローレコードです。
+// クライアントからバックエンドへの方向を順方向、バックエンドからクライアントへの方向を逆方向とする双方向のフローとして送信します。
+type FlowRecord struct {
+	SrcAddr  netip.Addr
+	DstAddr  netip.Addr
+	SrcPort  uint16
+	DstPort  uint16
+	Protocol uint8
+	// フローを転送したバックエンドのアドレスです。postNATDestinationAddress として送信します。
+	BackendAddr netip.Addr
+	// クライアントから受信したパケット数とバイト数です。
+	Packets uint64
+	Bytes   uint64
+	// バックエンドから送信されたパケット数とバイト数です。
+	ReversePackets uint64
+	ReverseBytes   uint64
+	Start          time.Time
+	End            time.Time
+	EndReason      FlowEndReason
+}
+
+// テンプレートのフィールドです。
+type field struct {
+	id     uint16
+	length uint16
+	// 逆方向の情報要素かどうかを表します。
+	reverse bool
+}
+
+// アドレスの長さ以外は IPv4 と IPv6 のテンプレートで同じフィールドを同じ順序で並べます。
+func templateFields(addrLen uint16) []field {
+	srcAddrId, dstAddrId, postNATDstAddrId := uint16(8), uint16(12), uint16(226)
+	if addrLen == net.IPv6len {
+		srcAddrId, dstAddrId, postNATDstAddrId = 27, 28, 282
+	}
+	return []field{
+		{id: srcAddrId, length: addrLen},
+		{id: dstAddrId, length: addrLen},
+		// sourceTransportPort, destinationTransportPort, protocolIdentifier
+		{id: 7, length: 2},
+		{id: 11, length: 2},
+		{id: 4, length: 1},
+		{id: postNATDstAddrId, length: addrLen},
+		// packetTotalCount, octetTotalCount
+		{id: 86, length: 8},
+		{id: 85, length: 8},
+		{id: 86, length: 8, reverse: true},
+		{id: 85, length: 8, reverse: true},
+		// flowStartMilliseconds, flowEndMilliseconds, flowEndReason
+		{id: 152, length: 8},
+		{id: 153, length: 8},
+		{id: 136, length: 1},
+	}
+}
+
+// IPFIX でフローレコードを UDP のコレクターに送信します。
+type Exporter struct {
+	mu       sync.Mutex
+	conn     net.Conn
+	domainId uint32
+	// これまでに送信したデータレコードの数です。メッセージヘッダーのシーケンス番号として利用します。
+	sequence     uint32
+	lastTemplate time.Time
+}
+
+// collector(host:port) に IPFIX メッセージを送信するエクスポーターを作成します。
+// domainId はメッセージヘッダーの Observation Domain ID です。
+func New(collector string, domainId uint32) (*Exporter, error) {
+	conn, err := net.Dial("udp", collector)
+	if err != nil {
+		return nil, err
+	}
+	return &Exporter{
+		conn:     conn,
+		domainId: domainId,
+	}, nil
+}
+
+func (e *Exporter) Close() error {
+	return e.conn.Close()
+}
+
+// フローレコードをコレクターに送信します。
+// レコードが多い場合は複数のメッセージに分割して送信します。
+func (e *Exporter) Export(records []FlowRecord) error {
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := time.Now()
+	m := e.newMessage(now)
+	for i := range records {
+		templateId, data := encodeRecord(&records[i])
+		if m.records > 0 && len(m.buf)+setHeaderLen+len(data) > maxMessageSize {
+			if err := e.send(m); err != nil {
+				return err
+			}
+			m = e.newMessage(now)
+		}
+		m.append(templateId, data)
+	}
+	if m.records == 0 {
+		return nil
+	}
+	return e.send(m)
+}
+
+// 送信するメッセージです。
+type message struct {
+	buf []byte
+	// 書き込み中のデータセットの先頭の位置とセット ID です。
+	setStart int
+	setId    uint16
+	records  uint32
+	// テンプレートセットを含むメッセージの場合に、送信できたらテンプレートを送信した時刻として記録する時刻です。
+	template time.Time
+}
+
+// メッセージヘッダーの領域を確保したメッセージを作成します。
+// テンプレートを前回送信してから templateRefreshInterval が経過している場合は先頭にテンプレートセットを含めます。
+func (e *Exporter) newMessage(now time.Time) *message {
+	m := &message{
+		buf: make([]byte, messageHeaderLen, maxMessageSize),
+	}
+	binary.BigEndian.PutUint32(m.buf[4:], uint32(now.Unix()))
+	binary.BigEndian.PutUint32(m.buf[12:], e.domainId)
+
+	if now.Sub(e.lastTemplate) >= templateRefreshInterval {
+		m.buf = appendTemplateSet(m.buf)
+		m.template = now
+	}
+	return m
+}
+
+func appendTemplateSet(buf []byte) []byte {
+	start := len(buf)
+	buf = binary.BigEndian.AppendUint16(buf, templateSetId)
+	buf = binary.BigEndian.AppendUint16(buf, 0)
+	for _, t := range []struct {
+		id      uint16
+		addrLen uint16
+	}{
+		{id: templateIdIPv4, addrLen: net.IPv4len},
+		{id: templateIdIPv6, addrLen: net.IPv6len},
+	} {
+		fields := templateFields(t.addrLen)
+		buf = binary.BigEndian.AppendUint16(buf, t.id)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(fields)))
+		for _, f := range fields {
+			if f.reverse {
+				buf = binary.BigEndian.AppendUint16(buf, f.id|enterpriseBit)
+				buf = binary.BigEndian.AppendUint16(buf, f.length)
+				buf = binary.BigEndian.AppendUint32(buf, reverseEnterpriseNumber)
+				continue
+			}
+			buf = binary.BigEndian.AppendUint16(buf, f.id)
+			buf = binary.BigEndian.AppendUint16(buf, f.length)
+		}
+	}
+	binary.BigEndian.PutUint16(buf[start+2:], uint16(len(buf)-start))
+	return buf
+}
+
+// データレコードを追加します。テンプレートが直前のレコードと異なる場合は新しいデータセットを開始します。
+func (m *message) append(templateId uint16, data []byte) {
+	if m.setId != templateId {
+		m.closeSet()
+		m.setStart = len(m.buf)
+		m.setId = templateId
+		m.buf = binary.BigEndian.AppendUint16(m.buf, templateId)
+		m.buf = binary.BigEndian.AppendUint16(m.buf, 0)
+	}
+	m.buf = append(m.buf, data...)
+	m.records += 1
+}
+
+// 書き込み中のデータセットの長さを書き込みます。
+func (m *message) closeSet() {
+	if m.setId == 0 {
+		return
+	}
+	binary.BigEndian.PutUint16(m.buf[m.setStart+2:], uint16(len(m.buf)-m.setStart))
+}
+
+func (e *Exporter) send(m *message) error {
+	m.closeSet()
+	binary.BigEndian.PutUint16(m.buf[0:], version)
+	binary.BigEndian.PutUint16(m.buf[2:], uint16(len(m.buf)))
+	binary.BigEndian.PutUint32(m.buf[8:], e.sequence)
+
+	if _, err := e.conn.Write(m.buf); err != nil {
+		return fmt.Errorf("failed to send an IPFIX message to %s: %w", e.conn.RemoteAddr(), err)
+	}
+	// シーケンス番号は送信したデータレコードの数なので、送信できなかったメッセージの分は数えません。
+	// テンプレートも送信できた場合のみ記録して、送信できなかった場合は次のメッセージで送り直します。
+	e.sequence += m.records
+	if !m.template.IsZero() {
+		e.lastTemplate = m.template
+	}
+	return nil
+}
+
+// フローレコードをテンプレートのフィールドの順に符号化して、対応するテンプレート ID とともに返します。
+// バックエンドのアドレスが不明な場合はすべて 0 のアドレスを送信します。
+func encodeRecord(r *FlowRecord) (uint16, []byte) {
+	templateId, addrLen := uint16(templateIdIPv4), net.IPv4len
+	if !is4(r.SrcAddr) || !is4(r.DstAddr) || (r.BackendAddr.IsValid() && !is4(r.BackendAddr)) {
+		templateId, addrLen = templateIdIPv6, net.IPv6len
+	}
+
+	buf := make([]byte, 0, 3*addrLen+54)
+	buf = appendAddr(buf, r.SrcAddr, addrLen)
+	buf = appendAddr(buf, r.DstAddr, addrLen)
+	buf = binary.BigEndian.AppendUint16(buf, r.SrcPort)
+	buf = binary.BigEndian.AppendUint16(buf, r.DstPort)
+	buf = append(buf, r.Protocol)
+	buf = appendAddr(buf, r.BackendAddr, addrLen)
+	buf = binary.BigEndian.AppendUint64(buf, r.Packets)
+	buf = binary.BigEndian.AppendUint64(buf, r.Bytes)
+	buf = binary.BigEndian.AppendUint64(buf, r.ReversePackets)
+	buf = binary.BigEndian.AppendUint64(buf, r.ReverseBytes)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(r.Start.UnixMilli()))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(r.End.UnixMilli()))
+	buf = append(buf, uint8(r.EndReason))
+	return templateId, buf
+}
+
+// conntrack のアドレスは IPv4-mapped IPv6 アドレスで保持しているので、IPv4 として扱えるかは Unmap して判定します。
+func is4(addr netip.Addr) bool {
+	return addr.Unmap().Is4()
+}
+
+func appendAddr(buf []byte, addr netip.Addr, addrLen int) []byte {
+	if !addr.IsValid() {
+		return append(buf, make([]byte, addrLen)...)
+	}
+	if addrLen == net.IPv4len {
+		a := addr.Unmap().As4()
+		return append(buf, a[:]...)
+	}
+	a := addr.As16()
+	return append(buf, a[:]...)
+}
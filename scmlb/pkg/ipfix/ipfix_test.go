@@ -0,0 +1,363 @@
+package ipfix
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+// コレクターの代わりに 127.0.0.1 で UDP を待ち受けて、エクスポーターを向けます。
+func setupCollector(t *testing.T) (*net.UDPConn, *Exporter) {
+	t.Helper()
+	collector, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { collector.Close() })
+
+	e, err := New(collector.LocalAddr().String(), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { e.Close() })
+	return collector, e
+}
+
+type decodedSet struct {
+	id   uint16
+	body []byte
+}
+
+type decodedMessage struct {
+	version  uint16
+	length   uint16
+	sequence uint32
+	domainId uint32
+	sets     []decodedSet
+}
+
+func receive(t *testing.T, collector *net.UDPConn) decodedMessage {
+	t.Helper()
+	buf := make([]byte, 65535)
+	if err := collector.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	n, err := collector.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf = buf[:n]
+	if n > maxMessageSize {
+		t.Fatalf("message size %d exceeds %d", n, maxMessageSize)
+	}
+
+	m := decodedMessage{
+		version:  binary.BigEndian.Uint16(buf[0:]),
+		length:   binary.BigEndian.Uint16(buf[2:]),
+		sequence: binary.BigEndian.Uint32(buf[8:]),
+		domainId: binary.BigEndian.Uint32(buf[12:]),
+	}
+	if int(m.length) != n {
+		t.Fatalf("message length %d, received %d bytes", m.length, n)
+	}
+	for rest := buf[messageHeaderLen:]; len(rest) > 0; {
+		if len(rest) < setHeaderLen {
+			t.Fatalf("truncated set header: %d bytes", len(rest))
+		}
+		id := binary.BigEndian.Uint16(rest[0:])
+		length := int(binary.BigEndian.Uint16(rest[2:]))
+		if length < setHeaderLen || length > len(rest) {
+			t.Fatalf("set %d has invalid length %d(%d bytes left)", id, length, len(rest))
+		}
+		m.sets = append(m.sets, decodedSet{id: id, body: rest[setHeaderLen:length]})
+		rest = rest[length:]
+	}
+	return m
+}
+
+// テンプレートセットを読んで、テンプレート ID ごとのフィールドを返します。
+func decodeTemplates(t *testing.T, body []byte) map[uint16][]field {
+	t.Helper()
+	templates := make(map[uint16][]field)
+	for len(body) > 0 {
+		id := binary.BigEndian.Uint16(body[0:])
+		count := int(binary.BigEndian.Uint16(body[2:]))
+		body = body[4:]
+		fields := make([]field, 0, count)
+		for i := 0; i < count; i++ {
+			f := field{
+				id:     binary.BigEndian.Uint16(body[0:]),
+				length: binary.BigEndian.Uint16(body[2:]),
+			}
+			body = body[4:]
+			if f.id&enterpriseBit != 0 {
+				if pen := binary.BigEndian.Uint32(body); pen != reverseEnterpriseNumber {
+					t.Fatalf("template %d field %d has enterprise number %d", id, i, pen)
+				}
+				body = body[4:]
+				f.id &^= enterpriseBit
+				f.reverse = true
+			}
+			fields = append(fields, f)
+		}
+		templates[id] = fields
+	}
+	return templates
+}
+
+func TestExport(t *testing.T) {
+	collector, e := setupCollector(t)
+
+	start := time.UnixMilli(1700000000000)
+	end := start.Add(90 * time.Second)
+	records := []FlowRecord{
+		{
+			// conntrack のアドレスは IPv4-mapped IPv6 アドレスです。
+			SrcAddr:        netip.MustParseAddr("::ffff:192.0.2.1"),
+			DstAddr:        netip.MustParseAddr("::ffff:203.0.113.11"),
+			SrcPort:        40000,
+			DstPort:        80,
+			Protocol:       6,
+			BackendAddr:    netip.MustParseAddr("::ffff:10.0.1.2"),
+			Packets:        10,
+			Bytes:          1000,
+			ReversePackets: 8,
+			ReverseBytes:   4000,
+			Start:          start,
+			End:            end,
+			EndReason:      FlowEndReasonEndOfFlow,
+		},
+		{
+			SrcAddr:        netip.MustParseAddr("2001:db8::1"),
+			DstAddr:        netip.MustParseAddr("2001:db8:1::11"),
+			SrcPort:        50000,
+			DstPort:        53,
+			Protocol:       17,
+			Packets:        1,
+			Bytes:          70,
+			ReversePackets: 1,
+			ReverseBytes:   120,
+			Start:          start,
+			End:            end,
+			EndReason:      FlowEndReasonIdleTimeout,
+		},
+	}
+	if err := e.Export(records); err != nil {
+		t.Fatal(err)
+	}
+
+	m := receive(t, collector)
+	if m.version != version {
+		t.Errorf("version: got %d, want %d", m.version, version)
+	}
+	if m.domainId != 7 {
+		t.Errorf("observation domain id: got %d, want 7", m.domainId)
+	}
+	if m.sequence != 0 {
+		t.Errorf("sequence: got %d, want 0", m.sequence)
+	}
+	if len(m.sets) != 3 {
+		t.Fatalf("got %d sets, want template set and 2 data sets", len(m.sets))
+	}
+
+	if m.sets[0].id != templateSetId {
+		t.Fatalf("first set id: got %d, want %d", m.sets[0].id, templateSetId)
+	}
+	templates := decodeTemplates(t, m.sets[0].body)
+	wantIds := map[uint16][]uint16{
+		templateIdIPv4: {8, 12, 7, 11, 4, 226, 86, 85, 86, 85, 152, 153, 136},
+		templateIdIPv6: {27, 28, 7, 11, 4, 282, 86, 85, 86, 85, 152, 153, 136},
+	}
+	for templateId, ids := range wantIds {
+		fields, ok := templates[templateId]
+		if !ok {
+			t.Fatalf("template %d is not found", templateId)
+		}
+		if len(fields) != len(ids) {
+			t.Fatalf("template %d: got %d fields, want %d", templateId, len(fields), len(ids))
+		}
+		for i, f := range fields {
+			if f.id != ids[i] {
+				t.Errorf("template %d field %d: got id %d, want %d", templateId, i, f.id, ids[i])
+			}
+			// packetTotalCount と octetTotalCount の 2 つ目は RFC 5103 の逆方向の情報要素です。
+			if wantReverse := i == 8 || i == 9; f.reverse != wantReverse {
+				t.Errorf("template %d field %d: got reverse %v, want %v", templateId, i, f.reverse, wantReverse)
+			}
+		}
+	}
+
+	for i, want := range []struct {
+		templateId uint16
+		addrLen    int
+		record     *FlowRecord
+	}{
+		{templateId: templateIdIPv4, addrLen: net.IPv4len, record: &records[0]},
+		{templateId: templateIdIPv6, addrLen: net.IPv6len, record: &records[1]},
+	} {
+		set := m.sets[i+1]
+		if set.id != want.templateId {
+			t.Fatalf("data set %d: got set id %d, want %d", i, set.id, want.templateId)
+		}
+		if len(set.body) != 3*want.addrLen+54 {
+			t.Fatalf("data set %d: got %d bytes, want %d", i, len(set.body), 3*want.addrLen+54)
+		}
+		checkRecord(t, set.body, want.addrLen, want.record)
+	}
+}
+
+// データレコードをテンプレートのフィールドの順に読んで比較します。
+func checkRecord(t *testing.T, b []byte, addrLen int, want *FlowRecord) {
+	t.Helper()
+	addr := func() netip.Addr {
+		a, _ := netip.AddrFromSlice(b[:addrLen])
+		b = b[addrLen:]
+		return a
+	}
+	u16 := func() uint16 {
+		v := binary.BigEndian.Uint16(b)
+		b = b[2:]
+		return v
+	}
+	u64 := func() uint64 {
+		v := binary.BigEndian.Uint64(b)
+		b = b[8:]
+		return v
+	}
+	wantAddr := func(a netip.Addr) netip.Addr {
+		switch {
+		case !a.IsValid() && addrLen == net.IPv4len:
+			return netip.IPv4Unspecified()
+		case !a.IsValid():
+			return netip.IPv6Unspecified()
+		case addrLen == net.IPv4len:
+			return a.Unmap()
+		default:
+			return a
+		}
+	}
+
+	if got := addr(); got != wantAddr(want.SrcAddr) {
+		t.Errorf("source address: got %s, want %s", got, want.SrcAddr)
+	}
+	if got := addr(); got != wantAddr(want.DstAddr) {
+		t.Errorf("destination address: got %s, want %s", got, want.DstAddr)
+	}
+	if got := u16(); got != want.SrcPort {
+		t.Errorf("source port: got %d, want %d", got, want.SrcPort)
+	}
+	if got := u16(); got != want.DstPort {
+		t.Errorf("destination port: got %d, want %d", got, want.DstPort)
+	}
+	if got := b[0]; got != want.Protocol {
+		t.Errorf("protocol: got %d, want %d", got, want.Protocol)
+	}
+	b = b[1:]
+	if got := addr(); got != wantAddr(want.BackendAddr) {
+		t.Errorf("backend address: got %s, want %s", got, want.BackendAddr)
+	}
+	for _, c := range []struct {
+		name string
+		want uint64
+	}{
+		{name: "packets", want: want.Packets},
+		{name: "bytes", want: want.Bytes},
+		{name: "reverse packets", want: want.ReversePackets},
+		{name: "reverse bytes", want: want.ReverseBytes},
+		{name: "start", want: uint64(want.Start.UnixMilli())},
+		{name: "end", want: uint64(want.End.UnixMilli())},
+	} {
+		if got := u64(); got != c.want {
+			t.Errorf("%s: got %d, want %d", c.name, got, c.want)
+		}
+	}
+	if got := FlowEndReason(b[0]); got != want.EndReason {
+		t.Errorf("end reason: got %d, want %d", got, want.EndReason)
+	}
+}
+
+func TestExportSplitMessages(t *testing.T) {
+	collector, e := setupCollector(t)
+
+	record := FlowRecord{
+		SrcAddr:  netip.MustParseAddr("192.0.2.1"),
+		DstAddr:  netip.MustParseAddr("203.0.113.11"),
+		Protocol: 6,
+	}
+	// テンプレートを送信したあとのメッセージにはテンプレートを含めません。
+	if err := e.Export([]FlowRecord{record}); err != nil {
+		t.Fatal(err)
+	}
+	receive(t, collector)
+
+	records := make([]FlowRecord, 50)
+	for i := range records {
+		records[i] = record
+		records[i].SrcPort = uint16(i)
+	}
+	if err := e.Export(records); err != nil {
+		t.Fatal(err)
+	}
+
+	sequence := uint32(1)
+	received := 0
+	for received < len(records) {
+		m := receive(t, collector)
+		if m.sequence != sequence {
+			t.Errorf("sequence: got %d, want %d", m.sequence, sequence)
+		}
+		if len(m.sets) != 1 || m.sets[0].id != templateIdIPv4 {
+			t.Fatalf("got sets %v, want only one IPv4 data set", m.sets)
+		}
+		n := len(m.sets[0].body) / (3*net.IPv4len + 54)
+		if n == 0 {
+			t.Fatal("message has no records")
+		}
+		received += n
+		sequence += uint32(n)
+	}
+	if received != len(records) {
+		t.Errorf("got %d records, want %d", received, len(records))
+	}
+}
+
+type failingConn struct {
+	net.Conn
+}
+
+func (c *failingConn) Write(b []byte) (int, error) {
+	return 0, errors.New("failed to write")
+}
+
+func TestExportResendTemplateAfterFailure(t *testing.T) {
+	collector, e := setupCollector(t)
+
+	record := FlowRecord{
+		SrcAddr:  netip.MustParseAddr("192.0.2.1"),
+		DstAddr:  netip.MustParseAddr("203.0.113.11"),
+		Protocol: 17,
+	}
+
+	conn := e.conn
+	e.conn = &failingConn{Conn: conn}
+	if err := e.Export([]FlowRecord{record}); err == nil {
+		t.Fatal("expected an error")
+	}
+	e.conn = conn
+
+	// テンプレートを含むメッセージを送信できなかったので、次のメッセージにテンプレートを含めます。
+	// 送信できなかったレコードはシーケンス番号に数えません。
+	if err := e.Export([]FlowRecord{record}); err != nil {
+		t.Fatal(err)
+	}
+	m := receive(t, collector)
+	if len(m.sets) != 2 || m.sets[0].id != templateSetId {
+		t.Fatalf("got sets %v, want a template set and a data set", m.sets)
+	}
+	if m.sequence != 0 {
+		t.Errorf("sequence: got %d, want 0", m.sequence)
+	}
+}
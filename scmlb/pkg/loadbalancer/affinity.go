@@ -0,0 +1,189 @@
+package loadbalancer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/netip"
+	"sort"
+	"time"
+
+	"github.com/cilium/ebpf"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
+	"golang.org/x/exp/slog"
+	"golang.org/x/sys/unix"
+)
+
+// 送信元アドレスによるセッション維持のテーブルのエントリーです。
+type AffinityEntry struct {
+	ServiceId  uint32
+	ClientAddr netip.Addr
+	BackendId  uint32
+	// 最後にパケットを受信してからの経過時間です。
+	Idle time.Duration
+	// アイドルタイムアウトを過ぎていて、次の新しいコネクションではバックエンドが選び直されるエントリーかどうかを表します。
+	Expired bool
+}
+
+// この構造体は bpf/include/scmlb.h の affinity_key 構造体に対応しています。
+type affinityKey struct {
+	ClientAddr [16]byte
+	ServiceId  uint32
+}
+
+// この構造体は bpf/include/scmlb.h の affinity_info 構造体に対応しています。
+type affinityInfo struct {
+	BackendId uint32
+	Pad       uint32
+	LastSeen  uint64
+}
+
+// サービスの送信元アドレスによるセッション維持の設定を変更します。
+// timeout に 0 を指定するとセッション維持を無効にして、そのサービスのセッション維持のエントリーを削除します。
+// XDP プログラムでは秒単位で扱うので、timeout は秒単位に切り捨てられます。
+func (l *LbBackendManager) SetAffinity(serviceId uint32, timeout time.Duration) error {
+
+	if timeout < 0 || (timeout != 0 && timeout < time.Second) {
+		return fmt.Errorf("affinity timeout must be 0 or greater than or equal to 1s: %s", timeout)
+	}
+	if timeout/time.Second > math.MaxUint32 {
+		return fmt.Errorf("affinity timeout is too long: %s", timeout)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s, ok := l.services[serviceId]
+	if !ok {
+		return fmt.Errorf("service is not found. id is %d", serviceId)
+	}
+
+	s.AffinityTimeout = timeout.Truncate(time.Second)
+
+	l.logger.Info("set session affinity", slog.Int("id", int(s.Id)), slog.String("service", s.String()), slog.Duration("timeout", s.AffinityTimeout))
+	if err := l.servicesMap.Update(s.key(), s.info(), ebpf.UpdateExist); err != nil {
+		return err
+	}
+
+	if s.AffinityTimeout == 0 {
+		return l.flushAffinity(s.Id)
+	}
+	return nil
+}
+
+// セッション維持のテーブルのエントリーを取得します。
+// serviceId に 0 を指定するとすべてのサービスのエントリーを取得します。
+func (l *LbBackendManager) GetAffinity(serviceId uint32) ([]AffinityEntry, error) {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if serviceId != 0 {
+		if _, ok := l.services[serviceId]; !ok {
+			return nil, fmt.Errorf("service is not found. id is %d", serviceId)
+		}
+	}
+
+	now, err := monotonicNow()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		key   affinityKey
+		value affinityInfo
+	)
+
+	entries := make([]AffinityEntry, 0)
+
+	iter := l.affinityMap.Iterate()
+	for iter.Next(&key, &value) {
+		if serviceId != 0 && key.ServiceId != serviceId {
+			continue
+		}
+		entry := AffinityEntry{
+			ServiceId:  key.ServiceId,
+			ClientAddr: protocols.IpAddrFrom16(key.ClientAddr),
+			BackendId:  value.BackendId,
+		}
+		if now > value.LastSeen {
+			entry.Idle = time.Duration(now - value.LastSeen)
+		}
+		// セッション維持が無効になったサービスのエントリーも期限切れとして扱います。
+		s, ok := l.services[key.ServiceId]
+		if !ok || s.AffinityTimeout == 0 || entry.Idle >= s.AffinityTimeout {
+			entry.Expired = true
+		}
+		entries = append(entries, entry)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].ServiceId != entries[j].ServiceId {
+			return entries[i].ServiceId < entries[j].ServiceId
+		}
+		return entries[i].ClientAddr.Less(entries[j].ClientAddr)
+	})
+
+	return entries, nil
+}
+
+// セッション維持のテーブルのエントリーを削除します。
+// serviceId に 0 を指定するとすべてのサービスのエントリーを削除します。
+func (l *LbBackendManager) FlushAffinity(serviceId uint32) error {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if serviceId != 0 {
+		if _, ok := l.services[serviceId]; !ok {
+			return fmt.Errorf("service is not found. id is %d", serviceId)
+		}
+	}
+
+	l.logger.Info("flush session affinity entries", slog.Int("service id", int(serviceId)))
+	return l.flushAffinity(serviceId)
+}
+
+// セッション維持のテーブルから指定されたサービスのエントリーを削除します。
+// serviceId が 0 の場合はすべてのエントリーを削除します。
+// この関数はロックを取得した状態で呼び出す必要があります。
+func (l *LbBackendManager) flushAffinity(serviceId uint32) error {
+
+	var (
+		key   affinityKey
+		value affinityInfo
+	)
+
+	// イテレーション中にエントリーを削除すると走査が先頭からやり直しになることがあるので、先にキーを集めてから削除します。
+	keys := make([]affinityKey, 0)
+	iter := l.affinityMap.Iterate()
+	for iter.Next(&key, &value) {
+		if serviceId != 0 && key.ServiceId != serviceId {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		// LRU のマップなので集めたあとに追い出されたエントリーは無視します。
+		if err := l.affinityMap.Delete(k); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// bpf_ktime_get_ns() と同じ CLOCK_MONOTONIC の現在時刻をナノ秒で返します。
+func monotonicNow() (uint64, error) {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &ts); err != nil {
+		return 0, err
+	}
+	return uint64(ts.Nano()), nil
+}
@@ -1,63 +1,309 @@
 package loadbalancer
 
 import (
+	"errors"
+	"fmt"
+	"net"
 	"net/netip"
+	"strings"
 	"time"
 
+	"github.com/cilium/ebpf"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
+	"golang.org/x/exp/slog"
 )
 
 type ConntrackEntry struct {
-	SrcAddr   netip.Addr
-	DstAddr   netip.Addr
-	SrcPort   uint32
-	DstPort   uint32
-	Protocol  protocols.TransportProtocol
-	State     ConnectionState
+	SrcAddr  netip.Addr
+	DstAddr  netip.Addr
+	SrcPort  uint32
+	DstPort  uint32
+	Protocol protocols.TransportProtocol
+	State    ConnectionState
+	// 最後にいずれかの方向のパケットを処理した時刻です。
 	Timestamp time.Time
+	// 最初のパケットを受信した時刻です。
+	FirstSeen time.Time
 	BackendId uint32
-	Counter   uint64
+	// クライアントから受信したパケット数です。
+	Counter uint64
+	// バックエンドから送信されたパケット数です。
+	EgressPackets uint64
+	IngressBytes  uint64
+	EgressBytes   uint64
+	// クライアント側の送信元 MAC アドレスです。戻りパケットの宛先 MAC アドレスとして利用されます。
+	SrcMacAddr net.HardwareAddr
+	// peer の scmlbd から同期されて、まだこのホストでパケットを処理していないエントリーかどうかを表します。
+	Replicated bool
 }
 
+// この構造体は bpf/include/scmlb.h の connection 構造体に対応しています。
+// アドレスは IPv4-mapped IPv6 アドレスを含む 16 byte の値です。
 type conntrackKey struct {
-	SrcAddr  uint32
-	DstAddr  uint32
+	SrcAddr  [16]byte
+	DstAddr  [16]byte
 	SrcPort  uint16
 	DstPort  uint16
 	Protocol uint32
 }
 
+// この構造体は bpf/include/scmlb.h の connection_info 構造体に対応しています。
+// FirstSeen と LastSeen は bpf_ktime_get_ns() で取得した CLOCK_MONOTONIC の時刻です。
 type conntrackInfo struct {
-	Id         uint32
-	Index      uint32
-	Status     uint8
-	SrcMacAddr [6]uint8
-	Counter    uint64
+	Id            uint32
+	Index         uint32
+	Status        uint8
+	Closer        uint8
+	SrcMacAddr    [6]uint8
+	Counter       uint64
+	EgressPackets uint64
+	IngressBytes  uint64
+	EgressBytes   uint64
+	FirstSeen     uint64
+	LastSeen      uint64
 }
 
+// bpf マップのキーと値から conntrack のエントリーを作成します。
+// monoNow と now には同じ時点の CLOCK_MONOTONIC の時刻と現在時刻を渡します。
+func newConntrackEntry(key *conntrackKey, value *conntrackInfo, monoNow uint64, now time.Time) *ConntrackEntry {
+	entry := &ConntrackEntry{
+		SrcAddr:    protocols.IpAddrFrom16(key.SrcAddr),
+		DstAddr:    protocols.IpAddrFrom16(key.DstAddr),
+		SrcPort:    uint32(protocols.Ntohs(key.SrcPort)),
+		DstPort:    uint32(protocols.Ntohs(key.DstPort)),
+		Protocol:   protocols.TransportProtocol(key.Protocol),
+		BackendId:  value.Id,
+		SrcMacAddr: net.HardwareAddr(value.SrcMacAddr[:]),
+	}
+	entry.update(value, monoNow, now)
+	return entry
+}
+
+// bpf マップの値でエントリーの状態と統計情報を更新します。
+func (e *ConntrackEntry) update(value *conntrackInfo, monoNow uint64, now time.Time) {
+	e.State = ConnectionState(value.Status)
+	e.Counter = value.Counter
+	e.EgressPackets = value.EgressPackets
+	e.IngressBytes = value.IngressBytes
+	e.EgressBytes = value.EgressBytes
+	e.FirstSeen = ktimeToTime(value.FirstSeen, monoNow, now)
+	e.Timestamp = ktimeToTime(value.LastSeen, monoNow, now)
+	// XDP プログラムが作成したエントリーは必ずパケット数が 1 以上なので、パケット数が 0 のエントリーは peer から同期されたものです。
+	e.Replicated = value.Counter == 0 && value.EgressPackets == 0
+}
+
+// bpf_ktime_get_ns() で取得した時刻を、同じ時点の CLOCK_MONOTONIC の時刻と現在時刻の差をもとに time.Time に変換します。
+func ktimeToTime(ktime, monoNow uint64, now time.Time) time.Time {
+	if ktime == 0 || ktime > monoNow {
+		return now
+	}
+	return now.Add(-time.Duration(monoNow - ktime))
+}
+
+// バックエンドの負荷として数えるコネクションかどうかを返します。
+// 終了処理中の TCP コネクションは数えません。
+func (e *ConntrackEntry) isActive() bool {
+	switch e.State {
+	case ConnectionStateNotTcp, ConnectionStateSynSent, ConnectionStateSynRecv, ConnectionStateEstablished:
+		return true
+	default:
+		return false
+	}
+}
+
+// 両方向の終了処理が完了したか、RST で終了した TCP コネクションかどうかを返します。
+// TimeWait のエントリーは遅れて届くパケットのために残していますが、コネクションとしては終了しています。
+func (e *ConntrackEntry) isFinished() bool {
+	return e.State == ConnectionStateTimeWait || e.State == ConnectionStateClosed
+}
+
+// TCP コネクションの状態です。
+// bpf/include/scmlb.h の ConnectionStatus に対応していて、Linux の nf_conntrack の TCP の状態と同じ意味を持ちます。
 type ConnectionState uint8
 
 const (
-	ConnectionStateNotTcp      = ConnectionState(0)
-	ConnectionStateOpening     = ConnectionState(1)
-	ConnectionStateEstablished = ConnectionState(2)
-	ConnectionStateClosing     = ConnectionState(3)
-	ConnectionStateClosed      = ConnectionState(4)
+	ConnectionStateNotTcp = ConnectionState(0)
+	// クライアントの SYN を受信した状態です。
+	ConnectionStateSynSent = ConnectionState(1)
+	// バックエンドの SYN+ACK を受信した状態です。
+	ConnectionStateSynRecv     = ConnectionState(2)
+	ConnectionStateEstablished = ConnectionState(3)
+	// 片方から FIN を受信した状態です。
+	ConnectionStateFinWait = ConnectionState(4)
+	// 先に FIN を送った側に反対側から ACK が返った、片方向のみ閉じられた状態です。
+	ConnectionStateCloseWait = ConnectionState(5)
+	// 両方向の FIN を受信して最後の ACK を待っている状態です。
+	ConnectionStateLastAck = ConnectionState(6)
+	// 最後の ACK を受信した状態です。
+	ConnectionStateTimeWait = ConnectionState(7)
+	// RST を受信した状態です。
+	ConnectionStateClosed = ConnectionState(8)
 )
 
+// TCP コネクションの状態の一覧です。
+var TcpConnectionStates = []ConnectionState{
+	ConnectionStateSynSent,
+	ConnectionStateSynRecv,
+	ConnectionStateEstablished,
+	ConnectionStateFinWait,
+	ConnectionStateCloseWait,
+	ConnectionStateLastAck,
+	ConnectionStateTimeWait,
+	ConnectionStateClosed,
+}
+
 func (c ConnectionState) String() string {
 	switch c {
 	case ConnectionStateNotTcp:
 		return "NotTCP"
-	case ConnectionStateOpening:
-		return "Opening"
+	case ConnectionStateSynSent:
+		return "SynSent"
+	case ConnectionStateSynRecv:
+		return "SynRecv"
 	case ConnectionStateEstablished:
 		return "Established"
-	case ConnectionStateClosing:
-		return "Closing"
+	case ConnectionStateFinWait:
+		return "FinWait"
+	case ConnectionStateCloseWait:
+		return "CloseWait"
+	case ConnectionStateLastAck:
+		return "LastAck"
+	case ConnectionStateTimeWait:
+		return "TimeWait"
 	case ConnectionStateClosed:
 		return "Closed"
 	default:
 		return "Unknown"
 	}
 }
+
+// 文字列から TCP コネクションの状態を返します。
+// 大文字と小文字は区別しません。
+func ConnectionStateFromString(s string) (ConnectionState, error) {
+	switch strings.ToLower(s) {
+	case "nottcp", "udp":
+		return ConnectionStateNotTcp, nil
+	case "synsent":
+		return ConnectionStateSynSent, nil
+	case "synrecv":
+		return ConnectionStateSynRecv, nil
+	case "established":
+		return ConnectionStateEstablished, nil
+	case "finwait":
+		return ConnectionStateFinWait, nil
+	case "closewait":
+		return ConnectionStateCloseWait, nil
+	case "lastack":
+		return ConnectionStateLastAck, nil
+	case "timewait":
+		return ConnectionStateTimeWait, nil
+	case "closed":
+		return ConnectionStateClosed, nil
+	default:
+		return ConnectionState(255), fmt.Errorf("unknown connection state: %s", s)
+	}
+}
+
+// conntrack のエントリーを一意に特定する 5-tuple です。
+type ConntrackTuple struct {
+	SrcAddr  netip.Addr
+	DstAddr  netip.Addr
+	SrcPort  uint32
+	DstPort  uint32
+	Protocol protocols.TransportProtocol
+}
+
+func (t *ConntrackTuple) key() conntrackKey {
+	return conntrackKey{
+		SrcAddr: protocols.IpAddrTo16(t.SrcAddr),
+		DstAddr: protocols.IpAddrTo16(t.DstAddr),
+		// conntrack マップのキーのポート番号はネットワークバイトオーダーで格納されています。
+		SrcPort:  protocols.Ntohs(uint16(t.SrcPort)),
+		DstPort:  protocols.Ntohs(uint16(t.DstPort)),
+		Protocol: uint32(t.Protocol),
+	}
+}
+
+// 5-tuple を指定して conntrack のエントリーを削除します。
+// 削除したエントリーの数を返します。エントリーが存在しない場合は 0 を返します。
+func (l *LbBackendManager) DeleteConntrackTuple(tuple ConntrackTuple) (int, error) {
+
+	if !tuple.SrcAddr.IsValid() || !tuple.DstAddr.IsValid() {
+		return 0, fmt.Errorf("source and destination addresses are required")
+	}
+	if tuple.SrcPort > 65535 || tuple.DstPort > 65535 {
+		return 0, fmt.Errorf("invalid port: %d, %d", tuple.SrcPort, tuple.DstPort)
+	}
+	if tuple.Protocol != protocols.TransportProtocolTcp && tuple.Protocol != protocols.TransportProtocolUdp {
+		return 0, fmt.Errorf("protocol must be tcp or udp: %s", tuple.Protocol)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := tuple.key()
+	if err := l.conntrackMap.Delete(key); err != nil {
+		if errors.Is(err, ebpf.ErrKeyNotExist) {
+			delete(l.conntrack, key)
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	l.logger.Info("delete a conntrack entry", slog.Any("tuple", tuple))
+	if entry, ok := l.conntrack[key]; ok {
+		l.notifyConntrack(ConntrackEventDeleted, entry, entry.State, ConntrackDeleteReasonDeleted)
+		delete(l.conntrack, key)
+	}
+	return 1, nil
+}
+
+// 条件に一致する conntrack のエントリーを削除します。
+// 前回の同期のあとに作成されたエントリーも削除できるように、bpf マップを直接走査します。
+// 削除したエントリーの数を返します。
+func (l *LbBackendManager) DeleteConntrackEntries(filter ConntrackFilter) (int, error) {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var (
+		key   conntrackKey
+		value conntrackInfo
+		keys  []conntrackKey
+	)
+
+	monoNow, err := monotonicNow()
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	iter := l.conntrackMap.Iterate()
+	for iter.Next(&key, &value) {
+		// 前回の同期のあとの状態で判定するために、bpf マップの値からエントリーを作成します。
+		entry := newConntrackEntry(&key, &value, monoNow, now)
+		if filter.match(entry, now) {
+			keys = append(keys, key)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return 0, err
+	}
+
+	// イテレーション中に要素を削除すると走査が先頭からやり直しになる場合があるので、走査が終わってから削除します。
+	var errs []error
+	deleted := 0
+	for _, k := range keys {
+		if err := l.conntrackMap.Delete(k); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		deleted += 1
+		if entry, ok := l.conntrack[k]; ok {
+			l.notifyConntrack(ConntrackEventDeleted, entry, entry.State, ConntrackDeleteReasonDeleted)
+			delete(l.conntrack, k)
+		}
+	}
+
+	l.logger.Info("delete conntrack entries", slog.Any("filter", filter), slog.Int("deleted", deleted))
+	return deleted, errors.Join(errs...)
+}
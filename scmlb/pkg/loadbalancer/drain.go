@@ -0,0 +1,117 @@
+package loadbalancer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cilium/ebpf"
+	"golang.org/x/exp/slog"
+)
+
+// drain 中のバックエンドの状態です。
+type drainState struct {
+	// この時刻を過ぎると残っているコネクションに関わらずバックエンドを削除します。ゼロ値の場合は期限を設けません。
+	deadline time.Time
+	// バックエンドに割り当てられている conntrack のエントリーの数です。
+	// conntrack の同期ごとに更新されます。
+	remaining uint32
+}
+
+// drain 中のバックエンドの進捗です。
+type DrainStatus struct {
+	Id uint32
+	// バックエンドに残っているコネクションの数です。
+	Remaining uint32
+	// バックエンドが自動で削除される期限です。期限を設けていない場合はゼロ値です。
+	Deadline time.Time
+	// drain が完了してバックエンドが削除されたかどうかを表します。
+	Completed bool
+}
+
+// drain 中のバックエンドの進捗を取得します。
+// バックエンドが既に削除されている場合は drain が完了したものとして扱います。
+func (l *LbBackendManager) DrainStatus(id uint32) (DrainStatus, error) {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	backend, ok := l.backends[id]
+	if !ok {
+		return DrainStatus{Id: id, Completed: true}, nil
+	}
+	if backend.drain == nil {
+		return DrainStatus{}, fmt.Errorf("backend is not drained. id is %d", id)
+	}
+
+	return DrainStatus{
+		Id:        id,
+		Remaining: backend.drain.remaining,
+		Deadline:  backend.drain.deadline,
+	}, nil
+}
+
+// drain 中のバックエンドの残りのコネクション数を更新して、コネクションがなくなったか期限を過ぎたバックエンドを削除します。
+// 期限を過ぎて削除する場合は、削除したバックエンドを参照し続けないように残っている conntrack のエントリーも削除します。
+// この関数はロックを取得した状態で呼び出す必要があります。
+func (l *LbBackendManager) completeDrains(remaining map[uint32]uint32) error {
+
+	var errs []error
+
+	now := time.Now()
+	for _, b := range l.backends {
+		if b.drain == nil {
+			continue
+		}
+		b.drain.remaining = remaining[b.Id]
+
+		expired := !b.drain.deadline.IsZero() && now.After(b.drain.deadline)
+		if b.drain.remaining > 0 && !expired {
+			continue
+		}
+
+		if b.drain.remaining > 0 {
+			l.logger.Warn("drain deadline exceeded. remaining connections are discarded", slog.Int("id", int(b.Id)), slog.Int("remaining", int(b.drain.remaining)))
+			if err := l.deleteConntrackEntries(b.Id); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+
+		l.logger.Info("drain completed. delete the backend", slog.Int("id", int(b.Id)))
+		if err := l.delete(b.Id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// バックエンドに割り当てられている conntrack のエントリーの数を返します。
+// 既に終了した TimeWait と Closed のコネクションのエントリーは数えません。
+// この関数はロックを取得した状態で呼び出す必要があります。
+func (l *LbBackendManager) countConntrackEntries(backendId uint32) uint32 {
+	var n uint32
+	for _, e := range l.conntrack {
+		if e.BackendId == backendId && !e.isFinished() {
+			n += 1
+		}
+	}
+	return n
+}
+
+// バックエンドに割り当てられている conntrack のエントリーを bpf マップからも削除します。
+// この関数はロックを取得した状態で呼び出す必要があります。
+func (l *LbBackendManager) deleteConntrackEntries(backendId uint32) error {
+	for k, e := range l.conntrack {
+		if e.BackendId != backendId {
+			continue
+		}
+		if err := l.conntrackMap.Delete(k); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			return err
+		}
+		delete(l.conntrack, k)
+		l.notifyConntrack(ConntrackEventDeleted, e, e.State, ConntrackDeleteReasonDrain)
+	}
+	return nil
+}
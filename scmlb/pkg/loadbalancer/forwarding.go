@@ -0,0 +1,141 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/cilium/ebpf"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/exp/slog"
+)
+
+// バックエンドへのパケットの転送方式です。
+// bpf/include/scmlb.h の enum ForwardingMode に対応しています。
+type ForwardingMode uint32
+
+const (
+	// バックエンドではサービスの転送方式に従うことを表します。
+	ForwardingModeUnspecified ForwardingMode = ForwardingMode(0)
+	// 宛先アドレスをバックエンドのアドレスに書き換えて転送して、戻りパケットもロードバランサーを経由させる方式です。
+	ForwardingModeNat ForwardingMode = ForwardingMode(1)
+	// 宛先アドレスを VIP のまま IP-in-IP でカプセル化して転送して、バックエンドがクライアントに直接応答する方式です。
+	ForwardingModeDsrIpip ForwardingMode = ForwardingMode(2)
+	// 宛先アドレスを VIP のまま GUE でカプセル化して転送して、バックエンドがクライアントに直接応答する方式です。
+	ForwardingModeDsrGue ForwardingMode = ForwardingMode(3)
+)
+
+// 空文字列の場合は ForwardingModeUnspecified を返します。
+func ForwardingModeFromString(s string) (ForwardingMode, error) {
+	switch s {
+	case "":
+		return ForwardingModeUnspecified, nil
+	case "nat":
+		return ForwardingModeNat, nil
+	case "ipip":
+		return ForwardingModeDsrIpip, nil
+	case "gue":
+		return ForwardingModeDsrGue, nil
+	default:
+		return ForwardingMode(255), fmt.Errorf("unknown forwarding mode: %s", s)
+	}
+}
+
+func (f ForwardingMode) String() string {
+	switch f {
+	case ForwardingModeUnspecified:
+		return ""
+	case ForwardingModeNat:
+		return "nat"
+	case ForwardingModeDsrIpip:
+		return "ipip"
+	case ForwardingModeDsrGue:
+		return "gue"
+	default:
+		return fmt.Sprintf("unknown(%d)", f)
+	}
+}
+
+// パケットをカプセル化してバックエンドが直接応答する転送方式かどうかを返します。
+func (f ForwardingMode) IsDsr() bool {
+	return f == ForwardingModeDsrIpip || f == ForwardingModeDsrGue
+}
+
+// バックエンドに実際に適用される転送方式を返します。
+// バックエンドに転送方式が指定されていない場合はサービスの転送方式に従います。
+func (b *Backend) forwarding(service *Service) ForwardingMode {
+	if b.Forwarding != ForwardingModeUnspecified {
+		return b.Forwarding
+	}
+	return service.Forwarding
+}
+
+// 転送方式がサービスとバックエンドの組み合わせで利用できるかを確認します。
+// IPv6 では外側の UDP ヘッダのチェックサムを省略できないので GUE には対応していません。
+// DSR ではバックエンドのデバイスのアドレスをカプセル化の外側の送信元アドレスに利用するので、アドレスが見つからない場合は利用できません。
+func validateForwarding(mode ForwardingMode, service *Service, encapSrc netip.Addr) error {
+	switch mode {
+	case ForwardingModeNat:
+		return nil
+	case ForwardingModeDsrIpip:
+	case ForwardingModeDsrGue:
+		if service.Vip.Is6() {
+			return fmt.Errorf("gue forwarding is not supported for ipv6 service: %s", service)
+		}
+	default:
+		return fmt.Errorf("invalid forwarding mode: %s", mode)
+	}
+	if !encapSrc.IsValid() {
+		return fmt.Errorf("%s forwarding requires an address on the backend device to encapsulate packets", mode)
+	}
+	return nil
+}
+
+// DSR でカプセル化するときの外側の送信元アドレスとして、バックエンドのデバイスに設定されているアドレスを返します。
+// リンクローカルアドレスは経路上で転送できないので利用しません。
+// 見つからない場合は無効なアドレスを返します。
+func encapSourceAddr(iface netlink.Link, is4 bool) (netip.Addr, error) {
+	family := netlink.FAMILY_V6
+	if is4 {
+		family = netlink.FAMILY_V4
+	}
+	addrs, err := netlink.AddrList(iface, family)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	for _, a := range addrs {
+		if !a.IP.IsGlobalUnicast() {
+			continue
+		}
+		addr, ok := netip.AddrFromSlice(a.IP)
+		if !ok {
+			continue
+		}
+		return addr.Unmap(), nil
+	}
+	return netip.Addr{}, nil
+}
+
+// サービスの転送方式に従うバックエンドの backend_info マップ上の転送方式を更新します。
+// この関数はロックを取得した状態で呼び出す必要があります。
+func (l *LbBackendManager) updateBackendInfoForwarding(service *Service) error {
+	for _, b := range l.backends {
+		if b.ServiceId != service.Id || b.Forwarding != ForwardingModeUnspecified {
+			continue
+		}
+		var info backendInfo
+		if err := l.backendInfoMap.Lookup(b.Id, &info); err != nil {
+			l.logger.Error("failed to lookup backend", err, slog.Int("id", int(b.Id)))
+			return err
+		}
+		if info.Forwarding == uint32(service.Forwarding) {
+			continue
+		}
+		info.Forwarding = uint32(service.Forwarding)
+
+		l.logger.Debug("update backend forwarding mode in backend_info map", slog.Int("id", int(b.Id)), slog.String("forwarding", service.Forwarding.String()))
+		if err := l.backendInfoMap.Update(b.Id, info, ebpf.UpdateExist); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package loadbalancer
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cilium/ebpf"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
+	"golang.org/x/exp/slog"
+)
+
+// conntrack の GC の設定です。
+// 最後にパケットを処理してからの経過時間がコネクションの状態ごとのタイムアウトを超えたエントリーを削除します。
+// タイムアウトが 0 の状態のエントリーは削除しません。
+type GCConfig struct {
+	// TCP のエントリーのタイムアウトによる削除を有効にするかどうかを表します。
+	// 無効の場合も UDP のエントリーは Udp、TCP で状態が TimeWait のエントリーは TcpTimeWait のタイムアウトで削除され、
+	// TCP で状態が Closed のエントリーは削除されます。
+	Enabled bool
+	// TCP で状態が SynSent, SynRecv のエントリーのタイムアウトです。
+	TcpOpening time.Duration
+	// TCP で状態が Established のエントリーのタイムアウトです。
+	TcpEstablished time.Duration
+	// TCP で状態が FinWait, CloseWait, LastAck のエントリーのタイムアウトです。
+	TcpClosing time.Duration
+	// TCP で状態が TimeWait のエントリーのタイムアウトです。
+	TcpTimeWait time.Duration
+	// UDP のエントリーのタイムアウトです。
+	Udp time.Duration
+}
+
+// GC で削除した conntrack のエントリーの数をコネクションの状態ごとに数えたカウンターです。
+type ConntrackEvictions struct {
+	// TCP のエントリーを削除した数です。インデックスはコネクションの状態です。
+	Tcp [ConnectionStateClosed + 1]uint64
+	Udp uint64
+}
+
+// TCP のコネクションの状態に対応するタイムアウトを返します。
+// 0 を返す場合はタイムアウトによる削除の対象ではありません。
+func (c *GCConfig) TcpTimeout(state ConnectionState) time.Duration {
+	// TimeWait のエントリーは正常に終了したコネクションのものなので、GC が無効でも削除します。
+	if !c.Enabled && state != ConnectionStateTimeWait {
+		return 0
+	}
+	switch state {
+	case ConnectionStateSynSent, ConnectionStateSynRecv:
+		return c.TcpOpening
+	case ConnectionStateEstablished:
+		return c.TcpEstablished
+	case ConnectionStateFinWait, ConnectionStateCloseWait, ConnectionStateLastAck:
+		return c.TcpClosing
+	case ConnectionStateTimeWait:
+		return c.TcpTimeWait
+	default:
+		return 0
+	}
+}
+
+// エントリーの状態に対応するタイムアウトを返します。
+// 0 を返す場合はタイムアウトによる削除の対象ではありません。
+func (c *GCConfig) timeout(entry *ConntrackEntry) time.Duration {
+	if entry.Protocol == protocols.TransportProtocolUdp {
+		return c.Udp
+	}
+	return c.TcpTimeout(entry.State)
+}
+
+// 削除したエントリーの状態に対応するカウンターをカウントアップします。
+func (e *ConntrackEvictions) count(entry *ConntrackEntry) {
+	if entry.Protocol == protocols.TransportProtocolUdp {
+		e.Udp += 1
+		return
+	}
+	if int(entry.State) < len(e.Tcp) {
+		e.Tcp[entry.State] += 1
+	}
+}
+
+// アイドルタイムアウトを過ぎた conntrack のエントリーを削除します。
+// 半開きのままのコネクションや、終了処理が途中で途切れたコネクションのエントリーもここで削除されます。
+// GC 有効化フラグが立っていない場合も、タイムアウトが 0 ではない状態のエントリーは削除します。
+func (l *LbBackendManager) gc() error {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var errs []error
+
+	now := time.Now()
+	for key, entry := range l.conntrack {
+		// peer から同期されたエントリーは peer がパケットを処理しているので、peer と同期している間は peer からの削除に従います。
+		if entry.Replicated && l.replicationPeers > 0 {
+			continue
+		}
+		timeout := l.gcConfig.timeout(entry)
+		if timeout == 0 || now.Sub(entry.Timestamp) <= timeout {
+			continue
+		}
+
+		l.logger.Debug("evict an idle conntrack entry", slog.Any("entry", entry), slog.Duration("timeout", timeout))
+		// XDP プログラムから削除されることはないので存在しない場合はそのまま取り除きます。
+		if err := l.conntrackMap.Delete(key); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		delete(l.conntrack, key)
+		l.evictions.count(entry)
+		l.notifyConntrack(ConntrackEventDeleted, entry, entry.State, ConntrackDeleteReasonTimeout)
+	}
+
+	return errors.Join(errs...)
+}
+
+// GC の設定と、これまでに削除した conntrack のエントリーの数を取得します。
+func (l *LbBackendManager) GetConntrackEvictions() (GCConfig, ConntrackEvictions) {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.gcConfig, l.evictions
+}
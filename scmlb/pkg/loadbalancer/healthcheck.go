@@ -0,0 +1,293 @@
+package loadbalancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+// ヘルスチェックの実行間隔やしきい値の設定です。
+// scmlbd start のフラグから与えられます。
+type HealthCheckConfig struct {
+	// ヘルスチェックを実行する間隔です。
+	Interval time.Duration
+	// 一回のヘルスチェックのタイムアウトです。
+	Timeout time.Duration
+	// Unhealthy から Healthy に戻すために必要な連続成功回数です。
+	Rise uint32
+	// Healthy から Unhealthy にするために必要な連続失敗回数です。
+	Fall uint32
+}
+
+type HealthState uint32
+
+const (
+	// ヘルスチェックが設定されていないか、まだ結果が出ていない状態です。
+	HealthStateUnknown   HealthState = HealthState(0)
+	HealthStateHealthy   HealthState = HealthState(1)
+	HealthStateUnhealthy HealthState = HealthState(2)
+)
+
+func (h HealthState) String() string {
+	switch h {
+	case HealthStateUnknown:
+		return "Unknown"
+	case HealthStateHealthy:
+		return "Healthy"
+	case HealthStateUnhealthy:
+		return "Unhealthy"
+	default:
+		return "Invalid"
+	}
+}
+
+// バックエンドごとのヘルスチェックの連続成功・失敗回数を保持する構造体です。
+type healthCounter struct {
+	success uint32
+	failure uint32
+}
+
+type healthChecker interface {
+	check(ctx context.Context) error
+}
+
+type httpChecker struct {
+	client *http.Client
+	url    string
+}
+
+// HTTP GET を送信して 2xx か 3xx のステータスコードが返ってくれば成功とみなします。
+func (h *httpChecker) check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 400 {
+		return fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	return nil
+}
+
+type tcpChecker struct {
+	addr string
+}
+
+// TCP のコネクションが確立できれば成功とみなします。
+func (t *tcpChecker) check(ctx context.Context) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", t.addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+type udpChecker struct {
+	addr string
+}
+
+// UDP はコネクションがないので、空のデータグラムを送信して ICMP Port Unreachable が返ってこなければ成功とみなします。
+// ICMP エラーを受信すると Read が ECONNREFUSED で失敗します。
+func (u *udpChecker) check(ctx context.Context) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", u.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if ok {
+		conn.SetDeadline(deadline)
+	}
+	if _, err := conn.Write([]byte{}); err != nil {
+		return err
+	}
+	buf := make([]byte, 1)
+	if _, err := conn.Read(buf); err != nil {
+		var ne net.Error
+		if errors.As(err, &ne) && ne.Timeout() {
+			// 応答がなくてもエラーが返ってこなければ待ち受けているとみなします。
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// Backend.HealthCheck の文字列からヘルスチェックの実装を作成します。
+// 以下の形式を受け付けます。
+// - http://10.0.5.2:8080/health
+// - tcp://10.0.5.2:8080
+// - udp://10.0.5.2:9090
+// - /health (バックエンドのアドレスに対して 80 番ポートで HTTP GET します)
+// 空文字列の場合はヘルスチェックを行いません。
+func newHealthChecker(target string, addr netip.Addr, timeout time.Duration) (healthChecker, error) {
+	if target == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(target, "/") {
+		target = fmt.Sprintf("http://%s%s", netip.AddrPortFrom(addr, 80).String(), target)
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return &httpChecker{
+			client: &http.Client{
+				Timeout: timeout,
+				// リダイレクト先には追従せずにステータスコードのみで判定します。
+				CheckRedirect: func(req *http.Request, via []*http.Request) error {
+					return http.ErrUseLastResponse
+				},
+			},
+			url: u.String(),
+		}, nil
+	case "tcp":
+		if u.Port() == "" {
+			return nil, fmt.Errorf("port is required for tcp health check: %s", target)
+		}
+		return &tcpChecker{addr: u.Host}, nil
+	case "udp":
+		if u.Port() == "" {
+			return nil, fmt.Errorf("port is required for udp health check: %s", target)
+		}
+		return &udpChecker{addr: u.Host}, nil
+	default:
+		return nil, fmt.Errorf("unsupported health check scheme: %s", u.Scheme)
+	}
+}
+
+// ヘルスチェックのループです。
+// interval ごとに登録されているバックエンドすべてに対してヘルスチェックを実行して、
+// rise/fall のしきい値を超えたバックエンドの状態を遷移させます。
+func (l *LbBackendManager) runHealthCheck(ctx context.Context) {
+
+	l.logger.InfoCtx(ctx, "starting health check loop", slog.Duration("interval", l.hcConfig.Interval))
+
+	ticker := time.NewTicker(l.hcConfig.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := l.healthCheck(ctx); err != nil {
+				l.logger.ErrorCtx(ctx, "failed to apply health check results", err)
+			}
+		case <-ctx.Done():
+			l.logger.InfoCtx(ctx, "stopping health check loop")
+			return
+		}
+	}
+}
+
+func (l *LbBackendManager) healthCheck(ctx context.Context) error {
+
+	l.mu.Lock()
+	checkers := make(map[uint32]healthChecker, len(l.backends))
+	for id, b := range l.backends {
+		if b.checker != nil {
+			checkers[id] = b.checker
+		}
+	}
+	l.mu.Unlock()
+
+	// 各バックエンドのヘルスチェックは並行に実行します。
+	results := make(map[uint32]error, len(checkers))
+	resultMu := &sync.Mutex{}
+	wg := &sync.WaitGroup{}
+	for id, c := range checkers {
+		wg.Add(1)
+		go func(id uint32, c healthChecker) {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, l.hcConfig.Timeout)
+			defer cancel()
+			err := c.check(cctx)
+			resultMu.Lock()
+			results[id] = err
+			resultMu.Unlock()
+		}(id, c)
+	}
+	wg.Wait()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	changed := false
+	for id, err := range results {
+		b, ok := l.backends[id]
+		if !ok {
+			// ヘルスチェック中に削除されたバックエンドです。
+			continue
+		}
+		if l.updateHealth(b, err) {
+			changed = true
+		}
+	}
+	if !changed && !l.hcDirty {
+		return nil
+	}
+
+	// 状態が変化したバックエンドがあれば backend_info と rr_table, maglev_table を更新します。
+	// 前回の反映に失敗していた場合は状態が変化していなくても再度反映します。
+	var errs []error
+	for _, b := range l.backends {
+		if err := l.updateBackendInfoStatus(b); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := l.ajustAllSchedulingTables(); err != nil {
+		errs = append(errs, err)
+	}
+	l.hcDirty = len(errs) > 0
+	return errors.Join(errs...)
+}
+
+// ヘルスチェックの結果を反映してバックエンドの Health を遷移させます。
+// 状態が変化したときに true を返します。
+func (l *LbBackendManager) updateHealth(b *Backend, err error) bool {
+	if err != nil {
+		b.counter.success = 0
+		b.counter.failure += 1
+		l.logger.Debug("health check failed", slog.Int("id", int(b.Id)), slog.String("target", b.HealthCheck), slog.Int("failure", int(b.counter.failure)), slog.String("error", err.Error()))
+		if b.Health != HealthStateUnhealthy && b.counter.failure >= l.hcConfig.Fall {
+			l.logger.Warn("backend becomes unhealthy", slog.Int("id", int(b.Id)), slog.String("name", b.Name), slog.String("target", b.HealthCheck))
+			b.Health = HealthStateUnhealthy
+			return true
+		}
+		return false
+	}
+
+	b.counter.failure = 0
+	b.counter.success += 1
+	switch b.Health {
+	case HealthStateUnknown:
+		// 初回の成功ではすぐに Healthy にします。
+		b.Health = HealthStateHealthy
+		return false
+	case HealthStateUnhealthy:
+		if b.counter.success >= l.hcConfig.Rise {
+			l.logger.Info("backend becomes healthy", slog.Int("id", int(b.Id)), slog.String("name", b.Name), slog.String("target", b.HealthCheck))
+			b.Health = HealthStateHealthy
+			return true
+		}
+	}
+	return false
+}
@@ -4,19 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
+	"math"
 	"net"
 	"net/netip"
-	"os"
-	"os/exec"
 	"sort"
-	"strings"
 	"sync"
 	"time"
 
 	"github.com/cilium/ebpf"
 	"github.com/cilium/ebpf/link"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/loader"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/logger"
 	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
 	"github.com/vishvananda/netlink"
@@ -24,27 +22,47 @@ import (
 )
 
 type LbBackendManager struct {
-	mu                      *sync.Mutex
-	logger                  *slog.Logger
-	upstramInfo             Upstream
-	vip                     netip.Addr
-	entrypoint              *ebpf.Program
-	backends                map[uint32]*Backend
-	conntrack               map[conntrackKey]*ConntrackEntry
-	interval                time.Duration
-	gcEnabled               bool
-	gcTime                  time.Duration
-	nextId                  uint32
-	registeredBackendLength uint32
-	redirectMap             *ebpf.Map
-	backendInfoMap          *ebpf.Map
-	backendIfindexMap       *ebpf.Map
-	upstreamMap             *ebpf.Map
-	conntrackMap            *ebpf.Map
-	rrTableMap              *ebpf.Map
-}
-
-func New(vip netip.Addr, upstreamIface string, entry *ebpf.Program, redirectMap, backendInfoMap, backendIfindexMap, upstreamMap *ebpf.Map, conntrack, rrTableMap *ebpf.Map, gcEnabled bool, gcTime time.Duration) (*LbBackendManager, error) {
+	mu          *sync.Mutex
+	logger      *slog.Logger
+	upstramInfo Upstream
+	entrypoint  *ebpf.Program
+	services    map[uint32]*Service
+	backends    map[uint32]*Backend
+	ifaces      map[int]*backendIface
+	conntrack   map[conntrackKey]*ConntrackEntry
+	watchers    map[*conntrackWatcher]struct{}
+	// conntrack のエントリーを同期している peer の数です。
+	replicationPeers int
+	interval         time.Duration
+	gcConfig         GCConfig
+	evictions        ConntrackEvictions
+	hcConfig         HealthCheckConfig
+	// ヘルスチェックの結果の反映に失敗して、次のヘルスチェックで再度反映する必要があることを表します。
+	hcDirty           bool
+	nextId            uint32
+	redirectMap       *ebpf.Map
+	backendInfoMap    *ebpf.Map
+	backendIfindexMap *ebpf.Map
+	upstreamMap       *ebpf.Map
+	conntrackMap      *ebpf.Map
+	rrTableMap        *ebpf.Map
+	maglevTableMap    *ebpf.Map
+	servicesMap       *ebpf.Map
+	reverseServiceMap *ebpf.Map
+	affinityMap       *ebpf.Map
+	backendMetaMap    *ebpf.Map
+	// バックエンドのデバイスにアタッチした link をピン留めするかどうかを表します。
+	persist bool
+}
+
+// XDP プログラムをアタッチしたバックエンドのデバイスです。
+// 同じデバイスの先に複数のバックエンドが存在する場合があるので、参照しているバックエンドの数を数えて最後のバックエンドが削除されたときにデタッチします。
+type backendIface struct {
+	link link.Link
+	refs int
+}
+
+func New(upstreamIface string, entry *ebpf.Program, redirectMap, backendInfoMap, backendIfindexMap, upstreamMap *ebpf.Map, conntrack, rrTableMap, maglevTableMap, servicesMap, reverseServiceMap, affinityMap, backendMetaMap *ebpf.Map, gcConfig GCConfig, hcConfig HealthCheckConfig, persist bool) (*LbBackendManager, error) {
 	out, err := logger.Output(constants.LogOutput)
 	if err != nil {
 		return nil, err
@@ -58,44 +76,69 @@ func New(vip netip.Addr, upstreamIface string, entry *ebpf.Program, redirectMap,
 	}
 
 	info := Upstream{
-		IpAddr:  vip,
 		Index:   uint32(upstreamLink.Attrs().Index),
 		MacAddr: upstreamLink.Attrs().HardwareAddr,
 	}
 
-	logger.Debug("setup loadbalcner upstream information", slog.String("vip", info.IpAddr.String()), slog.String("iface", upstreamLink.Attrs().Name), slog.String("mac addr", info.MacAddr.String()))
+	logger.Debug("setup loadbalcner upstream information", slog.String("iface", upstreamLink.Attrs().Name), slog.String("mac addr", info.MacAddr.String()))
 
 	return &LbBackendManager{
-		logger:                  logger,
-		mu:                      &sync.Mutex{},
-		vip:                     vip,
-		upstramInfo:             info,
-		entrypoint:              entry,
-		backends:                make(map[uint32]*Backend),
-		conntrack:               make(map[conntrackKey]*ConntrackEntry),
-		interval:                time.Second,
-		gcEnabled:               gcEnabled,
-		gcTime:                  gcTime,
-		nextId:                  1,
-		registeredBackendLength: 0,
-		redirectMap:             redirectMap,
-		backendInfoMap:          backendInfoMap,
-		backendIfindexMap:       backendIfindexMap,
-		upstreamMap:             upstreamMap,
-		conntrackMap:            conntrack,
-		rrTableMap:              rrTableMap,
+		logger:            logger,
+		mu:                &sync.Mutex{},
+		upstramInfo:       info,
+		entrypoint:        entry,
+		services:          make(map[uint32]*Service),
+		backends:          make(map[uint32]*Backend),
+		ifaces:            make(map[int]*backendIface),
+		conntrack:         make(map[conntrackKey]*ConntrackEntry),
+		watchers:          make(map[*conntrackWatcher]struct{}),
+		interval:          time.Second,
+		gcConfig:          gcConfig,
+		hcConfig:          hcConfig,
+		nextId:            1,
+		redirectMap:       redirectMap,
+		backendInfoMap:    backendInfoMap,
+		backendIfindexMap: backendIfindexMap,
+		upstreamMap:       upstreamMap,
+		conntrackMap:      conntrack,
+		rrTableMap:        rrTableMap,
+		maglevTableMap:    maglevTableMap,
+		servicesMap:       servicesMap,
+		reverseServiceMap: reverseServiceMap,
+		affinityMap:       affinityMap,
+		backendMetaMap:    backendMetaMap,
+		persist:           persist,
 	}, nil
 }
 
 type Backend struct {
-	Id          uint32
-	Name        string
-	Address     netip.Addr
+	Id uint32
+	// バックエンドが所属するサービスの id です。
+	ServiceId uint32
+	Name      string
+	Address   netip.Addr
+	// バックエンドへのパケットの宛先 MAC アドレスと送出するデバイスです。
+	// Set で指定されていない場合は netlink で経路と近隣テーブルを参照して解決します。
 	MacAddress  net.HardwareAddr
 	Iface       netlink.Link
 	Status      BackendStatus
 	HealthCheck string
-	finalizer   func() error
+	Health      HealthState
+	// 重み付きラウンドロビンでの重みです。rr_table にはこの値に比例した数だけ格納されます。
+	Weight uint32
+	// このバックエンドに割り当てられているアクティブなコネクションの数です。
+	// conntrack の同期ごとに更新されます。
+	ActiveConnections uint32
+	// バックエンドへの転送方式です。ForwardingModeUnspecified の場合はサービスの転送方式に従います。
+	// Get では実際に適用されている転送方式を返します。
+	Forwarding ForwardingMode
+	// DSR でカプセル化するときの外側の送信元アドレスです。
+	encapSrc netip.Addr
+	// drain 中のバックエンドの状態です。drain されていない場合は nil です。
+	drain     *drainState
+	finalizer func() error
+	checker   healthChecker
+	counter   healthCounter
 }
 
 type BackendStatus uint32
@@ -122,17 +165,17 @@ type backendInfo struct {
 	Satus      uint32
 	SrcMacAddr [6]uint8
 	DstMacAddr [6]uint8
-	DstIpAddr  uint32
+	DstIpAddr  [16]byte
+	SrcIpAddr  [16]byte
+	Forwarding uint32
 }
 
 type Upstream struct {
-	IpAddr  netip.Addr
 	Index   uint32
 	MacAddr net.HardwareAddr
 }
 
 type upstreamInfo struct {
-	IpAddr  uint32
 	IfIndex uint16
 	MacAddr [6]uint8
 }
@@ -141,7 +184,6 @@ func (l *LbBackendManager) Run(ctx context.Context) error {
 
 	// upstream の情報を bpf マップに格納して XDP プログラム側から読み取れるようにします。
 	info := upstreamInfo{
-		IpAddr:  protocols.IpAddrToU32Le(l.upstramInfo.IpAddr),
 		IfIndex: uint16(l.upstramInfo.Index),
 		MacAddr: [6]uint8(l.upstramInfo.MacAddr),
 	}
@@ -156,6 +198,9 @@ func (l *LbBackendManager) Run(ctx context.Context) error {
 		return err
 	}
 
+	// バックエンドのヘルスチェックを別の goroutine で開始します。
+	go l.runHealthCheck(ctx)
+
 	l.logger.InfoCtx(ctx, "starting conntrack loop")
 
 	ticker := time.NewTicker(l.interval)
@@ -177,6 +222,9 @@ func (l *LbBackendManager) Run(ctx context.Context) error {
 
 func (l *LbBackendManager) sync() error {
 
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	iter := l.conntrackMap.Iterate()
 
 	var (
@@ -185,44 +233,47 @@ func (l *LbBackendManager) sync() error {
 		errs  []error = []error{}
 	)
 
+	monoNow, err := monotonicNow()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
 	gcEtnries := make([]conntrackKey, 0)
+	// バックエンドごとのアクティブなコネクションの数です。
+	active := make(map[uint32]uint32)
+	// バックエンドごとの終了していないコネクションの conntrack のエントリーの数です。
+	// drain の完了の判定に使うので、drain.go の countConntrackEntries と同じく TimeWait のエントリーは数えません。
+	remaining := make(map[uint32]uint32)
+	// bpf マップに存在したエントリーです。
+	seen := make(map[conntrackKey]struct{})
 
 	for iter.Next(&key, &value) {
 		l.logger.Debug("iterate conntrack entries", slog.Any("key", key), slog.Any("value", value))
-
-		srcAddr, err := protocols.IpAddrFromLe(key.SrcAddr)
-		if err != nil {
-			errs = append(errs, err)
-		}
-		dstAddr, err := protocols.IpAddrFromLe(key.DstAddr)
-		if err != nil {
-			errs = append(errs, err)
-		}
+		seen[key] = struct{}{}
 
 		entry, ok := l.conntrack[key]
 		if !ok {
-			entry := &ConntrackEntry{
-				SrcAddr:   srcAddr,
-				DstAddr:   dstAddr,
-				SrcPort:   uint32(protocols.Ntohs(key.SrcPort)),
-				DstPort:   uint32(protocols.Ntohs(key.DstPort)),
-				Protocol:  protocols.TransportProtocol(key.Protocol),
-				State:     ConnectionState(value.Status),
-				BackendId: value.Id,
-				Counter:   value.Counter,
-				Timestamp: time.Now(),
-			}
+			entry := newConntrackEntry(&key, &value, monoNow, now)
 			l.conntrack[key] = entry
+			l.notifyConntrack(ConntrackEventCreated, entry, entry.State, "")
+			if !entry.isFinished() {
+				remaining[entry.BackendId] += 1
+			}
+			if entry.isActive() {
+				active[entry.BackendId] += 1
+			}
 			continue
 		}
-		entry.State = ConnectionState(value.Status)
-		if entry.Counter < value.Counter {
-			entry.Timestamp = time.Now()
+		prevState := entry.State
+		entry.update(&value, monoNow, now)
+		if entry.State != prevState {
+			l.notifyConntrack(ConntrackEventUpdated, entry, prevState, "")
 		}
-		entry.Counter = value.Counter
 
 		// GC 対象のエントリを一時的に保存します。
-		// GC の対象となるのは TCP で 状態が CLosed なものと、UDP で最新のパケットがやり取りされてから gcTime 以上の時間が経っているエントリーです。
+		// ここで削除するのは TCP で状態が Closed のエントリーです。
+		// TimeWait のエントリーを含めて、アイドルタイムアウトを過ぎたエントリーは gc() で削除します。
 		if entry.State == ConnectionStateClosed {
 			newKey := conntrackKey{
 				SrcAddr:  key.SrcAddr,
@@ -232,28 +283,55 @@ func (l *LbBackendManager) sync() error {
 				Protocol: key.Protocol,
 			}
 			gcEtnries = append(gcEtnries, newKey)
-		} else if entry.Protocol == protocols.TransportProtocolUdp && time.Since(entry.Timestamp) > l.gcTime {
-			newKey := conntrackKey{
-				SrcAddr:  key.SrcAddr,
-				DstAddr:  key.DstAddr,
-				SrcPort:  key.SrcPort,
-				DstPort:  key.DstPort,
-				Protocol: key.Protocol,
+		} else {
+			if !entry.isFinished() {
+				remaining[entry.BackendId] += 1
 			}
-			gcEtnries = append(gcEtnries, newKey)
+			if entry.isActive() {
+				active[entry.BackendId] += 1
+			}
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		errs = append(errs, err)
+	} else {
+		// bpf マップから消えたエントリーは、LRU のマップが溢れてカーネルが削除したものなので取り除きます。
+		for k, entry := range l.conntrack {
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			l.notifyConntrack(ConntrackEventDeleted, entry, entry.State, ConntrackDeleteReasonEvicted)
+			delete(l.conntrack, k)
+		}
+		// LRU ではないマップが溢れると新しいコネクションを登録できないので警告します。
+		if max := l.conntrackMap.MaxEntries(); len(seen) >= int(max) && l.conntrackMap.Type() != ebpf.LRUHash {
+			l.logger.Warn("conntrack map is full. new connections are not load balanced", slog.Int("max entries", int(max)))
 		}
 	}
 
+	if err := l.updateActiveConnections(active); err != nil {
+		errs = append(errs, err)
+	}
+
 	// GC の対象となったエントリーを削除して bpf マップからも削除します。
 	for _, gcEntry := range gcEtnries {
 		err := l.conntrackMap.Delete(gcEntry)
 		if err != nil {
 			errs = append(errs, err)
 		} else {
+			entry := l.conntrack[gcEntry]
+			l.evictions.count(entry)
+			l.notifyConntrack(ConntrackEventDeleted, entry, entry.State, ConntrackDeleteReasonClosed)
 			delete(l.conntrack, gcEntry)
 		}
 	}
 
+	// コネクションがなくなったか期限を過ぎた drain 中のバックエンドを削除します。
+	if err := l.completeDrains(remaining); err != nil {
+		errs = append(errs, err)
+	}
+
 	if len(errs) > 0 {
 		return errors.Join(errs...)
 	}
@@ -261,37 +339,116 @@ func (l *LbBackendManager) sync() error {
 	return nil
 }
 
-func (l *LbBackendManager) gc() error {
-	// GC 有効化フラグが立っていない場合はなにもしません。
-	if !l.gcEnabled {
-		return nil
+// バックエンドごとのアクティブなコネクションの数を更新します。
+// 最小コネクション数方式のサービスでは、コネクション数が変化していれば rr_table を再構成します。
+// この関数はロックを取得した状態で呼び出す必要があります。
+func (l *LbBackendManager) updateActiveConnections(active map[uint32]uint32) error {
+	changed := make(map[uint32]bool)
+	for _, b := range l.backends {
+		if b.ActiveConnections == active[b.Id] {
+			continue
+		}
+		b.ActiveConnections = active[b.Id]
+		changed[b.ServiceId] = true
 	}
-	return nil
+
+	var errs []error
+	for id := range changed {
+		s, ok := l.services[id]
+		if !ok || s.Scheduler != SchedulerLeastConnection {
+			continue
+		}
+		if err := l.ajustRrTable(s); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // ロードバランサーのバックエンドを追加します。
 func (l *LbBackendManager) Set(backend *Backend) error {
 
-	if err := learnMacAddr(backend.Address); err != nil {
+	// ヘルスチェックの対象の形式が不正な場合はバックエンドを登録する前にエラーを返します。
+	checker, err := newHealthChecker(backend.HealthCheck, backend.Address, l.hcConfig.Timeout)
+	if err != nil {
 		return err
 	}
 
-	entry, err := getBackendDeviceInfo(backend.Address)
+	// 名前とヘルスチェックの対象は backend_meta マップに保存するので、格納できる長さに制限します。
+	if len(backend.Name) > constants.BACKEND_NAME_MAX_SIZE {
+		return fmt.Errorf("backend name must be at most %d bytes", constants.BACKEND_NAME_MAX_SIZE)
+	}
+	if len(backend.HealthCheck) > constants.BACKEND_HEALTHCHECK_MAX_SIZE {
+		return fmt.Errorf("healthcheck target must be at most %d bytes", constants.BACKEND_HEALTHCHECK_MAX_SIZE)
+	}
+
+	// 重みが指定されていない場合は 1 とします。
+	if backend.Weight == 0 {
+		backend.Weight = 1
+	}
+
+	// バックエンドへのパケットを送出するデバイスと MAC アドレスを解決します。
+	// Iface と MacAddress があらかじめ指定されている場合はその値を利用します。
+	entry, err := resolveBackendNeigh(backend.Address, backend.Iface, backend.MacAddress)
 	if err != nil {
 		return err
 	}
+	iface := entry.link
 
-	iface, err := netlink.LinkByName(entry.device)
+	// サービスの転送方式があとから DSR に変更される場合に備えて、転送方式に関わらずカプセル化の送信元アドレスを取得しておきます。
+	encapSrc, err := encapSourceAddr(iface, backend.Address.Is4())
 	if err != nil {
 		return err
 	}
 
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	service, ok := l.services[backend.ServiceId]
+	if !ok {
+		return fmt.Errorf("service is not found. id is %d", backend.ServiceId)
+	}
+
+	// XDP ではアドレスファミリーの変換は行わないので、VIP とバックエンドのアドレスファミリーは揃っている必要があります。
+	if backend.Address.Is4() != service.Vip.Is4() {
+		return fmt.Errorf("address family of backend %s does not match the service vip %s", backend.Address, service.Vip)
+	}
+
+	forwarding := backend.forwarding(service)
+	if err := validateForwarding(forwarding, service, encapSrc); err != nil {
+		return err
+	}
+
+	// 戻りパケットからサービスを一意に特定できるように、同じアドレスのバックエンドを同じポートとプロトコルのサービスに重複して登録することはできません。
+	reverseKey := newServiceKey(backend.Address, service.Port, service.Protocol)
+	for _, b := range l.backends {
+		if b.Address != backend.Address {
+			continue
+		}
+		s := l.services[b.ServiceId]
+		if newServiceKey(b.Address, s.Port, s.Protocol) == reverseKey {
+			return fmt.Errorf("backend %s is already registered to service %d with the same port and protocol", backend.Address, s.Id)
+		}
+	}
+
+	// rr_table に収まらない重みの場合はバックエンドを登録する前にエラーを返します。
+	if err := l.validateWeight(backend); err != nil {
+		return err
+	}
+
+	// backend_info マップが溢れる場合は登録できません。
+	if max := l.backendInfoMap.MaxEntries(); len(l.backends) >= int(max) {
+		return fmt.Errorf("the number of backends reaches the capacity of backend_info map: %d. increase --backend-max-entries of scmlbd", max)
+	}
+
 	backend.Id = l.nextId
-	l.nextId += 1
 
 	backend.MacAddress = entry.macAddr
 	backend.Iface = iface
 	backend.Status = BackendStatusAvailable
+	backend.Health = HealthStateUnknown
+	backend.checker = checker
+	backend.encapSrc = encapSrc
 
 	// バックエンドの情報を各種マップに登録します。
 	ifindex := uint32(backend.Iface.Attrs().Index)
@@ -299,9 +456,13 @@ func (l *LbBackendManager) Set(backend *Backend) error {
 		Id:         backend.Id,
 		Index:      ifindex,
 		Satus:      uint32(0),
-		DstIpAddr:  protocols.IpAddrToU32Le(backend.Address),
+		DstIpAddr:  protocols.IpAddrTo16(backend.Address),
 		SrcMacAddr: [6]uint8(backend.Iface.Attrs().HardwareAddr),
 		DstMacAddr: [6]uint8(backend.MacAddress),
+		Forwarding: uint32(forwarding),
+	}
+	if encapSrc.IsValid() {
+		info.SrcIpAddr = protocols.IpAddrTo16(encapSrc)
 	}
 
 	l.logger.Debug("insert backend information to backend_info map", slog.Int("id", int(info.Id)), slog.Any("info", info))
@@ -309,53 +470,222 @@ func (l *LbBackendManager) Set(backend *Backend) error {
 		return err
 	}
 
-	l.logger.Debug("infex backend ifindex and id to backend_ifindex map", slog.Int("ifindex", int(ifindex)), slog.Int("id", int(info.Id)))
-	if err := l.backendIfindexMap.Update(ifindex, info.Id, ebpf.UpdateAny); err != nil {
+	// 途中で失敗した場合は、同じバックエンドを登録し直せるようにそれまでにマップに登録した内容を取り消します。
+	if err := l.saveBackendMeta(backend); err != nil {
+		l.deleteBackendMaps(backend.Id, reverseKey)
 		return err
 	}
 
-	l.logger.Debug("insert backend interface index to backend redirect device map", slog.Any("info", info))
-	if err := l.redirectMap.Update(ifindex, ifindex, ebpf.UpdateAny); err != nil {
+	l.logger.Debug("insert backend address and service vip to reverse_service map", slog.Int("id", int(info.Id)), slog.String("service", service.String()))
+	if err := l.reverseServiceMap.Update(reverseKey, protocols.IpAddrTo16(service.Vip), ebpf.UpdateAny); err != nil {
+		l.deleteBackendMaps(backend.Id, reverseKey)
 		return err
 	}
 
-	l.logger.Debug("insert new backend id to rr_table.", slog.Int("index", int(l.registeredBackendLength)), slog.Int("backend id", int(backend.Id)))
-	if err := l.rrTableMap.Update(l.registeredBackendLength, backend.Id, ebpf.UpdateAny); err != nil {
+	if err := l.attachBackendIface(iface, backend.Id); err != nil {
+		l.deleteBackendMaps(backend.Id, reverseKey)
 		return err
 	}
 
-	// 登録して RrTable の格納数が増えたらインクリメントします。
-	l.registeredBackendLength += 1
+	backend.finalizer = func() error {
+		return l.detachBackendIface(iface)
+	}
 
-	// 指定されたデバイスに XDP プログラムをアタッチします
-	l.logger.Info("attach xdp entrypoint program", slog.String("device", backend.Iface.Attrs().Name), slog.Any("backend", backend))
-	ll, err := link.AttachXDP(link.XDPOptions{
-		Program: l.entrypoint,
-		// アタッチしたいインターフェースのインデックスを指定します
-		Interface: iface.Attrs().Index,
-		// ここでは Generic XDP を利用するので XDPGenericMode を指定しています
-		// Native XDP で動かしたい場合は XDPDriverMode を指定します。
-		Flags: link.XDPGenericMode,
-	})
-	if err != nil {
+	l.logger.Debug("register a backend", slog.Any("backend", backend))
+	l.backends[backend.Id] = backend
+
+	// 重みを考慮して rr_table と maglev_table を再構成します。
+	if err := l.ajustSchedulingTables(service); err != nil {
+		l.logger.Error("failed to ajust scheduling table maps", err, slog.Int("id", int(backend.Id)))
+		// 一部の要素は新しいバックエンドに切り替わっているので、バックエンドを除いたテーブルに戻してから登録を取り消します。
+		delete(l.backends, backend.Id)
+		if err := l.ajustSchedulingTables(service); err != nil {
+			l.logger.Error("failed to revert scheduling table maps", err, slog.Int("id", int(backend.Id)))
+		}
+		if err := l.detachBackendIface(iface); err != nil {
+			l.logger.Error("failed to detach XDP program", err, slog.Int("id", int(backend.Id)), slog.String("device", iface.Attrs().Name))
+		}
+		l.deleteBackendMaps(backend.Id, reverseKey)
 		return err
 	}
+	l.nextId += 1
+
+	return nil
+}
 
-	finalizer := func() error {
-		return ll.Close()
+// 登録に失敗したバックエンドを backend_info, backend_meta, reverse_service マップから削除します。
+// 登録の途中で失敗した場合はまだ登録されていない値もあるので、存在しない値は無視します。
+func (l *LbBackendManager) deleteBackendMaps(id uint32, reverseKey serviceKey) {
+	if err := l.backendInfoMap.Delete(id); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+		l.logger.Error("failed to delete from backend_info map", err, slog.Int("id", int(id)))
 	}
+	if err := l.backendMetaMap.Delete(id); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+		l.logger.Error("failed to delete from backend_meta map", err, slog.Int("id", int(id)))
+	}
+	if err := l.reverseServiceMap.Delete(reverseKey); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+		l.logger.Error("failed to delete from reverse_service map", err, slog.Int("id", int(id)))
+	}
+}
+
+// 登録されているバックエンドの名前、ヘルスチェックの対象、重みを更新します。
+// アドレス、所属するサービス、MAC アドレスとデバイスは変更できないので、変更する場合は削除してから登録し直す必要があります。
+// XDP プログラムは既存のコネクションのパケットも backend_info の転送方式で転送するので、実際に適用される転送方式も変更できません。
+// ヘルスチェックの対象を変更した場合はヘルスチェックの結果をリセットします。
+func (l *LbBackendManager) Update(backend *Backend) error {
 
-	backend.finalizer = finalizer
+	if len(backend.Name) > constants.BACKEND_NAME_MAX_SIZE {
+		return fmt.Errorf("backend name must be at most %d bytes", constants.BACKEND_NAME_MAX_SIZE)
+	}
+	if len(backend.HealthCheck) > constants.BACKEND_HEALTHCHECK_MAX_SIZE {
+		return fmt.Errorf("healthcheck target must be at most %d bytes", constants.BACKEND_HEALTHCHECK_MAX_SIZE)
+	}
+	if backend.Weight == 0 {
+		backend.Weight = 1
+	}
 
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	l.logger.Debug("register a backend", slog.Any("backend", backend))
-	l.backends[backend.Id] = backend
+	b, ok := l.backends[backend.Id]
+	if !ok {
+		return fmt.Errorf("backend is not found. id is %d", backend.Id)
+	}
+	service, ok := l.services[b.ServiceId]
+	if !ok {
+		return fmt.Errorf("service is not found. id is %d", b.ServiceId)
+	}
+
+	checker := b.checker
+	if backend.HealthCheck != b.HealthCheck {
+		c, err := newHealthChecker(backend.HealthCheck, b.Address, l.hcConfig.Timeout)
+		if err != nil {
+			return err
+		}
+		checker = c
+	}
+
+	forwarding := backend.forwarding(service)
+	if forwarding != b.forwarding(service) {
+		return fmt.Errorf("forwarding mode of backend %d can not be changed from %s to %s. drain and delete the backend, then add it again", b.Id, b.forwarding(service), forwarding)
+	}
+
+	if err := l.validateWeight(&Backend{Id: b.Id, ServiceId: b.ServiceId, Weight: backend.Weight}); err != nil {
+		return err
+	}
+
+	l.logger.Info("update a backend", slog.Int("id", int(b.Id)), slog.String("name", backend.Name), slog.String("healthcheck", backend.HealthCheck), slog.Int("weight", int(backend.Weight)), slog.String("forwarding", forwarding.String()))
+
+	if backend.HealthCheck != b.HealthCheck {
+		b.HealthCheck = backend.HealthCheck
+		b.checker = checker
+		b.Health = HealthStateUnknown
+		b.counter = healthCounter{}
+	}
+	b.Name = backend.Name
+	b.Weight = backend.Weight
+	b.Forwarding = backend.Forwarding
+
+	if err := l.saveBackendMeta(b); err != nil {
+		return err
+	}
+	// Unhealthy だったバックエンドはヘルスチェックの結果をリセットすると新しいコネクションを受け付けるようになります。
+	if err := l.updateBackendInfoStatus(b); err != nil {
+		return err
+	}
+	if err := l.ajustSchedulingTables(service); err != nil {
+		l.logger.Error("failed to ajust scheduling table maps", err, slog.Int("id", int(b.Id)))
+		return err
+	}
 
 	return nil
 }
 
+// バックエンドのデバイスに XDP プログラムをアタッチします。
+// 既に他のバックエンドによってアタッチされているデバイスの場合は参照カウントを増やすだけです。
+func (l *LbBackendManager) attachBackendIface(iface netlink.Link, id uint32) error {
+
+	index := iface.Attrs().Index
+	if i, ok := l.ifaces[index]; ok {
+		i.refs += 1
+		return nil
+	}
+
+	ifindex := uint32(index)
+	l.logger.Debug("infex backend ifindex and id to backend_ifindex map", slog.Int("ifindex", int(ifindex)), slog.Int("id", int(id)))
+	if err := l.backendIfindexMap.Update(ifindex, id, ebpf.UpdateAny); err != nil {
+		return err
+	}
+
+	l.logger.Debug("insert backend interface index to backend redirect device map", slog.Int("ifindex", int(ifindex)))
+	if err := l.redirectMap.Update(ifindex, ifindex, ebpf.UpdateAny); err != nil {
+		l.deleteBackendIfindex(ifindex)
+		return err
+	}
+
+	// 指定されたデバイスに XDP プログラムをアタッチします
+	l.logger.Info("attach xdp entrypoint program", slog.String("device", iface.Attrs().Name))
+	// --persist で起動している場合は link をピン留めして、scmlbd を再起動してもデタッチされないようにします。
+	pinPath := ""
+	if l.persist {
+		pinPath = loader.LinkPinPath(iface.Attrs().Name)
+	}
+	ll, err := loader.AttachXDP(l.entrypoint, index, pinPath)
+	if err != nil {
+		l.deleteBackendIfindex(ifindex)
+		return err
+	}
+
+	l.ifaces[index] = &backendIface{
+		link: ll,
+		refs: 1,
+	}
+	return nil
+}
+
+// アタッチに失敗したデバイスを backend_ifindex, backend redirect device マップから削除します。
+func (l *LbBackendManager) deleteBackendIfindex(ifindex uint32) {
+	if err := l.backendIfindexMap.Delete(ifindex); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+		l.logger.Error("failed to delete from backend_ifindex map", err, slog.Int("ifindex", int(ifindex)))
+	}
+	if err := l.redirectMap.Delete(ifindex); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+		l.logger.Error("failed to delete from backend redirect dev map", err, slog.Int("ifindex", int(ifindex)))
+	}
+}
+
+// バックエンドのデバイスの参照カウントを減らして、参照するバックエンドがなくなった場合は XDP プログラムをデタッチします。
+func (l *LbBackendManager) detachBackendIface(iface netlink.Link) error {
+
+	index := iface.Attrs().Index
+	i, ok := l.ifaces[index]
+	if !ok {
+		return nil
+	}
+	i.refs -= 1
+	if i.refs > 0 {
+		return nil
+	}
+
+	l.logger.Info("detach XDP program from backend device", slog.String("device", iface.Attrs().Name))
+	// ピン留めされた link は Close だけではデタッチされないのでピン留めを解除します。
+	if l.persist {
+		if err := i.link.Unpin(); err != nil {
+			return err
+		}
+	}
+	if err := i.link.Close(); err != nil {
+		return err
+	}
+	delete(l.ifaces, index)
+
+	l.logger.Debug("delete from backend_ifindex map", slog.Int("ifindex", index))
+	if err := l.backendIfindexMap.Delete(uint32(index)); err != nil {
+		return err
+	}
+
+	l.logger.Debug("delete from backend redirect dev map", slog.Int("ifindex", index))
+	return l.redirectMap.Delete(uint32(index))
+}
+
 // ロードバランサーに紐付けられているバックエンドのリストを取得します。
 func (l *LbBackendManager) Get() ([]Backend, error) {
 
@@ -367,14 +697,23 @@ func (l *LbBackendManager) Get() ([]Backend, error) {
 	backends := make([]Backend, 0, len(l.backends))
 
 	for _, v := range l.backends {
+		forwarding := v.Forwarding
+		if s, ok := l.services[v.ServiceId]; ok {
+			forwarding = v.forwarding(s)
+		}
 		backends = append(backends, Backend{
-			Id:          v.Id,
-			Name:        v.Name,
-			Address:     v.Address,
-			MacAddress:  v.MacAddress,
-			Iface:       v.Iface,
-			HealthCheck: v.HealthCheck,
-			Status:      v.Status,
+			Id:                v.Id,
+			ServiceId:         v.ServiceId,
+			Name:              v.Name,
+			Address:           v.Address,
+			MacAddress:        v.MacAddress,
+			Iface:             v.Iface,
+			HealthCheck:       v.HealthCheck,
+			Status:            v.Status,
+			Health:            v.Health,
+			Weight:            v.Weight,
+			ActiveConnections: v.ActiveConnections,
+			Forwarding:        forwarding,
 		})
 	}
 	return backends, nil
@@ -383,19 +722,24 @@ func (l *LbBackendManager) Get() ([]Backend, error) {
 // ロードバランサーのバックエンドを削除します。
 func (l *LbBackendManager) Delete(id uint32) error {
 
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.delete(id)
+}
+
+// バックエンドを削除します。
+// この関数はロックを取得した状態で呼び出す必要があります。
+func (l *LbBackendManager) delete(id uint32) error {
+
 	backend, ok := l.backends[id]
 	if !ok {
 		return nil
 	}
 
-	var info backendInfo
-
-	if err := l.backendInfoMap.Lookup(id, &info); err != nil {
-		l.logger.Error("failed to lookup backend", err, slog.Int("id", int(id)))
-		return err
-	}
-
-	if info.Satus != uint32(BackenStatusUnavailable) {
+	// backend_info の状態はヘルスチェックに失敗したバックエンドでも Unavailable になるので、
+	// ドレインされているかどうかは Backend.Status で判定します。
+	if backend.Status != BackenStatusUnavailable {
 		err := fmt.Errorf("backens status is not unavailable")
 		l.logger.Error("drain before deleting backend", err, slog.Int("id", int(id)))
 		return err
@@ -416,15 +760,15 @@ func (l *LbBackendManager) Delete(id uint32) error {
 	if err := l.backendInfoMap.Delete(backend.Id); err != nil {
 		return err
 	}
-
-	l.logger.Debug("delete from backend_ifindex map", slog.Int("ifindex", backend.Iface.Attrs().Index))
-	if err := l.backendIfindexMap.Delete(uint32(backend.Iface.Attrs().Index)); err != nil {
+	if err := l.backendMetaMap.Delete(backend.Id); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
 		return err
 	}
 
-	l.logger.Debug("delete from backend redirect dev map", slog.Int("ifindex", backend.Iface.Attrs().Index))
-	if err := l.redirectMap.Delete(uint32(backend.Iface.Attrs().Index)); err != nil {
-		return err
+	if service, ok := l.services[backend.ServiceId]; ok {
+		l.logger.Debug("delete from reverse_service map", slog.Int("id", int(backend.Id)), slog.String("service", service.String()))
+		if err := l.reverseServiceMap.Delete(newServiceKey(backend.Address, service.Port, service.Protocol)); err != nil {
+			return err
+		}
 	}
 
 	delete(l.backends, id)
@@ -451,7 +795,13 @@ func (l *LbBackendManager) DeleteAll() error {
 // ロードバランサーのバックエンドを drain します。
 // バックエンド削除の前準備のための処理です。
 // 既存のコネクションを処理し続けたまま、新規のコネクションを受け付けないようにします。
-func (l *LbBackendManager) Drain(id uint32) error {
+// バックエンドの conntrack のエントリーがなくなるか、deadline が 0 より大きい場合はその時間が経過するとバックエンドを自動で削除します。
+// すでに drain 中のバックエンドの場合は期限のみを更新します。
+func (l *LbBackendManager) Drain(id uint32, deadline time.Duration) error {
+
+	if deadline < 0 {
+		return fmt.Errorf("drain deadline must not be negative: %s", deadline)
+	}
 
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -465,6 +815,21 @@ func (l *LbBackendManager) Drain(id uint32) error {
 	// バックエンドステータスを Unavailable にセットします。
 	backend.Status = BackenStatusUnavailable
 
+	if backend.drain == nil {
+		backend.drain = &drainState{
+			remaining: l.countConntrackEntries(id),
+		}
+	}
+	backend.drain.deadline = time.Time{}
+	if deadline > 0 {
+		backend.drain.deadline = time.Now().Add(deadline)
+	}
+	l.logger.Info("drain a backend", slog.Int("id", int(id)), slog.Int("remaining", int(backend.drain.remaining)), slog.Duration("deadline", deadline))
+
+	if err := l.saveBackendMeta(backend); err != nil {
+		return err
+	}
+
 	// bpf マップ上のステータスも Unavailable にします。
 	var info backendInfo
 	if err := l.backendInfoMap.Lookup(id, &info); err != nil {
@@ -479,154 +844,207 @@ func (l *LbBackendManager) Drain(id uint32) error {
 		return err
 	}
 
-	if err := l.ajustRrTable(id); err != nil {
-		l.logger.Error("failed to ajust round robin table map", err, slog.Int("id", int(id)))
+	if err := l.ajustSchedulingTables(l.services[backend.ServiceId]); err != nil {
+		l.logger.Error("failed to ajust scheduling table maps", err, slog.Int("id", int(id)))
 		return err
 	}
 
 	return nil
 }
 
-func (l *LbBackendManager) ajustRrTable(id uint32) error {
+// バックエンドが新しいコネクションを受け付けられる状態かどうかを返します。
+// drain されておらず、ヘルスチェックに失敗していないバックエンドが対象です。
+func (b *Backend) isActive() bool {
+	return b.Status == BackendStatusAvailable && b.Health != HealthStateUnhealthy
+}
 
-	l.logger.Debug("ajust rr_table")
-	// ラウンドロビンのテーブルを再調整する必要があります。
+// backend_info マップ上のバックエンドのステータスを現在の状態に合わせて更新します。
+func (l *LbBackendManager) updateBackendInfoStatus(backend *Backend) error {
+	var info backendInfo
+	if err := l.backendInfoMap.Lookup(backend.Id, &info); err != nil {
+		l.logger.Error("failed to lookup backend", err, slog.Int("id", int(backend.Id)))
+		return err
+	}
+
+	status := uint32(BackendStatusAvailable)
+	if !backend.isActive() {
+		status = uint32(BackenStatusUnavailable)
+	}
+	if info.Satus == status {
+		return nil
+	}
+	info.Satus = status
 
-	backends := make([]backendInfo, 0, len(l.backends))
+	l.logger.Debug("update backend status in backend_info map", slog.Int("id", int(backend.Id)), slog.String("status", BackendStatus(status).String()))
+	return l.backendInfoMap.Update(backend.Id, info, ebpf.UpdateAny)
+}
 
+// サービスに所属する新しいコネクションを受け付けられるバックエンドを id の順に並べて返します。
+func (l *LbBackendManager) activeBackends(serviceId uint32) []*Backend {
+	backends := make([]*Backend, 0, len(l.backends))
 	for _, v := range l.backends {
-		backends = append(backends, backendInfo{
-			Id:    v.Id,
-			Index: uint32(v.Iface.Attrs().Index),
-			Satus: 0,
-		})
+		if v.ServiceId == serviceId && v.isActive() {
+			backends = append(backends, v)
+		}
 	}
 	sort.Slice(backends, func(i, j int) bool {
 		return backends[i].Id < backends[j].Id
 	})
+	return backends
+}
 
-	// 削除対象のバックエンド id が何番目にあるかを特定します。
-	index := -1
-	for i, b := range backends {
-		if b.Id == id {
-			index = i
-			break
-		}
+// rr_table のサービスの領域を新しいコネクションを受け付けられるバックエンドだけで再構成します。
+// 最小コネクション数方式のサービスでは conntrack の同期ごとに呼び出されるので、前回から変化した要素のみを更新します。
+func (l *LbBackendManager) ajustRrTable(service *Service) error {
+
+	l.logger.Debug("ajust rr_table", slog.Int("service id", int(service.Id)))
+
+	build := buildRrTable
+	if service.Scheduler == SchedulerLeastConnection {
+		build = buildLeastConnectionTable
 	}
-	if index == -1 {
-		// バックエンドのリストに対象が含まれていないときはエラーを返します。
-		return fmt.Errorf("backend is not found: %d", id)
+	table, err := build(l.activeBackends(service.Id))
+	if err != nil {
+		return err
 	}
-	// backends から対象のインデックスの要素を削除します
-	backends = append(backends[:index], backends[index+1:]...)
 
-	// rr_table の要素を再構成します
-	for i := index; i < int(l.registeredBackendLength-1); i++ {
-		l.logger.Debug("update rr_table", slog.Int("index", i), slog.Int("ifindex", int(backends[i].Index)))
-		if err := l.rrTableMap.Update(uint32(i), backends[i].Id, ebpf.UpdateAny); err != nil {
+	// サービスの領域の先頭から詰めて格納します。
+	base := service.Id * constants.RR_TABLE_MAX_SIZE
+	for i, id := range table {
+		if i < len(service.rrTable) && service.rrTable[i] == id {
+			continue
+		}
+		l.logger.Debug("update rr_table", slog.Int("index", i), slog.Int("backend id", int(id)))
+		if err := l.rrTableMap.Update(base+uint32(i), id, ebpf.UpdateAny); err != nil {
 			return err
 		}
 	}
-	// 最後の要素は 0 で更新します(要素が 1 つ削除されて末尾のインデックスは空になるはずなので)
-	l.logger.Debug("refresh last index of rr_table", slog.Int("index", int(l.registeredBackendLength)-1))
-	if err := l.rrTableMap.Update(l.registeredBackendLength-1, uint32(0), ebpf.UpdateAny); err != nil {
-		return err
+	// 使われなくなった末尾の要素は 0 で更新します。
+	for i := len(table); i < len(service.rrTable); i++ {
+		l.logger.Debug("refresh rr_table", slog.Int("index", i))
+		if err := l.rrTableMap.Update(base+uint32(i), uint32(0), ebpf.UpdateAny); err != nil {
+			return err
+		}
 	}
 
-	// 最後に登録しているバックエンドの数をデクリメントします。
-	l.registeredBackendLength -= 1
+	service.rrTable = table
 	return nil
 }
 
-func (l *LbBackendManager) GetConntrackEntries() ([]ConntrackEntry, error) {
-
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	entries := make([]ConntrackEntry, 0, len(l.conntrack))
-
-	for _, e := range l.conntrack {
-		entries = append(entries, *e)
+// 新しいバックエンドを追加してもサービスの rr_table の領域に収まるかを検査します。
+// 登録済みのバックエンドの場合は、重みを変更したあとの値で検査します。
+// l.mu を取得した状態で呼び出す必要があります。
+func (l *LbBackendManager) validateWeight(backend *Backend) error {
+	backends := make([]*Backend, 0, len(l.backends)+1)
+	for _, v := range l.backends {
+		if v.ServiceId == backend.ServiceId && v.Id != backend.Id {
+			backends = append(backends, v)
+		}
 	}
+	backends = append(backends, backend)
 
-	return entries, nil
+	_, err := buildRrTable(backends)
+	return err
 }
 
-func getBackendDeviceInfo(addr netip.Addr) (arpEntry, error) {
-
-	addrMap, err := inspectArpTable()
-	if err != nil {
-		return arpEntry{}, err
-	}
-	info, ok := addrMap[addr]
-	if !ok {
-		return arpEntry{}, fmt.Errorf("failed to get device information for %s", addr.String())
+// バックエンドの重みから rr_table に格納するバックエンド id の列を作成します。
+func buildRrTable(backends []*Backend) ([]uint32, error) {
+	weights := make([]uint32, len(backends))
+	for i, b := range backends {
+		weights[i] = b.Weight
 	}
-
-	return info, nil
+	return buildWeightedTable(backends, weights)
 }
 
-const (
-	arpTablePath string = "/proc/net/arp"
-)
+// 最小コネクション数方式で負荷が最も小さいバックエンドを rr_table に格納する数を返します。
+// デフォルトの BACKEND_MAX_SIZE 個か、それより多い場合はサービスのバックエンドがすべてこの数だけ格納されても rr_table の領域に収まる値にしています。
+func leastConnectionMaxSlots(n int) float64 {
+	if n < constants.BACKEND_MAX_SIZE {
+		n = constants.BACKEND_MAX_SIZE
+	}
+	return float64(constants.RR_TABLE_MAX_SIZE / n)
+}
 
-type arpEntry struct {
-	ipAddr  netip.Addr
-	macAddr net.HardwareAddr
-	device  string
+// 最小コネクション数方式の rr_table に格納するバックエンド id の列を作成します。
+// アクティブなコネクション数を重みで割った負荷が小さいバックエンドほど多く格納されるように重みを計算します。
+// rr_table は conntrack の同期ごとにしか更新されないので、その間の新しいコネクションがひとつのバックエンドに集中しないように、
+// 負荷が大きいバックエンドも最低 1 つは格納します。
+func buildLeastConnectionTable(backends []*Backend) ([]uint32, error) {
+	scores := make([]float64, len(backends))
+	max := 0.0
+	for i, b := range backends {
+		scores[i] = float64(b.Weight) / float64(b.ActiveConnections+1)
+		if scores[i] > max {
+			max = scores[i]
+		}
+	}
+	slots := leastConnectionMaxSlots(len(backends))
+	weights := make([]uint32, len(backends))
+	for i := range backends {
+		weights[i] = uint32(math.Round(scores[i] / max * slots))
+		if weights[i] == 0 {
+			weights[i] = 1
+		}
+	}
+	return buildWeightedTable(backends, weights)
 }
 
-func inspectArpTable() (map[netip.Addr]arpEntry, error) {
+// 重みに従って rr_table に格納するバックエンド id の列を作成します。
+// 同じバックエンドが連続しないように smooth weighted round robin (nginx と同じ方式) で並べます。
+// 例えば重みが a:5, b:1, c:1 のときは a a b a c a a の順になります。
+func buildWeightedTable(backends []*Backend, ws []uint32) ([]uint32, error) {
+	if len(backends) == 0 {
+		return []uint32{}, nil
+	}
 
-	file, err := os.Open(arpTablePath)
-	if err != nil {
-		return nil, err
+	// 重みを最大公約数で割って rr_table の長さをできるだけ短くします。
+	g := uint32(0)
+	for _, w := range ws {
+		g = gcd(g, w)
+	}
+	weights := make([]int, len(backends))
+	total := 0
+	for i, w := range ws {
+		weights[i] = int(w / g)
+		total += weights[i]
+	}
+	if total > constants.RR_TABLE_MAX_SIZE {
+		return nil, fmt.Errorf("total weight of backends exceeds the size of rr_table: %d > %d", total, constants.RR_TABLE_MAX_SIZE)
 	}
-	defer file.Close()
 
-	data, err := io.ReadAll(file)
-	if err != nil {
-		return nil, err
+	table := make([]uint32, 0, total)
+	current := make([]int, len(backends))
+	for n := 0; n < total; n++ {
+		selected := 0
+		for i := range backends {
+			current[i] += weights[i]
+			if current[i] > current[selected] {
+				selected = i
+			}
+		}
+		current[selected] -= total
+		table = append(table, backends[selected].Id)
 	}
 
-	// 以下のように /proc/net/arp ファイルの結果をパースして MAC アドレスとデバイス名を取得します。
-	// $ cat /prpc/net/arp
-	// IP address       HW type     Flags       HW address            Mask     Device
-	// 10.0.0.2         0x1         0x2         6a:ad:44:35:5d:78     *        h0
+	return table, nil
+}
 
-	addrMap := make(map[netip.Addr]arpEntry)
+func gcd(a, b uint32) uint32 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
 
-	lines := strings.Split(string(data), "\n")
-	for i, line := range lines {
-		if i == 0 || line == "" {
-			// この行は ARP Table のヘッダ部分なので読み飛ばします。
-			continue
-		}
-		d := strings.Split(strings.Join(strings.Fields(line), " "), " ")
-		if len(d) < 6 {
-			return nil, fmt.Errorf("failed to parse arp table file")
-		}
-		addr, err := netip.ParseAddr(d[0])
-		if err != nil {
-			return nil, err
-		}
-		maddr, err := net.ParseMAC(d[3])
-		if err != nil {
-			return nil, err
-		}
+func (l *LbBackendManager) GetConntrackEntries() ([]ConntrackEntry, error) {
 
-		addrMap[addr] = arpEntry{
-			ipAddr:  addr,
-			macAddr: maddr,
-			device:  d[5],
-		}
-	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entries := make([]ConntrackEntry, 0, len(l.conntrack))
 
-	return addrMap, nil
-}
+	for _, e := range l.conntrack {
+		entries = append(entries, *e)
+	}
 
-// ping コマンドを実行して Linux の arp table にバックエンドの MAC アドレスとパケットを送出すべきデバイスを登録します。
-// 疎通確認も含めています。
-func learnMacAddr(addr netip.Addr) error {
-	cmd := exec.Command("ping", "-w", "1", "-c", "1", addr.String())
-	return cmd.Run()
+	return entries, nil
 }
@@ -0,0 +1,126 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// 近隣テーブルにエントリーがない場合に ARP/NDP による解決を待つ時間です。
+	neighResolveTimeout time.Duration = 3 * time.Second
+	// 解決を待つ間に近隣テーブルを参照する間隔です。
+	neighResolveInterval time.Duration = 100 * time.Millisecond
+)
+
+// バックエンドにパケットを転送するためのデバイスと、次の転送先の MAC アドレスです。
+type neighEntry struct {
+	link    netlink.Link
+	macAddr net.HardwareAddr
+}
+
+// バックエンドのアドレスへのパケットを送出するデバイスと、次の転送先の MAC アドレスを netlink で解決します。
+// link が nil の場合は経路表を引いてデバイスを決定します。
+// 経路にゲートウェイが指定されている場合は、バックエンドではなくゲートウェイの MAC アドレスを解決します。
+// mac が指定されている場合は近隣テーブルを参照せずにその値を利用します。
+func resolveBackendNeigh(addr netip.Addr, link netlink.Link, mac net.HardwareAddr) (neighEntry, error) {
+
+	nexthop := addr
+
+	routes, err := netlink.RouteGet(addr.AsSlice())
+	if err != nil && link == nil {
+		return neighEntry{}, fmt.Errorf("failed to lookup route to %s: %w", addr, err)
+	}
+	if err == nil && len(routes) > 0 {
+		route := routes[0]
+		if link == nil {
+			link, err = netlink.LinkByIndex(route.LinkIndex)
+			if err != nil {
+				return neighEntry{}, err
+			}
+		}
+		// デバイスが指定されていて経路のデバイスと異なる場合は、バックエンドがそのデバイスに直接接続されているものとして扱います。
+		if route.LinkIndex == link.Attrs().Index && route.Gw != nil {
+			if gw, ok := netip.AddrFromSlice(route.Gw); ok {
+				nexthop = gw.Unmap()
+			}
+		}
+	}
+	if link == nil {
+		return neighEntry{}, fmt.Errorf("no route to %s", addr)
+	}
+
+	if len(mac) != 0 {
+		return neighEntry{link: link, macAddr: mac}, nil
+	}
+
+	mac, err = resolveNeigh(link, nexthop)
+	if err != nil {
+		return neighEntry{}, err
+	}
+	return neighEntry{link: link, macAddr: mac}, nil
+}
+
+// デバイスの近隣テーブルからアドレスに対応する MAC アドレスを取得します。
+// 近隣テーブルにエントリーがない場合は NTF_USE フラグを指定したエントリーを登録してカーネルに ARP/NDP による解決を要求し、解決されるまで待ちます。
+// ICMP を送信しないので、ICMP がフィルタされている環境でも解決できます。
+func resolveNeigh(link netlink.Link, addr netip.Addr) (net.HardwareAddr, error) {
+
+	family := netlink.FAMILY_V6
+	if addr.Is4() {
+		family = netlink.FAMILY_V4
+	}
+
+	mac, err := lookupNeigh(link, addr, family)
+	if err != nil || mac != nil {
+		return mac, err
+	}
+
+	if err := netlink.NeighSet(&netlink.Neigh{
+		LinkIndex: link.Attrs().Index,
+		Family:    family,
+		IP:        addr.AsSlice(),
+		State:     netlink.NUD_NONE,
+		Flags:     netlink.NTF_USE,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to request neighbor resolution for %s: %w", addr, err)
+	}
+
+	deadline := time.Now().Add(neighResolveTimeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(neighResolveInterval)
+		mac, err := lookupNeigh(link, addr, family)
+		if err != nil || mac != nil {
+			return mac, err
+		}
+	}
+
+	return nil, fmt.Errorf("failed to resolve mac address of %s on %s", addr, link.Attrs().Name)
+}
+
+// デバイスの近隣テーブルから解決済みのエントリーを探して MAC アドレスを返します。
+// 見つからない場合は nil を返します。
+func lookupNeigh(link netlink.Link, addr netip.Addr, family int) (net.HardwareAddr, error) {
+
+	neighs, err := netlink.NeighList(link.Attrs().Index, family)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, neigh := range neighs {
+		a, ok := netip.AddrFromSlice(neigh.IP)
+		if !ok || a.Unmap() != addr {
+			continue
+		}
+		// 解決できていないエントリーは MAC アドレスを持たないので読み飛ばします。
+		if len(neigh.HardwareAddr) == 0 || neigh.State&(netlink.NUD_FAILED|netlink.NUD_INCOMPLETE) != 0 {
+			continue
+		}
+		return neigh.HardwareAddr, nil
+	}
+
+	return nil, nil
+}
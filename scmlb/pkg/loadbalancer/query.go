@@ -0,0 +1,289 @@
+package loadbalancer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+	"sort"
+	"time"
+
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
+)
+
+// conntrack のエントリーを絞り込む条件です。
+// ゼロ値のフィールドは条件として扱いません。
+type ConntrackFilter struct {
+	BackendId uint32
+	// TransportProtocolAny の場合はすべてのプロトコルが対象です。
+	Protocol protocols.TransportProtocol
+	// いずれかの状態に一致するエントリーが対象です。
+	States []ConnectionState
+	// 送信元アドレスがこのプレフィックスに含まれるエントリーが対象です。
+	SrcPrefix netip.Prefix
+	DstPort   uint32
+	// 最後にいずれかの方向のパケットを処理してからこの時間以上経過したエントリーが対象です。
+	MinIdle time.Duration
+}
+
+// conntrack のエントリーを並べ替えるキーです。
+type ConntrackSortKey uint8
+
+const (
+	// 5-tuple の昇順に並べます。
+	ConntrackSortKeyTuple = ConntrackSortKey(0)
+	// 両方向のパケット数の合計の多い順に並べます。
+	ConntrackSortKeyPackets = ConntrackSortKey(1)
+	// 最後にパケットを処理した時刻の新しい順に並べます。
+	ConntrackSortKeyLastSeen = ConntrackSortKey(2)
+	// 両方向のバイト数の合計の多い順に並べます。
+	ConntrackSortKeyBytes = ConntrackSortKey(3)
+)
+
+func ConntrackSortKeyFromString(s string) (ConntrackSortKey, error) {
+	switch s {
+	case "", "tuple":
+		return ConntrackSortKeyTuple, nil
+	case "packets":
+		return ConntrackSortKeyPackets, nil
+	case "last-seen":
+		return ConntrackSortKeyLastSeen, nil
+	case "bytes":
+		return ConntrackSortKeyBytes, nil
+	default:
+		return ConntrackSortKey(255), fmt.Errorf("unknown sort key: %s", s)
+	}
+}
+
+func (k ConntrackSortKey) String() string {
+	switch k {
+	case ConntrackSortKeyTuple:
+		return "tuple"
+	case ConntrackSortKeyPackets:
+		return "packets"
+	case ConntrackSortKeyLastSeen:
+		return "last-seen"
+	case ConntrackSortKeyBytes:
+		return "bytes"
+	default:
+		return "unknown"
+	}
+}
+
+// conntrack のエントリーの検索条件です。
+type ConntrackQuery struct {
+	Filter ConntrackFilter
+	SortBy ConntrackSortKey
+	// true の場合は SortBy の逆順に並べます。
+	Reverse bool
+	// 一度に返すエントリーの最大数です。0 の場合はすべてのエントリーを返します。
+	PageSize uint32
+	// 前回の検索で返されたトークンを指定すると、その続きのエントリーを返します。
+	PageToken string
+}
+
+// conntrack のエントリーの検索結果です。
+type ConntrackQueryResult struct {
+	Entries []ConntrackEntry
+	// 続きのエントリーがある場合に次の検索で指定するトークンです。続きがない場合は空文字列です。
+	NextPageToken string
+	// 条件に一致したエントリーの総数です。
+	Total int
+}
+
+// エントリーが条件に一致するかを返します。
+func (f *ConntrackFilter) match(e *ConntrackEntry, now time.Time) bool {
+	if f.BackendId != 0 && e.BackendId != f.BackendId {
+		return false
+	}
+	if f.Protocol != protocols.TransportProtocolAny && e.Protocol != f.Protocol {
+		return false
+	}
+	if len(f.States) > 0 {
+		found := false
+		for _, s := range f.States {
+			if e.State == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.SrcPrefix.IsValid() && !f.SrcPrefix.Contains(e.SrcAddr) {
+		return false
+	}
+	if f.DstPort != 0 && e.DstPort != f.DstPort {
+		return false
+	}
+	if f.MinIdle > 0 && now.Sub(e.Timestamp) < f.MinIdle {
+		return false
+	}
+	return true
+}
+
+// ページトークンに格納する、前回返した最後のエントリーの位置です。
+// 同じ並び順で、このエントリーより後ろにあるエントリーから続きを返します。
+// エントリーの追加や削除があっても続きの位置がずれないように、インデックスではなくエントリーの値を格納しています。
+type conntrackCursor struct {
+	SortBy  uint8
+	Reverse uint8
+	Value   int64
+	Key     conntrackKey
+}
+
+func (c *conntrackCursor) encode() string {
+	buf := new(bytes.Buffer)
+	// バッファへの書き込みは失敗しません。
+	binary.Write(buf, binary.LittleEndian, c)
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes())
+}
+
+func decodeConntrackCursor(token string) (*conntrackCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page token: %w", err)
+	}
+	var c conntrackCursor
+	if err := binary.Read(bytes.NewReader(b), binary.LittleEndian, &c); err != nil {
+		return nil, fmt.Errorf("invalid page token: %w", err)
+	}
+	return &c, nil
+}
+
+// 並べ替えのためのエントリーとキーの組です。
+type conntrackItem struct {
+	key   conntrackKey
+	entry *ConntrackEntry
+	value int64
+}
+
+// 並べ替えのキーに対応する値を返します。大きい値ほど先頭に並びます。
+func sortValue(sortBy ConntrackSortKey, e *ConntrackEntry) int64 {
+	switch sortBy {
+	case ConntrackSortKeyPackets:
+		return int64(e.Counter + e.EgressPackets)
+	case ConntrackSortKeyLastSeen:
+		return e.Timestamp.UnixNano()
+	case ConntrackSortKeyBytes:
+		return int64(e.IngressBytes + e.EgressBytes)
+	default:
+		return 0
+	}
+}
+
+// 5-tuple を比較します。
+func compareConntrackKey(a, b conntrackKey) int {
+	if c := bytes.Compare(a.SrcAddr[:], b.SrcAddr[:]); c != 0 {
+		return c
+	}
+	if c := bytes.Compare(a.DstAddr[:], b.DstAddr[:]); c != 0 {
+		return c
+	}
+	for _, p := range [][2]uint32{
+		{uint32(protocols.Ntohs(a.SrcPort)), uint32(protocols.Ntohs(b.SrcPort))},
+		{uint32(protocols.Ntohs(a.DstPort)), uint32(protocols.Ntohs(b.DstPort))},
+		{a.Protocol, b.Protocol},
+	} {
+		if p[0] < p[1] {
+			return -1
+		}
+		if p[0] > p[1] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// 並び順で a が b より前にある場合に負の値を返します。
+// 並べ替えのキーの値が等しい場合は 5-tuple の順に並べるので、エントリーの順序は一意に決まります。
+func compareConntrackItem(reverse bool, aValue int64, aKey conntrackKey, bValue int64, bKey conntrackKey) int {
+	c := 0
+	if aValue > bValue {
+		c = -1
+	} else if aValue < bValue {
+		c = 1
+	}
+	if c == 0 {
+		c = compareConntrackKey(aKey, bKey)
+	}
+	if reverse {
+		c = -c
+	}
+	return c
+}
+
+// 条件に一致する conntrack のエントリーを並べ替えて、ページごとに返します。
+func (l *LbBackendManager) QueryConntrackEntries(query *ConntrackQuery) (*ConntrackQueryResult, error) {
+
+	var cursor *conntrackCursor
+	if query.PageToken != "" {
+		c, err := decodeConntrackCursor(query.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		if ConntrackSortKey(c.SortBy) != query.SortBy || (c.Reverse != 0) != query.Reverse {
+			return nil, fmt.Errorf("page token was issued for a different sort order")
+		}
+		cursor = c
+	}
+
+	l.mu.Lock()
+
+	now := time.Now()
+	items := make([]conntrackItem, 0, len(l.conntrack))
+	for k, e := range l.conntrack {
+		if !query.Filter.match(e, now) {
+			continue
+		}
+		items = append(items, conntrackItem{
+			key:   k,
+			entry: e,
+			value: sortValue(query.SortBy, e),
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return compareConntrackItem(query.Reverse, items[i].value, items[i].key, items[j].value, items[j].key) < 0
+	})
+
+	result := &ConntrackQueryResult{
+		Total: len(items),
+	}
+
+	// ページトークンが指定されている場合は、前回返した最後のエントリーより後ろから返します。
+	start := 0
+	if cursor != nil {
+		start = sort.Search(len(items), func(i int) bool {
+			return compareConntrackItem(query.Reverse, items[i].value, items[i].key, cursor.Value, cursor.Key) > 0
+		})
+	}
+	end := len(items)
+	if query.PageSize > 0 && start+int(query.PageSize) < end {
+		end = start + int(query.PageSize)
+	}
+
+	result.Entries = make([]ConntrackEntry, 0, end-start)
+	for _, item := range items[start:end] {
+		result.Entries = append(result.Entries, *item.entry)
+	}
+
+	l.mu.Unlock()
+
+	if end < len(items) {
+		last := items[end-1]
+		c := &conntrackCursor{
+			SortBy: uint8(query.SortBy),
+			Value:  last.value,
+			Key:    last.key,
+		}
+		if query.Reverse {
+			c.Reverse = 1
+		}
+		result.NextPageToken = c.encode()
+	}
+
+	return result, nil
+}
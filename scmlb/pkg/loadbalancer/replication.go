@@ -0,0 +1,155 @@
+package loadbalancer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/cilium/ebpf"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
+	"golang.org/x/exp/slog"
+)
+
+// peer の scmlbd から受け取った conntrack のエントリーの変更です。
+type ConntrackReplica struct {
+	// Created と Updated の場合はエントリーを登録し、Deleted の場合は削除します。
+	Type  ConntrackEventType
+	Tuple ConntrackTuple
+	State ConnectionState
+	// peer とバックエンドの id は一致しないので、バックエンドはアドレスで指定します。
+	BackendAddr netip.Addr
+	SrcMacAddr  net.HardwareAddr
+}
+
+// peer に送信するために、このホストでパケットを処理している conntrack のエントリーを Created のイベントとして返します。
+// peer から同期されたエントリーは含みません。
+func (l *LbBackendManager) ConntrackSnapshot() []ConntrackEvent {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	events := make([]ConntrackEvent, 0, len(l.conntrack))
+	for _, entry := range l.conntrack {
+		if entry.Replicated {
+			continue
+		}
+		b, ok := l.backends[entry.BackendId]
+		if !ok {
+			continue
+		}
+		events = append(events, ConntrackEvent{
+			Type:        ConntrackEventCreated,
+			Entry:       *entry,
+			BackendAddr: b.Address,
+			Timestamp:   now,
+		})
+	}
+	return events
+}
+
+// peer から conntrack のエントリーの同期を開始したことを記録します。
+// 同期している間は、peer から同期されたエントリーをアイドルタイムアウトで削除しません。
+// 同期が終わったら EndReplication() を呼び出す必要があります。
+func (l *LbBackendManager) BeginReplication() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.replicationPeers += 1
+}
+
+// peer からの conntrack のエントリーの同期が終わったことを記録します。
+// peer から同期されたエントリーは、以降は登録した時刻を最後にパケットを処理した時刻としてアイドルタイムアウトで削除されます。
+func (l *LbBackendManager) EndReplication() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.replicationPeers > 0 {
+		l.replicationPeers -= 1
+	}
+}
+
+// peer から受け取った conntrack のエントリーの変更を conntrack マップに反映します。
+// このホストで既にパケットを処理しているエントリーは、このホストの状態を優先して変更しません。
+func (l *LbBackendManager) ApplyConntrackReplica(r *ConntrackReplica) error {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := r.Tuple.key()
+
+	var current conntrackInfo
+	exists := true
+	if err := l.conntrackMap.Lookup(key, &current); err != nil {
+		if !errors.Is(err, ebpf.ErrKeyNotExist) {
+			return err
+		}
+		exists = false
+	}
+	if exists && (current.Counter != 0 || current.EgressPackets != 0) {
+		l.logger.Debug("skip a replica of a conntrack entry handled by this host", slog.Any("tuple", r.Tuple))
+		return nil
+	}
+
+	if r.Type == ConntrackEventDeleted {
+		if !exists {
+			return nil
+		}
+		if err := l.conntrackMap.Delete(key); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			return err
+		}
+		if entry, ok := l.conntrack[key]; ok {
+			l.notifyConntrack(ConntrackEventDeleted, entry, entry.State, ConntrackDeleteReasonReplicated)
+			delete(l.conntrack, key)
+		}
+		return nil
+	}
+
+	backend, err := l.replicaBackend(r)
+	if err != nil {
+		return err
+	}
+
+	monoNow, err := monotonicNow()
+	if err != nil {
+		return err
+	}
+	info := conntrackInfo{
+		Id:        backend.Id,
+		Index:     uint32(backend.Iface.Attrs().Index),
+		Status:    uint8(r.State),
+		FirstSeen: monoNow,
+		LastSeen:  monoNow,
+	}
+	if exists {
+		info.FirstSeen = current.FirstSeen
+	}
+	copy(info.SrcMacAddr[:], r.SrcMacAddr)
+
+	l.logger.Debug("install a replica of a conntrack entry", slog.Any("tuple", r.Tuple), slog.Int("backend id", int(backend.Id)), slog.String("state", r.State.String()))
+	return l.conntrackMap.Update(key, info, ebpf.UpdateAny)
+}
+
+// peer から受け取ったエントリーの転送先のバックエンドを、宛先の VIP のサービスに登録されたバックエンドのアドレスから探します。
+// この関数はロックを取得した状態で呼び出す必要があります。
+func (l *LbBackendManager) replicaBackend(r *ConntrackReplica) (*Backend, error) {
+	for _, b := range l.backends {
+		if b.Address != r.BackendAddr {
+			continue
+		}
+		s, ok := l.services[b.ServiceId]
+		if !ok || s.Vip != r.Tuple.DstAddr {
+			continue
+		}
+		if s.Protocol != protocols.TransportProtocolAny && s.Protocol != r.Tuple.Protocol {
+			continue
+		}
+		if s.Port != 0 && s.Port != r.Tuple.DstPort {
+			continue
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("backend %s of service %s is not found", r.BackendAddr, r.Tuple.DstAddr)
+}
@@ -0,0 +1,181 @@
+package loadbalancer
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cilium/ebpf"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/exp/slog"
+)
+
+// この構造体は bpf/include/scmlb.h の backend_meta 構造体に対応しています。
+// backend_info に含まれないバックエンドの情報を保存して、再起動時にバックエンドを復元するために利用します。
+type backendMeta struct {
+	ServiceId  uint32
+	Weight     uint32
+	Forwarding uint32
+	Drained    uint32
+	// drain の期限を UNIX 時間(ナノ秒)で格納します。期限がない場合は 0 です。
+	DrainDeadline int64
+	Name          [constants.BACKEND_NAME_MAX_SIZE]byte
+	HealthCheck   [constants.BACKEND_HEALTHCHECK_MAX_SIZE]byte
+}
+
+// backend_meta マップにバックエンドの情報を保存します。
+// この関数はロックを取得した状態で呼び出す必要があります。
+func (l *LbBackendManager) saveBackendMeta(backend *Backend) error {
+	meta := backendMeta{
+		ServiceId:  backend.ServiceId,
+		Weight:     backend.Weight,
+		Forwarding: uint32(backend.Forwarding),
+	}
+	if backend.drain != nil {
+		meta.Drained = 1
+		if !backend.drain.deadline.IsZero() {
+			meta.DrainDeadline = backend.drain.deadline.UnixNano()
+		}
+	}
+	copy(meta.Name[:], backend.Name)
+	copy(meta.HealthCheck[:], backend.HealthCheck)
+
+	l.logger.Debug("insert backend metadata to backend_meta map", slog.Int("id", int(backend.Id)), slog.Any("meta", meta))
+	return l.backendMetaMap.Update(backend.Id, meta, ebpf.UpdateAny)
+}
+
+// ピン留めされた bpf マップからサービスとバックエンドを復元します。
+// バックエンドのデバイスにアタッチされている XDP プログラムは、ピン留めされた link のプログラムを置き換えて引き継ぎます。
+// conntrack のエントリーは同期のループで bpf マップから読み込まれるので、ここでは復元しません。
+func (l *LbBackendManager) Restore() error {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.restoreServices(); err != nil {
+		return err
+	}
+	if err := l.restoreBackends(); err != nil {
+		return err
+	}
+
+	// 復元したバックエンドの状態をもとに rr_table と maglev_table を再構成します。
+	for _, s := range l.services {
+		if err := l.ajustSchedulingTables(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// services マップからサービスを復元します。
+// この関数はロックを取得した状態で呼び出す必要があります。
+func (l *LbBackendManager) restoreServices() error {
+
+	var (
+		key  serviceKey
+		info serviceInfo
+	)
+	entries := l.servicesMap.Iterate()
+	for entries.Next(&key, &info) {
+		s := &Service{
+			Id:              info.Id,
+			Vip:             protocols.IpAddrFrom16(key.Addr),
+			Port:            uint32(protocols.Ntohs(key.Port)),
+			Protocol:        protocols.TransportProtocol(key.Protocol),
+			Scheduler:       Scheduler(info.Scheduler),
+			AffinityTimeout: time.Duration(info.AffinityTimeout) * time.Second,
+			Forwarding:      ForwardingMode(info.Forwarding),
+		}
+
+		// rr_table のサービスの領域は先頭から詰めて格納されているので、0 が現れるまでを前回書き込んだ値として読み込みます。
+		base := s.Id * constants.RR_TABLE_MAX_SIZE
+		for i := uint32(0); i < constants.RR_TABLE_MAX_SIZE; i++ {
+			var id uint32
+			if err := l.rrTableMap.Lookup(base+i, &id); err != nil {
+				return err
+			}
+			if id == 0 {
+				break
+			}
+			s.rrTable = append(s.rrTable, id)
+		}
+
+		l.logger.Info("restore a service", slog.Int("id", int(s.Id)), slog.String("service", s.String()), slog.String("scheduler", s.Scheduler.String()), slog.String("forwarding", s.Forwarding.String()))
+		l.services[s.Id] = s
+	}
+	return entries.Err()
+}
+
+// backend_info マップと backend_meta マップからバックエンドを復元します。
+// ヘルスチェックの状態は保存していないので、Unknown から始めます。
+// この関数はロックを取得した状態で呼び出す必要があります。
+func (l *LbBackendManager) restoreBackends() error {
+
+	var (
+		id   uint32
+		info backendInfo
+	)
+	entries := l.backendInfoMap.Iterate()
+	for entries.Next(&id, &info) {
+		var meta backendMeta
+		if err := l.backendMetaMap.Lookup(id, &meta); err != nil {
+			return fmt.Errorf("failed to lookup metadata of backend %d: %w", id, err)
+		}
+		if _, ok := l.services[meta.ServiceId]; !ok {
+			return fmt.Errorf("service %d of backend %d is not found", meta.ServiceId, id)
+		}
+
+		iface, err := netlink.LinkByIndex(int(info.Index))
+		if err != nil {
+			return fmt.Errorf("failed to find the device of backend %d: %w", id, err)
+		}
+
+		backend := &Backend{
+			Id:          id,
+			ServiceId:   meta.ServiceId,
+			Name:        string(bytes.TrimRight(meta.Name[:], "\x00")),
+			Address:     protocols.IpAddrFrom16(info.DstIpAddr),
+			MacAddress:  net.HardwareAddr(info.DstMacAddr[:]),
+			Iface:       iface,
+			Status:      BackendStatusAvailable,
+			HealthCheck: string(bytes.TrimRight(meta.HealthCheck[:], "\x00")),
+			Health:      HealthStateUnknown,
+			Weight:      meta.Weight,
+			Forwarding:  ForwardingMode(meta.Forwarding),
+		}
+		if info.SrcIpAddr != [16]byte{} {
+			backend.encapSrc = protocols.IpAddrFrom16(info.SrcIpAddr)
+		}
+		if meta.Drained != 0 {
+			backend.Status = BackenStatusUnavailable
+			backend.drain = &drainState{}
+			if meta.DrainDeadline != 0 {
+				backend.drain.deadline = time.Unix(0, meta.DrainDeadline)
+			}
+		}
+
+		checker, err := newHealthChecker(backend.HealthCheck, backend.Address, l.hcConfig.Timeout)
+		if err != nil {
+			return err
+		}
+		backend.checker = checker
+
+		if err := l.attachBackendIface(iface, id); err != nil {
+			return err
+		}
+		backend.finalizer = func() error {
+			return l.detachBackendIface(iface)
+		}
+
+		l.logger.Info("restore a backend", slog.Int("id", int(id)), slog.String("address", backend.Address.String()), slog.String("device", iface.Attrs().Name))
+		l.backends[id] = backend
+		if id >= l.nextId {
+			l.nextId = id + 1
+		}
+	}
+	return entries.Err()
+}
@@ -0,0 +1,157 @@
+package loadbalancer
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"github.com/cilium/ebpf"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
+	"golang.org/x/exp/slog"
+)
+
+// 新しいコネクションを割り当てるバックエンドの選択方式です。
+// bpf/include/scmlb.h の enum Scheduler に対応しています。
+type Scheduler uint32
+
+const (
+	SchedulerRoundRobin Scheduler = Scheduler(0)
+	SchedulerMaglev     Scheduler = Scheduler(1)
+	// アクティブなコネクション数が少ないバックエンドを優先して選択する方式です。
+	SchedulerLeastConnection Scheduler = Scheduler(2)
+)
+
+func SchedulerFromString(s string) (Scheduler, error) {
+	switch s {
+	case "rr", "round-robin":
+		return SchedulerRoundRobin, nil
+	case "maglev":
+		return SchedulerMaglev, nil
+	case "lc", "least-connection":
+		return SchedulerLeastConnection, nil
+	default:
+		return Scheduler(255), fmt.Errorf("unknown scheduler: %s", s)
+	}
+}
+
+func (s Scheduler) String() string {
+	switch s {
+	case SchedulerRoundRobin:
+		return "rr"
+	case SchedulerMaglev:
+		return "maglev"
+	case SchedulerLeastConnection:
+		return "lc"
+	default:
+		return fmt.Sprintf("unknown(%d)", s)
+	}
+}
+
+// サービスの rr_table と maglev_table をバックエンドの現在の状態に合わせて更新します。
+// バックエンドの追加や drain、ヘルスチェックの結果によってバックエンドの状態が変化したときに呼び出します。
+func (l *LbBackendManager) ajustSchedulingTables(service *Service) error {
+	if err := l.ajustRrTable(service); err != nil {
+		return err
+	}
+	if service.Scheduler != SchedulerMaglev {
+		return nil
+	}
+	return l.ajustMaglevTable(service)
+}
+
+// すべてのサービスの rr_table と maglev_table を更新します。
+func (l *LbBackendManager) ajustAllSchedulingTables() error {
+	var errs []error
+	for _, s := range l.services {
+		if err := l.ajustSchedulingTables(s); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// maglev_table のサービスの領域を新しいコネクションを受け付けられるバックエンドで再計算します。
+// テーブル全体を書き換えるとシステムコールの回数が多くなるので、前回の計算結果から変化した要素のみを更新します。
+func (l *LbBackendManager) ajustMaglevTable(service *Service) error {
+
+	l.logger.Debug("ajust maglev_table", slog.Int("service id", int(service.Id)))
+
+	if service.maglevTable == nil {
+		service.maglevTable = make([]uint32, constants.MAGLEV_TABLE_SIZE)
+	}
+
+	table := buildMaglevTable(l.activeBackends(service.Id), constants.MAGLEV_TABLE_SIZE)
+
+	base := service.Id * constants.MAGLEV_TABLE_SIZE
+	updated := 0
+	for i, id := range table {
+		if service.maglevTable[i] == id {
+			continue
+		}
+		if err := l.maglevTableMap.Update(base+uint32(i), id, ebpf.UpdateAny); err != nil {
+			return err
+		}
+		service.maglevTable[i] = id
+		updated += 1
+	}
+
+	l.logger.Debug("maglev_table is updated", slog.Int("service id", int(service.Id)), slog.Int("updated entries", updated))
+	return nil
+}
+
+// Maglev のルックアップテーブルを計算します。
+// 各バックエンドはアドレスから計算した offset と skip で決まる順番でテーブルの空いている要素を埋めていきます。
+// バックエンドがひとつ増減しても、移動するエントリーはおよそ 1/N に抑えられます。
+// 重みが指定されている場合は一巡ごとに重みの数だけ要素を埋めます。
+// 参考: https://research.google/pubs/pub44824/
+func buildMaglevTable(backends []*Backend, size uint64) []uint32 {
+
+	table := make([]uint32, size)
+	if len(backends) == 0 {
+		return table
+	}
+
+	// 複数のバックエンドが同じ要素を取り合った場合は先に埋めたバックエンドのものになります。
+	// バックエンドの id はホストごとに異なるので、アドレス順に並べて埋める順番をホストに依存しないようにします。
+	backends = append([]*Backend(nil), backends...)
+	sort.Slice(backends, func(i, j int) bool {
+		return backends[i].Address.Less(backends[j].Address)
+	})
+
+	offsets := make([]uint64, len(backends))
+	skips := make([]uint64, len(backends))
+	next := make([]uint64, len(backends))
+	for i, b := range backends {
+		// バックエンドの id ではなくアドレスからハッシュ値を計算することで、
+		// デーモンの再起動やホストが変わっても同じテーブルを計算できるようにしています。
+		offsets[i] = maglevHash(b.Address.String(), "offset") % size
+		skips[i] = maglevHash(b.Address.String(), "skip")%(size-1) + 1
+	}
+
+	filled := uint64(0)
+	for {
+		for i, b := range backends {
+			for w := uint32(0); w < b.Weight; w++ {
+				c := (offsets[i] + next[i]*skips[i]) % size
+				for table[c] != 0 {
+					next[i] += 1
+					c = (offsets[i] + next[i]*skips[i]) % size
+				}
+				table[c] = b.Id
+				next[i] += 1
+				filled += 1
+				if filled == size {
+					return table
+				}
+			}
+		}
+	}
+}
+
+func maglevHash(key, seed string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	h.Write([]byte(seed))
+	return h.Sum64()
+}
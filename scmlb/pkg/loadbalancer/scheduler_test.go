@@ -0,0 +1,124 @@
+package loadbalancer
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
+)
+
+// 10.0.0.1 から順にアドレスを割り当てた重み 1 のバックエンドを n 個作成します。
+// id は first から順に割り当てます。
+func newMaglevBackends(n int, first uint32) []*Backend {
+	backends := make([]*Backend, 0, n)
+	addr := netip.MustParseAddr("10.0.0.1")
+	for i := 0; i < n; i++ {
+		backends = append(backends, &Backend{
+			Id:      first + uint32(i),
+			Address: addr,
+			Weight:  1,
+		})
+		addr = addr.Next()
+	}
+	return backends
+}
+
+// テーブルの要素をバックエンドの id からアドレスに置き換えます。
+func maglevTableAddrs(t *testing.T, table []uint32, backends []*Backend) []netip.Addr {
+	t.Helper()
+	addrs := make(map[uint32]netip.Addr, len(backends))
+	for _, b := range backends {
+		addrs[b.Id] = b.Address
+	}
+	res := make([]netip.Addr, len(table))
+	for i, id := range table {
+		addr, ok := addrs[id]
+		if !ok {
+			t.Fatalf("table[%d] has unknown backend id %d", i, id)
+		}
+		res[i] = addr
+	}
+	return res
+}
+
+func countMovedSlots(before, after []netip.Addr) int {
+	moved := 0
+	for i := range before {
+		if before[i] != after[i] {
+			moved += 1
+		}
+	}
+	return moved
+}
+
+func TestBuildMaglevTableBalanced(t *testing.T) {
+	size := uint64(constants.MAGLEV_TABLE_SIZE)
+	backends := newMaglevBackends(10, 1)
+
+	table := buildMaglevTable(backends, size)
+
+	counts := make(map[uint32]int)
+	for _, id := range table {
+		counts[id] += 1
+	}
+	expected := int(size) / len(backends)
+	for _, b := range backends {
+		c := counts[b.Id]
+		if c < expected*9/10 || c > expected*11/10 {
+			t.Errorf("backend %d has %d entries, expected about %d", b.Id, c, expected)
+		}
+	}
+}
+
+func TestBuildMaglevTableIndependentOfIdAndOrder(t *testing.T) {
+	size := uint64(constants.MAGLEV_TABLE_SIZE)
+
+	// ホストによってバックエンドの id と並び順が異なる場合でも、アドレスで見て同じテーブルになることを確認します。
+	a := newMaglevBackends(5, 1)
+	b := newMaglevBackends(5, 100)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+
+	tableA := maglevTableAddrs(t, buildMaglevTable(a, size), a)
+	tableB := maglevTableAddrs(t, buildMaglevTable(b, size), b)
+	if moved := countMovedSlots(tableA, tableB); moved != 0 {
+		t.Fatalf("%d entries differ between hosts", moved)
+	}
+}
+
+func TestBuildMaglevTableDisruption(t *testing.T) {
+	size := uint64(constants.MAGLEV_TABLE_SIZE)
+
+	tests := []struct {
+		name   string
+		before int
+		after  int
+	}{
+		{name: "add a backend to 10", before: 10, after: 11},
+		{name: "remove a backend from 11", before: 11, after: 10},
+		{name: "add a backend to 3", before: 3, after: 4},
+		{name: "remove a backend from 4", before: 4, after: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := newMaglevBackends(tt.before, 1)
+			after := newMaglevBackends(tt.after, 1)
+
+			tableBefore := maglevTableAddrs(t, buildMaglevTable(before, size), before)
+			tableAfter := maglevTableAddrs(t, buildMaglevTable(after, size), after)
+
+			// 増減したバックエンドの分の 1/N が移動するのは避けられないので、
+			// それ以外のバックエンド間での移動も含めて 2/N 以下に収まることを確認します。
+			n := tt.before
+			if tt.after > n {
+				n = tt.after
+			}
+			moved := countMovedSlots(tableBefore, tableAfter)
+			minimum := int(size) / n
+			if moved < minimum*9/10 || moved > minimum*2 {
+				t.Fatalf("%d of %d entries moved, expected about %d", moved, size, minimum)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"time"
+
+	"github.com/cilium/ebpf"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/constants"
+	"github.com/terassyi/seccamp-xdp/scmlb/pkg/protocols"
+	"golang.org/x/exp/slog"
+)
+
+// ロードバランサーが待ち受ける VIP とポート、プロトコルの組です。
+// サービスごとにバックエンドの選択方式とバックエンドの集合を持ちます。
+// Port が 0 の場合はすべてのポート、Protocol が Any の場合は TCP と UDP の両方が対象になります。
+type Service struct {
+	Id        uint32
+	Vip       netip.Addr
+	Port      uint32
+	Protocol  protocols.TransportProtocol
+	Scheduler Scheduler
+	// 送信元アドレスによるセッション維持のアイドルタイムアウトです。0 のときはセッション維持を行いません。
+	AffinityTimeout time.Duration
+	// バックエンドへの転送方式です。転送方式が指定されていないバックエンドに適用されます。
+	Forwarding ForwardingMode
+
+	// 前回書き込んだ rr_table のこのサービスの領域です。
+	rrTable []uint32
+	// 前回計算した maglev_table のこのサービスの領域です。
+	maglevTable []uint32
+}
+
+// この構造体は bpf/include/scmlb.h の service_key 構造体に対応しています。
+type serviceKey struct {
+	Addr     [16]byte
+	Port     uint16
+	Protocol uint8
+	Pad      uint8
+}
+
+// この構造体は bpf/include/scmlb.h の service 構造体に対応しています。
+type serviceInfo struct {
+	Id        uint32
+	Scheduler uint32
+	RrIndex   uint32
+	// セッション維持のアイドルタイムアウト(秒)です。
+	AffinityTimeout uint32
+	// サービスの転送方式です。XDP プログラムでは利用せず、再起動時にサービスを復元するために保存しています。
+	Forwarding uint32
+}
+
+func newServiceKey(addr netip.Addr, port uint32, protocol protocols.TransportProtocol) serviceKey {
+	return serviceKey{
+		Addr: protocols.IpAddrTo16(addr),
+		// パケットのポート番号と比較できるようにネットワークバイトオーダーで格納します。
+		Port:     protocols.Ntohs(uint16(port)),
+		Protocol: uint8(protocol),
+	}
+}
+
+func (s *Service) key() serviceKey {
+	return newServiceKey(s.Vip, s.Port, s.Protocol)
+}
+
+func (s *Service) info() serviceInfo {
+	return serviceInfo{
+		Id:              s.Id,
+		Scheduler:       uint32(s.Scheduler),
+		AffinityTimeout: uint32(s.AffinityTimeout / time.Second),
+		Forwarding:      uint32(s.Forwarding),
+	}
+}
+
+func (s *Service) String() string {
+	return fmt.Sprintf("%s:%d/%s", s.Vip, s.Port, s.Protocol)
+}
+
+// ロードバランサーのサービスを追加します。
+// 同じ VIP、ポート、プロトコルのサービスが既に存在する場合は選択方式と転送方式を更新します。
+// 転送方式が指定されていない場合は NAT とします。
+func (l *LbBackendManager) SetService(service *Service) (uint32, error) {
+
+	if !service.Vip.IsValid() {
+		return 0, fmt.Errorf("invalid vip: %s", service.Vip)
+	}
+	if service.Port > 65535 {
+		return 0, fmt.Errorf("invalid port: %d", service.Port)
+	}
+	switch service.Protocol {
+	case protocols.TransportProtocolAny, protocols.TransportProtocolTcp, protocols.TransportProtocolUdp:
+	default:
+		return 0, fmt.Errorf("unsupported protocol for service: %s", service.Protocol)
+	}
+	forwarding := service.Forwarding
+	if forwarding == ForwardingModeUnspecified {
+		forwarding = ForwardingModeNat
+	}
+	if forwarding == ForwardingModeDsrGue && service.Vip.Is6() {
+		return 0, fmt.Errorf("gue forwarding is not supported for ipv6 service: %s", service)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, s := range l.services {
+		if s.key() != service.key() {
+			continue
+		}
+		// 転送方式に従うすべてのバックエンドで新しい転送方式が利用できることを確認します。
+		for _, b := range l.backends {
+			if b.ServiceId != s.Id || b.Forwarding != ForwardingModeUnspecified {
+				continue
+			}
+			if err := validateForwarding(forwarding, s, b.encapSrc); err != nil {
+				return 0, fmt.Errorf("backend %d: %w", b.Id, err)
+			}
+		}
+		// 既存のサービスの選択方式と転送方式を更新します。
+		l.logger.Info("update a service", slog.Int("id", int(s.Id)), slog.String("service", s.String()), slog.String("scheduler", service.Scheduler.String()), slog.String("forwarding", forwarding.String()))
+		s.Scheduler = service.Scheduler
+		s.Forwarding = forwarding
+		if err := l.servicesMap.Update(s.key(), s.info(), ebpf.UpdateAny); err != nil {
+			return 0, err
+		}
+		if err := l.updateBackendInfoForwarding(s); err != nil {
+			return 0, err
+		}
+		if err := l.ajustSchedulingTables(s); err != nil {
+			l.logger.Error("failed to ajust scheduling table maps", err, slog.Int("id", int(s.Id)))
+			return 0, err
+		}
+		return s.Id, nil
+	}
+
+	id, err := l.allocateServiceId()
+	if err != nil {
+		return 0, err
+	}
+
+	s := &Service{
+		Id:         id,
+		Vip:        service.Vip,
+		Port:       service.Port,
+		Protocol:   service.Protocol,
+		Scheduler:  service.Scheduler,
+		Forwarding: forwarding,
+	}
+
+	l.logger.Info("register a service", slog.Int("id", int(s.Id)), slog.String("service", s.String()), slog.String("scheduler", s.Scheduler.String()), slog.String("forwarding", s.Forwarding.String()))
+	if err := l.servicesMap.Update(s.key(), s.info(), ebpf.UpdateNoExist); err != nil {
+		return 0, err
+	}
+	l.services[s.Id] = s
+
+	return s.Id, nil
+}
+
+// ロードバランサーのサービスのリストを id の順に取得します。
+func (l *LbBackendManager) GetServices() []Service {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	services := make([]Service, 0, len(l.services))
+	for _, s := range l.services {
+		services = append(services, Service{
+			Id:              s.Id,
+			Vip:             s.Vip,
+			Port:            s.Port,
+			Protocol:        s.Protocol,
+			Scheduler:       s.Scheduler,
+			AffinityTimeout: s.AffinityTimeout,
+			Forwarding:      s.Forwarding,
+		})
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Id < services[j].Id
+	})
+	return services
+}
+
+// ロードバランサーのサービスを削除します。
+// バックエンドが残っているサービスは削除できません。
+func (l *LbBackendManager) DeleteService(id uint32) error {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s, ok := l.services[id]
+	if !ok {
+		return fmt.Errorf("service is not found. id is %d", id)
+	}
+
+	for _, b := range l.backends {
+		if b.ServiceId == id {
+			err := fmt.Errorf("service has backends")
+			l.logger.Error("delete backends before deleting service", err, slog.Int("id", int(id)), slog.Int("backend id", int(b.Id)))
+			return err
+		}
+	}
+
+	l.logger.Info("delete a service", slog.Int("id", int(id)), slog.String("service", s.String()))
+
+	if err := l.servicesMap.Delete(s.key()); err != nil {
+		return err
+	}
+
+	// 同じ id が再利用されたときに古いセッション維持のエントリーが参照されないように削除します。
+	if err := l.flushAffinity(id); err != nil {
+		return err
+	}
+
+	// 同じ id が再利用されたときに古いエントリーが参照されないように rr_table と maglev_table の領域を空にします。
+	for i := range s.rrTable {
+		if err := l.rrTableMap.Update(s.Id*constants.RR_TABLE_MAX_SIZE+uint32(i), uint32(0), ebpf.UpdateAny); err != nil {
+			return err
+		}
+	}
+	for i, backendId := range s.maglevTable {
+		if backendId == 0 {
+			continue
+		}
+		if err := l.maglevTableMap.Update(s.Id*constants.MAGLEV_TABLE_SIZE+uint32(i), uint32(0), ebpf.UpdateAny); err != nil {
+			return err
+		}
+	}
+
+	delete(l.services, id)
+
+	return nil
+}
+
+// 使われていない最小のサービス id を返します。
+// サービスの id は rr_table と maglev_table の領域のインデックスになるので SERVICE_MAX_SIZE 未満である必要があります。
+// また、0 はサービスが指定されていないことを表すので利用しません。
+func (l *LbBackendManager) allocateServiceId() (uint32, error) {
+	for id := uint32(1); id < constants.SERVICE_MAX_SIZE; id++ {
+		if _, ok := l.services[id]; !ok {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("the number of services reaches the limit: %d", constants.SERVICE_MAX_SIZE-1)
+}
@@ -0,0 +1,129 @@
+package loadbalancer
+
+import (
+	"net/netip"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+// conntrack のイベントを受け取る購読者ごとのバッファの大きさです。
+// バッファが溢れた購読者は購読を解除されます。
+const conntrackWatchBufferSize = 1024
+
+// conntrack のエントリーに起きた変化の種類です。
+type ConntrackEventType uint8
+
+const (
+	// エントリーが新しく作成されたことを表します。
+	ConntrackEventCreated = ConntrackEventType(1)
+	// エントリーのコネクションの状態が変化したことを表します。
+	ConntrackEventUpdated = ConntrackEventType(2)
+	// エントリーが削除されたことを表します。
+	ConntrackEventDeleted = ConntrackEventType(3)
+)
+
+func (t ConntrackEventType) String() string {
+	switch t {
+	case ConntrackEventCreated:
+		return "Created"
+	case ConntrackEventUpdated:
+		return "Updated"
+	case ConntrackEventDeleted:
+		return "Deleted"
+	default:
+		return "Unknown"
+	}
+}
+
+// エントリーが削除された理由です。
+const (
+	ConntrackDeleteReasonClosed  = "closed"
+	ConntrackDeleteReasonTimeout = "idle timeout"
+	ConntrackDeleteReasonDrain   = "drain deadline"
+	ConntrackDeleteReasonDeleted = "deleted"
+	ConntrackDeleteReasonEvicted = "evicted"
+	// peer の scmlbd でエントリーが削除されたことを表します。
+	ConntrackDeleteReasonReplicated = "deleted by peer"
+)
+
+// conntrack のエントリーに起きた変化を表すイベントです。
+type ConntrackEvent struct {
+	Type ConntrackEventType
+	// イベントが起きたときのエントリーの値です。
+	Entry ConntrackEntry
+	// Updated のときの変化する前のコネクションの状態です。
+	PrevState ConnectionState
+	// Deleted のときの削除された理由です。
+	Reason string
+	// イベントが起きたときのエントリーのバックエンドのアドレスです。
+	// peer の scmlbd とバックエンドの id は一致しないので、conntrack の同期ではアドレスでバックエンドを特定します。
+	BackendAddr netip.Addr
+	Timestamp   time.Time
+}
+
+// conntrack のイベントの購読者です。
+type conntrackWatcher struct {
+	// 0 の場合はすべてのバックエンドのイベントを受け取ります。
+	backendId uint32
+	ch        chan ConntrackEvent
+}
+
+// conntrack のエントリーの作成、状態の変化、削除のイベントを購読します。
+// backendId に 0 以外を指定するとそのバックエンドに割り当てられたエントリーのイベントのみを受け取ります。
+// イベントを受け取るのが遅れてバッファが溢れた場合はチャネルが閉じられます。
+// 購読をやめるときは返り値の関数を呼び出す必要があります。
+func (l *LbBackendManager) WatchConntrack(backendId uint32) (<-chan ConntrackEvent, func()) {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w := &conntrackWatcher{
+		backendId: backendId,
+		ch:        make(chan ConntrackEvent, conntrackWatchBufferSize),
+	}
+	l.watchers[w] = struct{}{}
+
+	cancel := func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if _, ok := l.watchers[w]; ok {
+			delete(l.watchers, w)
+			close(w.ch)
+		}
+	}
+	return w.ch, cancel
+}
+
+// 購読者に conntrack のイベントを通知します。
+// conntrack の同期を止めないように、バッファが溢れている購読者は待たずに購読を解除します。
+// この関数はロックを取得した状態で呼び出す必要があります。
+func (l *LbBackendManager) notifyConntrack(typ ConntrackEventType, entry *ConntrackEntry, prev ConnectionState, reason string) {
+	if len(l.watchers) == 0 {
+		return
+	}
+
+	event := ConntrackEvent{
+		Type:      typ,
+		Entry:     *entry,
+		PrevState: prev,
+		Reason:    reason,
+		Timestamp: time.Now(),
+	}
+	if b, ok := l.backends[entry.BackendId]; ok {
+		event.BackendAddr = b.Address
+	}
+	for w := range l.watchers {
+		if w.backendId != 0 && w.backendId != entry.BackendId {
+			continue
+		}
+		select {
+		case w.ch <- event:
+		default:
+			l.logger.Warn("conntrack watcher is too slow. stop notifying events", slog.Int("backend id", int(w.backendId)))
+			delete(l.watchers, w)
+			close(w.ch)
+		}
+	}
+}
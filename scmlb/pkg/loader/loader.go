@@ -3,6 +3,9 @@ package loader
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/cilium/ebpf"
 	"github.com/cilium/ebpf/link"
@@ -30,6 +33,8 @@ const (
 	MAP_NAME_DROP_COUNTER     = "drop_counter"
 	MAP_NAME_ADV_RULE_MATCHER = "adv_rulematcher"
 	MAP_NAME_ADV_RULES        = "adv_rules"
+	MAP_NAME_ADV_RULE_LISTS   = "adv_rule_lists"
+	MAP_NAME_FW_GENERATION    = "fw_generation"
 	MAP_NAME_DOSP_COUNTER     = "dosp_counter"
 	MAP_NAME_REDIRECT_DEV_MAP = "redirect_dev_map"
 	MAP_NAME_BACKEND_IFINDEX  = "backend_ifindex"
@@ -37,8 +42,17 @@ const (
 	MAP_NAME_UPSTREAM_INFO    = "upstream_info"
 	MAP_NAME_CONNTRACK        = "conntrack"
 	MAP_NAME_RR_TABLE         = "rr_table"
+	MAP_NAME_MAGLEV_TABLE     = "maglev_table"
+	MAP_NAME_SERVICES         = "services"
+	MAP_NAME_REVERSE_SERVICE  = "reverse_service"
+	MAP_NAME_AFFINITY         = "affinity"
+	MAP_NAME_BACKEND_META     = "backend_meta"
+	MAP_NAME_DOSP_POLICIES    = "dosp_policies"
+	MAP_NAME_DOSP_FW_RULES    = "dosp_fw_rules"
 
 	PinBasePath = "/sys/fs/bpf/scmlb"
+	// XDP プログラムをアタッチした link をピン留めするディレクトリです。
+	LinkPinBasePath = PinBasePath + "/links"
 )
 
 // tail call のための calls_map にデータを反映させるための map を定義しています。
@@ -50,38 +64,131 @@ var tailCalledPrograms = map[uint32]string{
 	4: PROG_NAME_LB_EGRESS,
 }
 
+// ロード時に変更するマップの設定です。
+// 大きさが 0 のマップは bpf/include/maps.h で定義した大きさのままロードします。
+type MapConfig struct {
+	// conntrack マップの最大エントリー数です。
+	ConntrackMaxEntries uint32
+	// true の場合は conntrack マップを BPF_MAP_TYPE_LRU_HASH に変更します。
+	// エントリーが溢れたときに新しいコネクションを拒否する代わりに、最も使われていないエントリーを削除します。
+	ConntrackLRU bool
+	// バックエンドの情報を格納するマップの最大エントリー数です。
+	BackendMaxEntries uint32
+	// fire wall のルールを格納するマップの最大エントリー数です。
+	FireWallMaxEntries uint32
+}
+
+// 設定の大きさを変更するマップです。
+var (
+	conntrackMaps = []string{MAP_NAME_CONNTRACK}
+	backendMaps   = []string{MAP_NAME_BACKEND_IFINDEX, MAP_NAME_BACKEND_INFO, MAP_NAME_REVERSE_SERVICE, MAP_NAME_BACKEND_META}
+	fireWallMaps  = []string{MAP_NAME_RULES, MAP_NAME_ADV_RULES, MAP_NAME_DROP_COUNTER, MAP_NAME_DOSP_FW_RULES}
+	// ルールを一括で適用する間は新旧 2 つの世代のエントリーを格納するので、fire wall のルールの最大数の 2 倍の大きさにするマップです。
+	fireWallGenerationMaps = []string{MAP_NAME_ADV_RULE_MATCHER}
+)
+
+// ロードする前の CollectionSpec のマップの定義を設定に従って書き換えます。
+func (c *MapConfig) apply(spec *ebpf.CollectionSpec) error {
+	resize := func(names []string, size uint32) error {
+		if size == 0 {
+			return nil
+		}
+		for _, name := range names {
+			m, ok := spec.Maps[name]
+			if !ok {
+				return fmt.Errorf("map %s is not found in the collection spec", name)
+			}
+			m.MaxEntries = size
+		}
+		return nil
+	}
+
+	if err := resize(conntrackMaps, c.ConntrackMaxEntries); err != nil {
+		return err
+	}
+	if err := resize(backendMaps, c.BackendMaxEntries); err != nil {
+		return err
+	}
+	if err := resize(fireWallMaps, c.FireWallMaxEntries); err != nil {
+		return err
+	}
+	if err := resize(fireWallGenerationMaps, c.FireWallMaxEntries*2); err != nil {
+		return err
+	}
+
+	if c.ConntrackLRU {
+		m, ok := spec.Maps[MAP_NAME_CONNTRACK]
+		if !ok {
+			return fmt.Errorf("map %s is not found in the collection spec", MAP_NAME_CONNTRACK)
+		}
+		m.Type = ebpf.LRUHash
+	}
+	return nil
+}
+
 // bpf/xdp.c から生成した関数やマップの情報を保持する構造体
 type Loader struct {
 	logger   slog.Logger
 	Programs map[string]*ebpf.Program
 	Maps     map[string]*ebpf.Map
 	links    map[string]link.Link
+	// マップと link を PinBasePath にピン留めして、次回の起動時に再利用するかどうかを表します。
+	Persist bool
 }
 
 // この関数は bpf/xdp.c で定義した eBPF プログラムをカーネルにロードします
-func Load(logger slog.Logger) (*Loader, error) {
+// persist が true の場合はマップを PinBasePath にピン留めします。
+// 既にピン留めされたマップがある場合は新しく作成せずにそのマップを再利用するので、前回の起動時の conntrack などのエントリーを引き継ぐことができます。
+// mapConfig に従ってマップの大きさと種類を変更してからロードします。
+func Load(logger slog.Logger, persist bool, mapConfig MapConfig) (*Loader, error) {
 
-	// if err := os.Mkdir(PinBasePath, os.ModePerm); err != nil {
-	// return nil, err
-	// }
+	if persist {
+		if err := os.MkdirAll(LinkPinBasePath, os.ModePerm); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info("load XDP programs", slog.Bool("persist", persist), slog.Any("map config", mapConfig))
+	// loadXdpProg() は bpf2go で自動生成された関数で、ELF ファイルからプログラムとマップの定義を読み込みます
+	spec, err := loadXdpProg()
+	if err != nil {
+		return nil, err
+	}
+	if err := mapConfig.apply(spec); err != nil {
+		return nil, err
+	}
+	if persist {
+		// すべてのマップを名前でピン留めします。
+		// tail call 先のプログラムを格納している calls_map もピン留めして、再起動中もアタッチされたままのプログラムから tail call できるようにします。
+		for name, m := range spec.Maps {
+			// .rodata などのグローバル変数のためのマップは対象外です。
+			if strings.HasPrefix(name, ".") {
+				continue
+			}
+			m.Pinning = ebpf.PinByName
+		}
+	}
 
-	logger.Info("load XDP programs")
 	objects := XdpProgObjects{}
-	// LoadXdpProgObjects() は bpf2go で自動生成された関数で、これを実行することで eBPF プログラムをカーネルにロードすることができます
-	if err := LoadXdpProgObjects(&objects, &ebpf.CollectionOptions{
+	// LoadAndAssign() を実行することで eBPF プログラムをカーネルにロードすることができます
+	if err := spec.LoadAndAssign(&objects, &ebpf.CollectionOptions{
 		Programs: ebpf.ProgramOptions{
 			LogLevel: ebpf.LogLevelInstruction,
 			LogSize:  ebpf.DefaultVerifierLogSize * 256,
 		},
-		// Maps: ebpf.MapOptions{
-		// PinPath: PinBasePath,
-		// },
+		Maps: ebpf.MapOptions{
+			PinPath: PinBasePath,
+		},
 	}); err != nil {
 		var ve *ebpf.VerifierError
 		if errors.As(err, &ve) {
 			fmt.Printf("Verifier error: %+v\n", ve)
 			return nil, err
 		}
+		// マップの定義やマップの大きさの設定が変わった場合は前回ピン留めしたマップを再利用できません。
+		if errors.Is(err, ebpf.ErrMapIncompatible) {
+			return nil, fmt.Errorf("pinned maps are incompatible with this version or map config. remove %s to start without the previous state: %w", PinBasePath, err)
+		}
 		return nil, err
 	}
 
@@ -101,6 +208,8 @@ func Load(logger slog.Logger) (*Loader, error) {
 	maps[MAP_NAME_DROP_COUNTER] = objects.DropCounter
 	maps[MAP_NAME_ADV_RULE_MATCHER] = objects.AdvRulematcher
 	maps[MAP_NAME_ADV_RULES] = objects.AdvRules
+	maps[MAP_NAME_ADV_RULE_LISTS] = objects.AdvRuleLists
+	maps[MAP_NAME_FW_GENERATION] = objects.FwGeneration
 	maps[MAP_NAME_DOSP_COUNTER] = objects.DospCounter
 	maps[MAP_NAME_REDIRECT_DEV_MAP] = objects.RedirectDevMap
 	maps[MAP_NAME_BACKEND_INFO] = objects.BackendInfo
@@ -108,12 +217,20 @@ func Load(logger slog.Logger) (*Loader, error) {
 	maps[MAP_NAME_UPSTREAM_INFO] = objects.UpstreamInfo
 	maps[MAP_NAME_CONNTRACK] = objects.Conntrack
 	maps[MAP_NAME_RR_TABLE] = objects.RrTable
+	maps[MAP_NAME_MAGLEV_TABLE] = objects.MaglevTable
+	maps[MAP_NAME_SERVICES] = objects.Services
+	maps[MAP_NAME_REVERSE_SERVICE] = objects.ReverseService
+	maps[MAP_NAME_AFFINITY] = objects.Affinity
+	maps[MAP_NAME_BACKEND_META] = objects.BackendMeta
+	maps[MAP_NAME_DOSP_POLICIES] = objects.DospPolicies
+	maps[MAP_NAME_DOSP_FW_RULES] = objects.DospFwRules
 
 	return &Loader{
 		logger:   logger,
 		Programs: programs,
 		Maps:     maps,
 		links:    make(map[string]link.Link),
+		Persist:  persist,
 	}, nil
 }
 
@@ -129,24 +246,66 @@ func (l *Loader) Attach(device string) error {
 	l.logger.Info("attach a XDP entrypoint program to the upstream interface")
 
 	// 実際にプログラムをアタッチします
+	pinPath := ""
+	if l.Persist {
+		pinPath = LinkPinPath(device)
+	}
+	ll, err := AttachXDP(l.Programs[PROG_NAME_ENTRYPOINY], iface.Attrs().Index, pinPath)
+	if err != nil {
+		return err
+	}
+
+	l.links[device] = ll
+
+	return nil
+}
+
+// デバイスにアタッチした XDP プログラムの link をピン留めするパスを返します。
+func LinkPinPath(device string) string {
+	return filepath.Join(LinkPinBasePath, device)
+}
+
+// XDP プログラムをデバイスにアタッチします。
+// pinPath が指定されている場合は link をピン留めします。ピン留めされた link はプログラムが終了してもデタッチされません。
+// 既に pinPath に link がピン留めされている場合は、デタッチせずにアタッチされているプログラムを置き換えるので、パケットの処理が途切れません。
+func AttachXDP(program *ebpf.Program, ifindex int, pinPath string) (link.Link, error) {
+	if pinPath != "" {
+		ll, err := link.LoadPinnedLink(pinPath, nil)
+		if err == nil {
+			if err := ll.Update(program); err != nil {
+				ll.Close()
+				return nil, fmt.Errorf("failed to replace the program of the pinned link %s: %w", pinPath, err)
+			}
+			return ll, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
 	ll, err := link.AttachXDP(link.XDPOptions{
-		Program: l.Programs[PROG_NAME_ENTRYPOINY],
+		Program: program,
 		// アタッチしたいインターフェースのインデックスを指定します
-		Interface: iface.Attrs().Index, //
+		Interface: ifindex,
 		// ここでは Generic XDP を利用するので XDPGenericMode を指定しています
 		// Native XDP で動かしたい場合は XDPDriverMode を指定します。
 		Flags: link.XDPGenericMode,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	l.links[device] = ll
-
-	return nil
+	if pinPath != "" {
+		if err := ll.Pin(pinPath); err != nil {
+			ll.Close()
+			return nil, err
+		}
+	}
+	return ll, nil
 }
 
 // この関数は プログラム終了時にカーネルにロード、 NIC にアタッチしたXDP プログラムや eBPF マップの後片付けを行う関数です。
+// ピン留めしたマップと link はファイルディスクリプタを閉じても削除されないので、XDP プログラムはアタッチされたまま動作し続けます。
 func (l *Loader) Finalize() error {
 	errs := []error{}
 	for _, p := range l.Programs {
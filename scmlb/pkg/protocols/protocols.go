@@ -144,18 +144,16 @@ func (f TcpFlag) String() string {
 	}
 }
 
-func IpAddrFromLe(v uint32) (netip.Addr, error) {
-	b := make([]byte, 4)
-	binary.LittleEndian.PutUint32(b, v)
-	addr, ok := netip.AddrFromSlice(b)
-	if !ok {
-		return netip.Addr{}, fmt.Errorf("invalid ip address value %d", v)
-	}
-	return addr, nil
+// BPF マップに格納された 16 byte のアドレスを netip.Addr に変換します。
+// IPv4-mapped IPv6 アドレスは IPv4 アドレスとして返します。
+func IpAddrFrom16(b [16]byte) netip.Addr {
+	return netip.AddrFrom16(b).Unmap()
 }
 
-func IpAddrToU32Le(addr netip.Addr) uint32 {
-	return binary.LittleEndian.Uint32(addr.AsSlice())
+// netip.Addr を BPF マップに格納する 16 byte のアドレスに変換します。
+// IPv4 アドレスは IPv4-mapped IPv6 アドレス (::ffff:a.b.c.d) に変換されます。
+func IpAddrTo16(addr netip.Addr) [16]byte {
+	return addr.As16()
 }
 
 func Ntohs(v uint16) uint16 {
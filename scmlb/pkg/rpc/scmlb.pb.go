@@ -458,6 +458,126 @@ func (x *FireWallRuleDeleteRequest) GetId() int32 {
 	return 0
 }
 
+// rules に含まれるルールの追加と delete_ids のルールの削除を、XDP プログラムから見て一度に反映します。
+// replace が true の場合は、DoS protector が追加したルール以外のルールを rules と同じ内容に置き換えます。
+type FireWallRuleApplyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rules     []*FireWallRule `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	DeleteIds []int32         `protobuf:"varint,2,rep,packed,name=delete_ids,json=deleteIds,proto3" json:"delete_ids,omitempty"`
+	Replace   bool            `protobuf:"varint,3,opt,name=replace,proto3" json:"replace,omitempty"`
+}
+
+func (x *FireWallRuleApplyRequest) Reset() {
+	*x = FireWallRuleApplyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FireWallRuleApplyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FireWallRuleApplyRequest) ProtoMessage() {}
+
+func (x *FireWallRuleApplyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FireWallRuleApplyRequest.ProtoReflect.Descriptor instead.
+func (*FireWallRuleApplyRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *FireWallRuleApplyRequest) GetRules() []*FireWallRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *FireWallRuleApplyRequest) GetDeleteIds() []int32 {
+	if x != nil {
+		return x.DeleteIds
+	}
+	return nil
+}
+
+func (x *FireWallRuleApplyRequest) GetReplace() bool {
+	if x != nil {
+		return x.Replace
+	}
+	return false
+}
+
+type FireWallRuleApplyResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AddedIds   []int32 `protobuf:"varint,1,rep,packed,name=added_ids,json=addedIds,proto3" json:"added_ids,omitempty"`
+	DeletedIds []int32 `protobuf:"varint,2,rep,packed,name=deleted_ids,json=deletedIds,proto3" json:"deleted_ids,omitempty"`
+}
+
+func (x *FireWallRuleApplyResponse) Reset() {
+	*x = FireWallRuleApplyResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FireWallRuleApplyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FireWallRuleApplyResponse) ProtoMessage() {}
+
+func (x *FireWallRuleApplyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FireWallRuleApplyResponse.ProtoReflect.Descriptor instead.
+func (*FireWallRuleApplyResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *FireWallRuleApplyResponse) GetAddedIds() []int32 {
+	if x != nil {
+		return x.AddedIds
+	}
+	return nil
+}
+
+func (x *FireWallRuleApplyResponse) GetDeletedIds() []int32 {
+	if x != nil {
+		return x.DeletedIds
+	}
+	return nil
+}
+
 type FireWallRule struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -471,12 +591,15 @@ type FireWallRule struct {
 	ToDstPort   int32  `protobuf:"varint,6,opt,name=to_dst_port,json=toDstPort,proto3" json:"to_dst_port,omitempty"`
 	Protocol    int32  `protobuf:"varint,7,opt,name=protocol,proto3" json:"protocol,omitempty"`
 	Count       int64  `protobuf:"varint,8,opt,name=count,proto3" json:"count,omitempty"`
+	Priority    int32  `protobuf:"varint,9,opt,name=priority,proto3" json:"priority,omitempty"`
+	Action      string `protobuf:"bytes,10,opt,name=action,proto3" json:"action,omitempty"`
+	DstPrefix   string `protobuf:"bytes,11,opt,name=dst_prefix,json=dstPrefix,proto3" json:"dst_prefix,omitempty"`
 }
 
 func (x *FireWallRule) Reset() {
 	*x = FireWallRule{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_scmlb_proto_msgTypes[9]
+		mi := &file_protobuf_scmlb_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -489,7 +612,7 @@ func (x *FireWallRule) String() string {
 func (*FireWallRule) ProtoMessage() {}
 
 func (x *FireWallRule) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_scmlb_proto_msgTypes[9]
+	mi := &file_protobuf_scmlb_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -502,7 +625,7 @@ func (x *FireWallRule) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FireWallRule.ProtoReflect.Descriptor instead.
 func (*FireWallRule) Descriptor() ([]byte, []int) {
-	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{9}
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *FireWallRule) GetId() int32 {
@@ -561,6 +684,27 @@ func (x *FireWallRule) GetCount() int64 {
 	return 0
 }
 
+func (x *FireWallRule) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *FireWallRule) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *FireWallRule) GetDstPrefix() string {
+	if x != nil {
+		return x.DstPrefix
+	}
+	return ""
+}
+
 type DoSProtectionPolicySetRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -572,7 +716,7 @@ type DoSProtectionPolicySetRequest struct {
 func (x *DoSProtectionPolicySetRequest) Reset() {
 	*x = DoSProtectionPolicySetRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_scmlb_proto_msgTypes[10]
+		mi := &file_protobuf_scmlb_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -585,7 +729,7 @@ func (x *DoSProtectionPolicySetRequest) String() string {
 func (*DoSProtectionPolicySetRequest) ProtoMessage() {}
 
 func (x *DoSProtectionPolicySetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_scmlb_proto_msgTypes[10]
+	mi := &file_protobuf_scmlb_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -598,7 +742,7 @@ func (x *DoSProtectionPolicySetRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DoSProtectionPolicySetRequest.ProtoReflect.Descriptor instead.
 func (*DoSProtectionPolicySetRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{10}
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *DoSProtectionPolicySetRequest) GetPolicy() *DoSProtectionPolicy {
@@ -617,7 +761,7 @@ type DoSProtectionPolicyGetRequest struct {
 func (x *DoSProtectionPolicyGetRequest) Reset() {
 	*x = DoSProtectionPolicyGetRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_scmlb_proto_msgTypes[11]
+		mi := &file_protobuf_scmlb_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -630,7 +774,7 @@ func (x *DoSProtectionPolicyGetRequest) String() string {
 func (*DoSProtectionPolicyGetRequest) ProtoMessage() {}
 
 func (x *DoSProtectionPolicyGetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_scmlb_proto_msgTypes[11]
+	mi := &file_protobuf_scmlb_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -643,7 +787,7 @@ func (x *DoSProtectionPolicyGetRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DoSProtectionPolicyGetRequest.ProtoReflect.Descriptor instead.
 func (*DoSProtectionPolicyGetRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{11}
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{13}
 }
 
 type DoSProtectionPolicyGetResponse struct {
@@ -657,7 +801,7 @@ type DoSProtectionPolicyGetResponse struct {
 func (x *DoSProtectionPolicyGetResponse) Reset() {
 	*x = DoSProtectionPolicyGetResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_scmlb_proto_msgTypes[12]
+		mi := &file_protobuf_scmlb_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -670,7 +814,7 @@ func (x *DoSProtectionPolicyGetResponse) String() string {
 func (*DoSProtectionPolicyGetResponse) ProtoMessage() {}
 
 func (x *DoSProtectionPolicyGetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_scmlb_proto_msgTypes[12]
+	mi := &file_protobuf_scmlb_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -683,7 +827,7 @@ func (x *DoSProtectionPolicyGetResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DoSProtectionPolicyGetResponse.ProtoReflect.Descriptor instead.
 func (*DoSProtectionPolicyGetResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{12}
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *DoSProtectionPolicyGetResponse) GetPolicies() []*DoSProtectionPolicy {
@@ -704,7 +848,7 @@ type DoSProtectionPolicyDeleteRequest struct {
 func (x *DoSProtectionPolicyDeleteRequest) Reset() {
 	*x = DoSProtectionPolicyDeleteRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_scmlb_proto_msgTypes[13]
+		mi := &file_protobuf_scmlb_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -717,7 +861,7 @@ func (x *DoSProtectionPolicyDeleteRequest) String() string {
 func (*DoSProtectionPolicyDeleteRequest) ProtoMessage() {}
 
 func (x *DoSProtectionPolicyDeleteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_scmlb_proto_msgTypes[13]
+	mi := &file_protobuf_scmlb_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -730,7 +874,7 @@ func (x *DoSProtectionPolicyDeleteRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DoSProtectionPolicyDeleteRequest.ProtoReflect.Descriptor instead.
 func (*DoSProtectionPolicyDeleteRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{13}
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *DoSProtectionPolicyDeleteRequest) GetId() int32 {
@@ -756,7 +900,7 @@ type DoSProtectionPolicy struct {
 func (x *DoSProtectionPolicy) Reset() {
 	*x = DoSProtectionPolicy{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_scmlb_proto_msgTypes[14]
+		mi := &file_protobuf_scmlb_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -769,7 +913,7 @@ func (x *DoSProtectionPolicy) String() string {
 func (*DoSProtectionPolicy) ProtoMessage() {}
 
 func (x *DoSProtectionPolicy) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_scmlb_proto_msgTypes[14]
+	mi := &file_protobuf_scmlb_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -782,7 +926,7 @@ func (x *DoSProtectionPolicy) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DoSProtectionPolicy.ProtoReflect.Descriptor instead.
 func (*DoSProtectionPolicy) Descriptor() ([]byte, []int) {
-	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{14}
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *DoSProtectionPolicy) GetId() int32 {
@@ -835,12 +979,17 @@ type LoadBalancerSetRequest struct {
 	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Address     string `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
 	Healthcheck string `protobuf:"bytes,3,opt,name=healthcheck,proto3" json:"healthcheck,omitempty"`
+	Weight      int32  `protobuf:"varint,4,opt,name=weight,proto3" json:"weight,omitempty"`
+	ServiceId   int32  `protobuf:"varint,5,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Forwarding  string `protobuf:"bytes,6,opt,name=forwarding,proto3" json:"forwarding,omitempty"`
+	MacAddr     string `protobuf:"bytes,7,opt,name=mac_addr,json=macAddr,proto3" json:"mac_addr,omitempty"`
+	Device      string `protobuf:"bytes,8,opt,name=device,proto3" json:"device,omitempty"`
 }
 
 func (x *LoadBalancerSetRequest) Reset() {
 	*x = LoadBalancerSetRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_scmlb_proto_msgTypes[15]
+		mi := &file_protobuf_scmlb_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -853,7 +1002,7 @@ func (x *LoadBalancerSetRequest) String() string {
 func (*LoadBalancerSetRequest) ProtoMessage() {}
 
 func (x *LoadBalancerSetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_scmlb_proto_msgTypes[15]
+	mi := &file_protobuf_scmlb_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -866,7 +1015,7 @@ func (x *LoadBalancerSetRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoadBalancerSetRequest.ProtoReflect.Descriptor instead.
 func (*LoadBalancerSetRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{15}
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *LoadBalancerSetRequest) GetName() string {
@@ -890,6 +1039,41 @@ func (x *LoadBalancerSetRequest) GetHealthcheck() string {
 	return ""
 }
 
+func (x *LoadBalancerSetRequest) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *LoadBalancerSetRequest) GetServiceId() int32 {
+	if x != nil {
+		return x.ServiceId
+	}
+	return 0
+}
+
+func (x *LoadBalancerSetRequest) GetForwarding() string {
+	if x != nil {
+		return x.Forwarding
+	}
+	return ""
+}
+
+func (x *LoadBalancerSetRequest) GetMacAddr() string {
+	if x != nil {
+		return x.MacAddr
+	}
+	return ""
+}
+
+func (x *LoadBalancerSetRequest) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
 type LoadBalancerGetRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -899,7 +1083,7 @@ type LoadBalancerGetRequest struct {
 func (x *LoadBalancerGetRequest) Reset() {
 	*x = LoadBalancerGetRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_scmlb_proto_msgTypes[16]
+		mi := &file_protobuf_scmlb_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -912,7 +1096,7 @@ func (x *LoadBalancerGetRequest) String() string {
 func (*LoadBalancerGetRequest) ProtoMessage() {}
 
 func (x *LoadBalancerGetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_scmlb_proto_msgTypes[16]
+	mi := &file_protobuf_scmlb_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -925,7 +1109,7 @@ func (x *LoadBalancerGetRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoadBalancerGetRequest.ProtoReflect.Descriptor instead.
 func (*LoadBalancerGetRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{16}
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{18}
 }
 
 type LoadBalancerGetResponse struct {
@@ -939,7 +1123,7 @@ type LoadBalancerGetResponse struct {
 func (x *LoadBalancerGetResponse) Reset() {
 	*x = LoadBalancerGetResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_scmlb_proto_msgTypes[17]
+		mi := &file_protobuf_scmlb_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -952,7 +1136,7 @@ func (x *LoadBalancerGetResponse) String() string {
 func (*LoadBalancerGetResponse) ProtoMessage() {}
 
 func (x *LoadBalancerGetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_scmlb_proto_msgTypes[17]
+	mi := &file_protobuf_scmlb_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -965,7 +1149,7 @@ func (x *LoadBalancerGetResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoadBalancerGetResponse.ProtoReflect.Descriptor instead.
 func (*LoadBalancerGetResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{17}
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *LoadBalancerGetResponse) GetBackends() []*LoadBalancerBackend {
@@ -986,7 +1170,7 @@ type LoadBalancerDeleteRequest struct {
 func (x *LoadBalancerDeleteRequest) Reset() {
 	*x = LoadBalancerDeleteRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_scmlb_proto_msgTypes[18]
+		mi := &file_protobuf_scmlb_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -999,7 +1183,7 @@ func (x *LoadBalancerDeleteRequest) String() string {
 func (*LoadBalancerDeleteRequest) ProtoMessage() {}
 
 func (x *LoadBalancerDeleteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_scmlb_proto_msgTypes[18]
+	mi := &file_protobuf_scmlb_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1012,7 +1196,7 @@ func (x *LoadBalancerDeleteRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoadBalancerDeleteRequest.ProtoReflect.Descriptor instead.
 func (*LoadBalancerDeleteRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{18}
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *LoadBalancerDeleteRequest) GetId() int32 {
@@ -1027,13 +1211,14 @@ type LoadBalancerDrainRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Id       int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Deadline int64 `protobuf:"varint,2,opt,name=deadline,proto3" json:"deadline,omitempty"`
 }
 
 func (x *LoadBalancerDrainRequest) Reset() {
 	*x = LoadBalancerDrainRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_scmlb_proto_msgTypes[19]
+		mi := &file_protobuf_scmlb_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1046,7 +1231,7 @@ func (x *LoadBalancerDrainRequest) String() string {
 func (*LoadBalancerDrainRequest) ProtoMessage() {}
 
 func (x *LoadBalancerDrainRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_scmlb_proto_msgTypes[19]
+	mi := &file_protobuf_scmlb_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1059,7 +1244,7 @@ func (x *LoadBalancerDrainRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoadBalancerDrainRequest.ProtoReflect.Descriptor instead.
 func (*LoadBalancerDrainRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{19}
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *LoadBalancerDrainRequest) GetId() int32 {
@@ -1069,37 +1254,38 @@ func (x *LoadBalancerDrainRequest) GetId() int32 {
 	return 0
 }
 
-type LoadBalancerBackend struct {
+func (x *LoadBalancerDrainRequest) GetDeadline() int64 {
+	if x != nil {
+		return x.Deadline
+	}
+	return 0
+}
+
+type LoadBalancerDrainWaitRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id          int32  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name        string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	DevName     string `protobuf:"bytes,3,opt,name=dev_name,json=devName,proto3" json:"dev_name,omitempty"`
-	IpAddr      string `protobuf:"bytes,4,opt,name=ip_addr,json=ipAddr,proto3" json:"ip_addr,omitempty"`
-	MacAddr     string `protobuf:"bytes,5,opt,name=mac_addr,json=macAddr,proto3" json:"mac_addr,omitempty"`
-	Healthcheck string `protobuf:"bytes,6,opt,name=healthcheck,proto3" json:"healthcheck,omitempty"`
-	Status      int32  `protobuf:"varint,7,opt,name=status,proto3" json:"status,omitempty"`
+	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (x *LoadBalancerBackend) Reset() {
-	*x = LoadBalancerBackend{}
+func (x *LoadBalancerDrainWaitRequest) Reset() {
+	*x = LoadBalancerDrainWaitRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_scmlb_proto_msgTypes[20]
+		mi := &file_protobuf_scmlb_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *LoadBalancerBackend) String() string {
+func (x *LoadBalancerDrainWaitRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LoadBalancerBackend) ProtoMessage() {}
+func (*LoadBalancerDrainWaitRequest) ProtoMessage() {}
 
-func (x *LoadBalancerBackend) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_scmlb_proto_msgTypes[20]
+func (x *LoadBalancerDrainWaitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1110,48 +1296,177 @@ func (x *LoadBalancerBackend) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LoadBalancerBackend.ProtoReflect.Descriptor instead.
-func (*LoadBalancerBackend) Descriptor() ([]byte, []int) {
-	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use LoadBalancerDrainWaitRequest.ProtoReflect.Descriptor instead.
+func (*LoadBalancerDrainWaitRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *LoadBalancerBackend) GetId() int32 {
+func (x *LoadBalancerDrainWaitRequest) GetId() int32 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *LoadBalancerBackend) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
+type LoadBalancerDrainStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Remaining int32                  `protobuf:"varint,2,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	Deadline  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	Completed bool                   `protobuf:"varint,4,opt,name=completed,proto3" json:"completed,omitempty"`
 }
 
-func (x *LoadBalancerBackend) GetDevName() string {
-	if x != nil {
-		return x.DevName
+func (x *LoadBalancerDrainStatus) Reset() {
+	*x = LoadBalancerDrainStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return ""
 }
 
-func (x *LoadBalancerBackend) GetIpAddr() string {
-	if x != nil {
-		return x.IpAddr
-	}
-	return ""
+func (x *LoadBalancerDrainStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *LoadBalancerBackend) GetMacAddr() string {
-	if x != nil {
-		return x.MacAddr
+func (*LoadBalancerDrainStatus) ProtoMessage() {}
+
+func (x *LoadBalancerDrainStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *LoadBalancerBackend) GetHealthcheck() string {
-	if x != nil {
+// Deprecated: Use LoadBalancerDrainStatus.ProtoReflect.Descriptor instead.
+func (*LoadBalancerDrainStatus) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *LoadBalancerDrainStatus) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *LoadBalancerDrainStatus) GetRemaining() int32 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *LoadBalancerDrainStatus) GetDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+func (x *LoadBalancerDrainStatus) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+type LoadBalancerBackend struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id                int32  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name              string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	DevName           string `protobuf:"bytes,3,opt,name=dev_name,json=devName,proto3" json:"dev_name,omitempty"`
+	IpAddr            string `protobuf:"bytes,4,opt,name=ip_addr,json=ipAddr,proto3" json:"ip_addr,omitempty"`
+	MacAddr           string `protobuf:"bytes,5,opt,name=mac_addr,json=macAddr,proto3" json:"mac_addr,omitempty"`
+	Healthcheck       string `protobuf:"bytes,6,opt,name=healthcheck,proto3" json:"healthcheck,omitempty"`
+	Status            int32  `protobuf:"varint,7,opt,name=status,proto3" json:"status,omitempty"`
+	Health            int32  `protobuf:"varint,8,opt,name=health,proto3" json:"health,omitempty"`
+	Weight            int32  `protobuf:"varint,9,opt,name=weight,proto3" json:"weight,omitempty"`
+	ServiceId         int32  `protobuf:"varint,10,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	ActiveConnections int32  `protobuf:"varint,11,opt,name=active_connections,json=activeConnections,proto3" json:"active_connections,omitempty"`
+	Forwarding        string `protobuf:"bytes,12,opt,name=forwarding,proto3" json:"forwarding,omitempty"`
+}
+
+func (x *LoadBalancerBackend) Reset() {
+	*x = LoadBalancerBackend{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadBalancerBackend) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadBalancerBackend) ProtoMessage() {}
+
+func (x *LoadBalancerBackend) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadBalancerBackend.ProtoReflect.Descriptor instead.
+func (*LoadBalancerBackend) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *LoadBalancerBackend) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *LoadBalancerBackend) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LoadBalancerBackend) GetDevName() string {
+	if x != nil {
+		return x.DevName
+	}
+	return ""
+}
+
+func (x *LoadBalancerBackend) GetIpAddr() string {
+	if x != nil {
+		return x.IpAddr
+	}
+	return ""
+}
+
+func (x *LoadBalancerBackend) GetMacAddr() string {
+	if x != nil {
+		return x.MacAddr
+	}
+	return ""
+}
+
+func (x *LoadBalancerBackend) GetHealthcheck() string {
+	if x != nil {
 		return x.Healthcheck
 	}
 	return ""
@@ -1164,16 +1479,62 @@ func (x *LoadBalancerBackend) GetStatus() int32 {
 	return 0
 }
 
+func (x *LoadBalancerBackend) GetHealth() int32 {
+	if x != nil {
+		return x.Health
+	}
+	return 0
+}
+
+func (x *LoadBalancerBackend) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *LoadBalancerBackend) GetServiceId() int32 {
+	if x != nil {
+		return x.ServiceId
+	}
+	return 0
+}
+
+func (x *LoadBalancerBackend) GetActiveConnections() int32 {
+	if x != nil {
+		return x.ActiveConnections
+	}
+	return 0
+}
+
+func (x *LoadBalancerBackend) GetForwarding() string {
+	if x != nil {
+		return x.Forwarding
+	}
+	return ""
+}
+
 type LoadBalancerConntrackGetRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	BackendId int32   `protobuf:"varint,1,opt,name=backend_id,json=backendId,proto3" json:"backend_id,omitempty"`
+	Protocol  int32   `protobuf:"varint,2,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	States    []int32 `protobuf:"varint,3,rep,packed,name=states,proto3" json:"states,omitempty"`
+	SrcPrefix string  `protobuf:"bytes,4,opt,name=src_prefix,json=srcPrefix,proto3" json:"src_prefix,omitempty"`
+	DstPort   int32   `protobuf:"varint,5,opt,name=dst_port,json=dstPort,proto3" json:"dst_port,omitempty"`
+	MinIdle   int64   `protobuf:"varint,6,opt,name=min_idle,json=minIdle,proto3" json:"min_idle,omitempty"`
+	SortBy    string  `protobuf:"bytes,7,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	Reverse   bool    `protobuf:"varint,8,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	PageSize  int32   `protobuf:"varint,9,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string  `protobuf:"bytes,10,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 }
 
 func (x *LoadBalancerConntrackGetRequest) Reset() {
 	*x = LoadBalancerConntrackGetRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_scmlb_proto_msgTypes[21]
+		mi := &file_protobuf_scmlb_proto_msgTypes[25]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1186,7 +1547,7 @@ func (x *LoadBalancerConntrackGetRequest) String() string {
 func (*LoadBalancerConntrackGetRequest) ProtoMessage() {}
 
 func (x *LoadBalancerConntrackGetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_scmlb_proto_msgTypes[21]
+	mi := &file_protobuf_scmlb_proto_msgTypes[25]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1199,7 +1560,77 @@ func (x *LoadBalancerConntrackGetRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoadBalancerConntrackGetRequest.ProtoReflect.Descriptor instead.
 func (*LoadBalancerConntrackGetRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{21}
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *LoadBalancerConntrackGetRequest) GetBackendId() int32 {
+	if x != nil {
+		return x.BackendId
+	}
+	return 0
+}
+
+func (x *LoadBalancerConntrackGetRequest) GetProtocol() int32 {
+	if x != nil {
+		return x.Protocol
+	}
+	return 0
+}
+
+func (x *LoadBalancerConntrackGetRequest) GetStates() []int32 {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *LoadBalancerConntrackGetRequest) GetSrcPrefix() string {
+	if x != nil {
+		return x.SrcPrefix
+	}
+	return ""
+}
+
+func (x *LoadBalancerConntrackGetRequest) GetDstPort() int32 {
+	if x != nil {
+		return x.DstPort
+	}
+	return 0
+}
+
+func (x *LoadBalancerConntrackGetRequest) GetMinIdle() int64 {
+	if x != nil {
+		return x.MinIdle
+	}
+	return 0
+}
+
+func (x *LoadBalancerConntrackGetRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *LoadBalancerConntrackGetRequest) GetReverse() bool {
+	if x != nil {
+		return x.Reverse
+	}
+	return false
+}
+
+func (x *LoadBalancerConntrackGetRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *LoadBalancerConntrackGetRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
 }
 
 type LoadBalancerConntrackGetResponse struct {
@@ -1207,13 +1638,15 @@ type LoadBalancerConntrackGetResponse struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Entries []*ConntrackEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	Entries       []*ConntrackEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	NextPageToken string            `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	Total         int32             `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
 }
 
 func (x *LoadBalancerConntrackGetResponse) Reset() {
 	*x = LoadBalancerConntrackGetResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_scmlb_proto_msgTypes[22]
+		mi := &file_protobuf_scmlb_proto_msgTypes[26]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1226,7 +1659,7 @@ func (x *LoadBalancerConntrackGetResponse) String() string {
 func (*LoadBalancerConntrackGetResponse) ProtoMessage() {}
 
 func (x *LoadBalancerConntrackGetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_scmlb_proto_msgTypes[22]
+	mi := &file_protobuf_scmlb_proto_msgTypes[26]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1239,7 +1672,7 @@ func (x *LoadBalancerConntrackGetResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoadBalancerConntrackGetResponse.ProtoReflect.Descriptor instead.
 func (*LoadBalancerConntrackGetResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{22}
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *LoadBalancerConntrackGetResponse) GetEntries() []*ConntrackEntry {
@@ -1249,26 +1682,44 @@ func (x *LoadBalancerConntrackGetResponse) GetEntries() []*ConntrackEntry {
 	return nil
 }
 
+func (x *LoadBalancerConntrackGetResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *LoadBalancerConntrackGetResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
 type ConntrackEntry struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SrcAddr   string                 `protobuf:"bytes,1,opt,name=src_addr,json=srcAddr,proto3" json:"src_addr,omitempty"`
-	DstAddr   string                 `protobuf:"bytes,2,opt,name=dst_addr,json=dstAddr,proto3" json:"dst_addr,omitempty"`
-	SrcPort   int32                  `protobuf:"varint,3,opt,name=src_port,json=srcPort,proto3" json:"src_port,omitempty"`
-	DstPort   int32                  `protobuf:"varint,4,opt,name=dst_port,json=dstPort,proto3" json:"dst_port,omitempty"`
-	Protocol  int32                  `protobuf:"varint,5,opt,name=protocol,proto3" json:"protocol,omitempty"`
-	Status    int32                  `protobuf:"varint,6,opt,name=status,proto3" json:"status,omitempty"`
-	Timestamp *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	BackendId int32                  `protobuf:"varint,8,opt,name=backend_id,json=backendId,proto3" json:"backend_id,omitempty"`
-	Counter   uint64                 `protobuf:"varint,9,opt,name=counter,proto3" json:"counter,omitempty"`
+	SrcAddr       string                 `protobuf:"bytes,1,opt,name=src_addr,json=srcAddr,proto3" json:"src_addr,omitempty"`
+	DstAddr       string                 `protobuf:"bytes,2,opt,name=dst_addr,json=dstAddr,proto3" json:"dst_addr,omitempty"`
+	SrcPort       int32                  `protobuf:"varint,3,opt,name=src_port,json=srcPort,proto3" json:"src_port,omitempty"`
+	DstPort       int32                  `protobuf:"varint,4,opt,name=dst_port,json=dstPort,proto3" json:"dst_port,omitempty"`
+	Protocol      int32                  `protobuf:"varint,5,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Status        int32                  `protobuf:"varint,6,opt,name=status,proto3" json:"status,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	BackendId     int32                  `protobuf:"varint,8,opt,name=backend_id,json=backendId,proto3" json:"backend_id,omitempty"`
+	Counter       uint64                 `protobuf:"varint,9,opt,name=counter,proto3" json:"counter,omitempty"`
+	EgressPackets uint64                 `protobuf:"varint,10,opt,name=egress_packets,json=egressPackets,proto3" json:"egress_packets,omitempty"`
+	IngressBytes  uint64                 `protobuf:"varint,11,opt,name=ingress_bytes,json=ingressBytes,proto3" json:"ingress_bytes,omitempty"`
+	EgressBytes   uint64                 `protobuf:"varint,12,opt,name=egress_bytes,json=egressBytes,proto3" json:"egress_bytes,omitempty"`
+	FirstSeen     *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"`
 }
 
 func (x *ConntrackEntry) Reset() {
 	*x = ConntrackEntry{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protobuf_scmlb_proto_msgTypes[23]
+		mi := &file_protobuf_scmlb_proto_msgTypes[27]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1281,393 +1732,2210 @@ func (x *ConntrackEntry) String() string {
 func (*ConntrackEntry) ProtoMessage() {}
 
 func (x *ConntrackEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_scmlb_proto_msgTypes[23]
+	mi := &file_protobuf_scmlb_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
 		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use ConntrackEntry.ProtoReflect.Descriptor instead.
-func (*ConntrackEntry) Descriptor() ([]byte, []int) {
-	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{23}
-}
-
-func (x *ConntrackEntry) GetSrcAddr() string {
-	if x != nil {
-		return x.SrcAddr
-	}
-	return ""
-}
-
-func (x *ConntrackEntry) GetDstAddr() string {
-	if x != nil {
-		return x.DstAddr
-	}
-	return ""
-}
-
-func (x *ConntrackEntry) GetSrcPort() int32 {
-	if x != nil {
-		return x.SrcPort
-	}
-	return 0
-}
-
-func (x *ConntrackEntry) GetDstPort() int32 {
-	if x != nil {
-		return x.DstPort
-	}
-	return 0
-}
-
-func (x *ConntrackEntry) GetProtocol() int32 {
-	if x != nil {
-		return x.Protocol
-	}
-	return 0
-}
-
-func (x *ConntrackEntry) GetStatus() int32 {
-	if x != nil {
-		return x.Status
-	}
-	return 0
-}
-
-func (x *ConntrackEntry) GetTimestamp() *timestamppb.Timestamp {
-	if x != nil {
-		return x.Timestamp
-	}
-	return nil
-}
-
-func (x *ConntrackEntry) GetBackendId() int32 {
-	if x != nil {
-		return x.BackendId
-	}
-	return 0
-}
-
-func (x *ConntrackEntry) GetCounter() uint64 {
-	if x != nil {
-		return x.Counter
-	}
-	return 0
-}
-
-var File_protobuf_scmlb_proto protoreflect.FileDescriptor
-
-var file_protobuf_scmlb_proto_rawDesc = []byte{
-	0x0a, 0x14, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x63, 0x6d, 0x6c, 0x62,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31,
-	0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74,
-	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x0f,
-	0x0a, 0x0d, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
-	0x0d, 0x0a, 0x0b, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x3b,
-	0x0a, 0x0c, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b,
-	0x0a, 0x06, 0x69, 0x66, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13,
-	0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66,
-	0x61, 0x63, 0x65, 0x52, 0x06, 0x69, 0x66, 0x61, 0x63, 0x65, 0x73, 0x22, 0x84, 0x01, 0x0a, 0x09,
-	0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a,
-	0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e,
-	0x64, 0x65, 0x78, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x75, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12,
-	0x31, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x17, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x63, 0x6b,
-	0x65, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74,
-	0x65, 0x72, 0x22, 0x47, 0x0a, 0x0d, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x43, 0x6f, 0x75, 0x6e,
-	0x74, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x63, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x04, 0x69, 0x63, 0x6d, 0x70, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x63, 0x70, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x74, 0x63, 0x70, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x64, 0x70,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x75, 0x64, 0x70, 0x22, 0x43, 0x0a, 0x15, 0x46,
-	0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x52, 0x71,
-	0x65, 0x75, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69,
-	0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x04, 0x72, 0x75, 0x6c, 0x65,
-	0x22, 0x18, 0x0a, 0x16, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65,
-	0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x47, 0x0a, 0x17, 0x46, 0x69,
-	0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e,
-	0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75,
-	0x6c, 0x65, 0x73, 0x22, 0x2b, 0x0a, 0x19, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52,
-	0x75, 0x6c, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64,
-	0x22, 0xf0, 0x01, 0x0a, 0x0c, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c,
-	0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69,
-	0x64, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x22, 0x0a, 0x0d, 0x66, 0x72, 0x6f,
-	0x6d, 0x5f, 0x73, 0x72, 0x63, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x0b, 0x66, 0x72, 0x6f, 0x6d, 0x53, 0x72, 0x63, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x1e, 0x0a,
-	0x0b, 0x74, 0x6f, 0x5f, 0x73, 0x72, 0x63, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x09, 0x74, 0x6f, 0x53, 0x72, 0x63, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x22, 0x0a,
-	0x0d, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x64, 0x73, 0x74, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x66, 0x72, 0x6f, 0x6d, 0x44, 0x73, 0x74, 0x50, 0x6f, 0x72,
-	0x74, 0x12, 0x1e, 0x0a, 0x0b, 0x74, 0x6f, 0x5f, 0x64, 0x73, 0x74, 0x5f, 0x70, 0x6f, 0x72, 0x74,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x74, 0x6f, 0x44, 0x73, 0x74, 0x50, 0x6f, 0x72,
-	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x07, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x14, 0x0a,
-	0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x6f,
-	0x75, 0x6e, 0x74, 0x22, 0x56, 0x0a, 0x1d, 0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x53, 0x65, 0x74, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x35, 0x0a, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e,
-	0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c,
-	0x69, 0x63, 0x79, 0x52, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x22, 0x1f, 0x0a, 0x1d, 0x44,
-	0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69,
-	0x63, 0x79, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x5b, 0x0a, 0x1e,
-	0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c,
-	0x69, 0x63, 0x79, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x39,
-	0x0a, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x1d, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x53, 0x50,
-	0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52,
-	0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x22, 0x32, 0x0a, 0x20, 0x44, 0x6f, 0x53,
-	0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a,
-	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0xa3, 0x01,
-	0x0a, 0x13, 0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50,
-	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
-	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
-	0x6c, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x12, 0x1e, 0x0a, 0x0b, 0x66, 0x77, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x5f, 0x69,
-	0x64, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x05, 0x52, 0x09, 0x66, 0x77, 0x52, 0x75, 0x6c, 0x65,
-	0x49, 0x64, 0x73, 0x22, 0x68, 0x0a, 0x16, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e,
-	0x63, 0x65, 0x72, 0x53, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x68,
-	0x65, 0x61, 0x6c, 0x74, 0x68, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0b, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x22, 0x18, 0x0a,
-	0x16, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x47, 0x65, 0x74,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x54, 0x0a, 0x17, 0x4c, 0x6f, 0x61, 0x64, 0x42,
-	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x39, 0x0a, 0x08, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e,
-	0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x42, 0x61, 0x63, 0x6b,
-	0x65, 0x6e, 0x64, 0x52, 0x08, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x73, 0x22, 0x2b, 0x0a,
-	0x19, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x44, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2a, 0x0a, 0x18, 0x4c, 0x6f,
-	0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0xc2, 0x01, 0x0a, 0x13, 0x4c, 0x6f, 0x61, 0x64, 0x42,
-	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x42, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x12, 0x0e,
-	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12,
-	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x64, 0x65, 0x76, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x65, 0x76, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a,
-	0x07, 0x69, 0x70, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
-	0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x61, 0x63, 0x5f, 0x61, 0x64,
-	0x64, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x61, 0x63, 0x41, 0x64, 0x64,
-	0x72, 0x12, 0x20, 0x0a, 0x0b, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x63, 0x68, 0x65, 0x63, 0x6b,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x63, 0x68,
-	0x65, 0x63, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x07, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x21, 0x0a, 0x1f, 0x4c,
-	0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x74,
-	0x72, 0x61, 0x63, 0x6b, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x56,
-	0x0a, 0x20, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x43, 0x6f,
-	0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x32, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x43,
-	0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65,
-	0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0xa3, 0x02, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x6e, 0x74,
-	0x72, 0x61, 0x63, 0x6b, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x72, 0x63,
-	0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x72, 0x63,
-	0x41, 0x64, 0x64, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x64, 0x73, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x73, 0x74, 0x41, 0x64, 0x64, 0x72, 0x12,
-	0x19, 0x0a, 0x08, 0x73, 0x72, 0x63, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x07, 0x73, 0x72, 0x63, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x64, 0x73,
-	0x74, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x64, 0x73,
-	0x74, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
-	0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
-	0x6c, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d,
-	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
-	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x5f, 0x69,
-	0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64,
-	0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x09, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x32, 0xd8, 0x08, 0x0a,
-	0x08, 0x53, 0x63, 0x6d, 0x4c, 0x62, 0x41, 0x70, 0x69, 0x12, 0x39, 0x0a, 0x06, 0x48, 0x65, 0x61,
-	0x6c, 0x74, 0x68, 0x12, 0x17, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x48,
-	0x65, 0x61, 0x6c, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
-	0x6d, 0x70, 0x74, 0x79, 0x12, 0x35, 0x0a, 0x04, 0x53, 0x74, 0x61, 0x74, 0x12, 0x15, 0x2e, 0x73,
-	0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x53,
-	0x74, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0f, 0x46,
-	0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x12, 0x1f,
-	0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61,
-	0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x52, 0x71, 0x65, 0x75, 0x73, 0x74, 0x1a,
-	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x56, 0x0a, 0x0f, 0x46, 0x69, 0x72, 0x65, 0x57,
-	0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x47, 0x65, 0x74, 0x12, 0x20, 0x2e, 0x73, 0x63, 0x6d,
-	0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75,
-	0x6c, 0x65, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x73,
-	0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c,
-	0x52, 0x75, 0x6c, 0x65, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x51, 0x0a, 0x12, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x44,
-	0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x23, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31,
-	0x2e, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x44, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
-	0x74, 0x79, 0x12, 0x59, 0x0a, 0x16, 0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74,
-	0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x53, 0x65, 0x74, 0x12, 0x27, 0x2e, 0x73,
-	0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x53, 0x65, 0x74, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x6b, 0x0a,
-	0x16, 0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f,
-	0x6c, 0x69, 0x63, 0x79, 0x47, 0x65, 0x74, 0x12, 0x27, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e,
-	0x76, 0x31, 0x2e, 0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x28, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x53, 0x50,
-	0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x47,
-	0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5f, 0x0a, 0x19, 0x44, 0x6f,
-	0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63,
-	0x79, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x2a, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e,
-	0x76, 0x31, 0x2e, 0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x4b, 0x0a, 0x0f, 0x4c,
-	0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x53, 0x65, 0x74, 0x12, 0x20,
-	0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61,
-	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x53, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x56, 0x0a, 0x0f, 0x4c, 0x6f, 0x61, 0x64,
-	0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x47, 0x65, 0x74, 0x12, 0x20, 0x2e, 0x73, 0x63,
-	0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e,
-	0x63, 0x65, 0x72, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e,
-	0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c,
-	0x61, 0x6e, 0x63, 0x65, 0x72, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x51, 0x0a, 0x12, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x23, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76,
-	0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
-	0x70, 0x74, 0x79, 0x12, 0x4f, 0x0a, 0x11, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e,
-	0x63, 0x65, 0x72, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x12, 0x22, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62,
-	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72,
-	0x44, 0x72, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
-	0x6d, 0x70, 0x74, 0x79, 0x12, 0x71, 0x0a, 0x18, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61,
-	0x6e, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x47, 0x65, 0x74,
-	0x12, 0x29, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64,
-	0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63,
-	0x6b, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x73, 0x63,
-	0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e,
-	0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x47, 0x65, 0x74, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2b, 0x5a, 0x29, 0x67, 0x69, 0x74, 0x68, 0x75,
-	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x65, 0x72, 0x61, 0x73, 0x73, 0x79, 0x69, 0x2f, 0x73,
-	0x65, 0x63, 0x63, 0x61, 0x6d, 0x70, 0x2d, 0x78, 0x64, 0x70, 0x2f, 0x73, 0x63, 0x6d, 0x6c, 0x62,
-	0x2f, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
-
-var (
-	file_protobuf_scmlb_proto_rawDescOnce sync.Once
-	file_protobuf_scmlb_proto_rawDescData = file_protobuf_scmlb_proto_rawDesc
-)
-
-func file_protobuf_scmlb_proto_rawDescGZIP() []byte {
-	file_protobuf_scmlb_proto_rawDescOnce.Do(func() {
-		file_protobuf_scmlb_proto_rawDescData = protoimpl.X.CompressGZIP(file_protobuf_scmlb_proto_rawDescData)
-	})
-	return file_protobuf_scmlb_proto_rawDescData
-}
-
-var file_protobuf_scmlb_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
-var file_protobuf_scmlb_proto_goTypes = []interface{}{
-	(*HealthRequest)(nil),                    // 0: scmlb.v1.HealthRequest
-	(*StatRequest)(nil),                      // 1: scmlb.v1.StatRequest
-	(*StatResponse)(nil),                     // 2: scmlb.v1.StatResponse
-	(*Interface)(nil),                        // 3: scmlb.v1.Interface
-	(*PacketCounter)(nil),                    // 4: scmlb.v1.PacketCounter
-	(*FireWallRuleSetRqeust)(nil),            // 5: scmlb.v1.FireWallRuleSetRqeust
-	(*FireWallRuleGetRequest)(nil),           // 6: scmlb.v1.FireWallRuleGetRequest
-	(*FireWallRuleGetResponse)(nil),          // 7: scmlb.v1.FireWallRuleGetResponse
-	(*FireWallRuleDeleteRequest)(nil),        // 8: scmlb.v1.FireWallRuleDeleteRequest
-	(*FireWallRule)(nil),                     // 9: scmlb.v1.FireWallRule
-	(*DoSProtectionPolicySetRequest)(nil),    // 10: scmlb.v1.DoSProtectionPolicySetRequest
-	(*DoSProtectionPolicyGetRequest)(nil),    // 11: scmlb.v1.DoSProtectionPolicyGetRequest
-	(*DoSProtectionPolicyGetResponse)(nil),   // 12: scmlb.v1.DoSProtectionPolicyGetResponse
-	(*DoSProtectionPolicyDeleteRequest)(nil), // 13: scmlb.v1.DoSProtectionPolicyDeleteRequest
-	(*DoSProtectionPolicy)(nil),              // 14: scmlb.v1.DoSProtectionPolicy
-	(*LoadBalancerSetRequest)(nil),           // 15: scmlb.v1.LoadBalancerSetRequest
-	(*LoadBalancerGetRequest)(nil),           // 16: scmlb.v1.LoadBalancerGetRequest
-	(*LoadBalancerGetResponse)(nil),          // 17: scmlb.v1.LoadBalancerGetResponse
-	(*LoadBalancerDeleteRequest)(nil),        // 18: scmlb.v1.LoadBalancerDeleteRequest
-	(*LoadBalancerDrainRequest)(nil),         // 19: scmlb.v1.LoadBalancerDrainRequest
-	(*LoadBalancerBackend)(nil),              // 20: scmlb.v1.LoadBalancerBackend
-	(*LoadBalancerConntrackGetRequest)(nil),  // 21: scmlb.v1.LoadBalancerConntrackGetRequest
-	(*LoadBalancerConntrackGetResponse)(nil), // 22: scmlb.v1.LoadBalancerConntrackGetResponse
-	(*ConntrackEntry)(nil),                   // 23: scmlb.v1.ConntrackEntry
-	(*timestamppb.Timestamp)(nil),            // 24: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),                    // 25: google.protobuf.Empty
-}
-var file_protobuf_scmlb_proto_depIdxs = []int32{
-	3,  // 0: scmlb.v1.StatResponse.ifaces:type_name -> scmlb.v1.Interface
-	4,  // 1: scmlb.v1.Interface.counter:type_name -> scmlb.v1.PacketCounter
-	9,  // 2: scmlb.v1.FireWallRuleSetRqeust.rule:type_name -> scmlb.v1.FireWallRule
-	9,  // 3: scmlb.v1.FireWallRuleGetResponse.rules:type_name -> scmlb.v1.FireWallRule
-	14, // 4: scmlb.v1.DoSProtectionPolicySetRequest.policy:type_name -> scmlb.v1.DoSProtectionPolicy
-	14, // 5: scmlb.v1.DoSProtectionPolicyGetResponse.policies:type_name -> scmlb.v1.DoSProtectionPolicy
-	20, // 6: scmlb.v1.LoadBalancerGetResponse.backends:type_name -> scmlb.v1.LoadBalancerBackend
-	23, // 7: scmlb.v1.LoadBalancerConntrackGetResponse.entries:type_name -> scmlb.v1.ConntrackEntry
-	24, // 8: scmlb.v1.ConntrackEntry.timestamp:type_name -> google.protobuf.Timestamp
-	0,  // 9: scmlb.v1.ScmLbApi.Health:input_type -> scmlb.v1.HealthRequest
-	1,  // 10: scmlb.v1.ScmLbApi.Stat:input_type -> scmlb.v1.StatRequest
-	5,  // 11: scmlb.v1.ScmLbApi.FireWallRuleSet:input_type -> scmlb.v1.FireWallRuleSetRqeust
-	6,  // 12: scmlb.v1.ScmLbApi.FireWallRuleGet:input_type -> scmlb.v1.FireWallRuleGetRequest
-	8,  // 13: scmlb.v1.ScmLbApi.FireWallRuleDelete:input_type -> scmlb.v1.FireWallRuleDeleteRequest
-	10, // 14: scmlb.v1.ScmLbApi.DoSProtectionPolicySet:input_type -> scmlb.v1.DoSProtectionPolicySetRequest
-	11, // 15: scmlb.v1.ScmLbApi.DoSProtectionPolicyGet:input_type -> scmlb.v1.DoSProtectionPolicyGetRequest
-	13, // 16: scmlb.v1.ScmLbApi.DoSProtectionPolicyDelete:input_type -> scmlb.v1.DoSProtectionPolicyDeleteRequest
-	15, // 17: scmlb.v1.ScmLbApi.LoadBalancerSet:input_type -> scmlb.v1.LoadBalancerSetRequest
-	16, // 18: scmlb.v1.ScmLbApi.LoadBalancerGet:input_type -> scmlb.v1.LoadBalancerGetRequest
-	18, // 19: scmlb.v1.ScmLbApi.LoadBalancerDelete:input_type -> scmlb.v1.LoadBalancerDeleteRequest
-	19, // 20: scmlb.v1.ScmLbApi.LoadBalancerDrain:input_type -> scmlb.v1.LoadBalancerDrainRequest
-	21, // 21: scmlb.v1.ScmLbApi.LoadBalancerConntrackGet:input_type -> scmlb.v1.LoadBalancerConntrackGetRequest
-	25, // 22: scmlb.v1.ScmLbApi.Health:output_type -> google.protobuf.Empty
-	2,  // 23: scmlb.v1.ScmLbApi.Stat:output_type -> scmlb.v1.StatResponse
-	25, // 24: scmlb.v1.ScmLbApi.FireWallRuleSet:output_type -> google.protobuf.Empty
-	7,  // 25: scmlb.v1.ScmLbApi.FireWallRuleGet:output_type -> scmlb.v1.FireWallRuleGetResponse
-	25, // 26: scmlb.v1.ScmLbApi.FireWallRuleDelete:output_type -> google.protobuf.Empty
-	25, // 27: scmlb.v1.ScmLbApi.DoSProtectionPolicySet:output_type -> google.protobuf.Empty
-	12, // 28: scmlb.v1.ScmLbApi.DoSProtectionPolicyGet:output_type -> scmlb.v1.DoSProtectionPolicyGetResponse
-	25, // 29: scmlb.v1.ScmLbApi.DoSProtectionPolicyDelete:output_type -> google.protobuf.Empty
-	25, // 30: scmlb.v1.ScmLbApi.LoadBalancerSet:output_type -> google.protobuf.Empty
-	17, // 31: scmlb.v1.ScmLbApi.LoadBalancerGet:output_type -> scmlb.v1.LoadBalancerGetResponse
-	25, // 32: scmlb.v1.ScmLbApi.LoadBalancerDelete:output_type -> google.protobuf.Empty
-	25, // 33: scmlb.v1.ScmLbApi.LoadBalancerDrain:output_type -> google.protobuf.Empty
-	22, // 34: scmlb.v1.ScmLbApi.LoadBalancerConntrackGet:output_type -> scmlb.v1.LoadBalancerConntrackGetResponse
-	22, // [22:35] is the sub-list for method output_type
-	9,  // [9:22] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
-}
-
-func init() { file_protobuf_scmlb_proto_init() }
-func file_protobuf_scmlb_proto_init() {
-	if File_protobuf_scmlb_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_protobuf_scmlb_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HealthRequest); i {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConntrackEntry.ProtoReflect.Descriptor instead.
+func (*ConntrackEntry) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ConntrackEntry) GetSrcAddr() string {
+	if x != nil {
+		return x.SrcAddr
+	}
+	return ""
+}
+
+func (x *ConntrackEntry) GetDstAddr() string {
+	if x != nil {
+		return x.DstAddr
+	}
+	return ""
+}
+
+func (x *ConntrackEntry) GetSrcPort() int32 {
+	if x != nil {
+		return x.SrcPort
+	}
+	return 0
+}
+
+func (x *ConntrackEntry) GetDstPort() int32 {
+	if x != nil {
+		return x.DstPort
+	}
+	return 0
+}
+
+func (x *ConntrackEntry) GetProtocol() int32 {
+	if x != nil {
+		return x.Protocol
+	}
+	return 0
+}
+
+func (x *ConntrackEntry) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *ConntrackEntry) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ConntrackEntry) GetBackendId() int32 {
+	if x != nil {
+		return x.BackendId
+	}
+	return 0
+}
+
+func (x *ConntrackEntry) GetCounter() uint64 {
+	if x != nil {
+		return x.Counter
+	}
+	return 0
+}
+
+func (x *ConntrackEntry) GetEgressPackets() uint64 {
+	if x != nil {
+		return x.EgressPackets
+	}
+	return 0
+}
+
+func (x *ConntrackEntry) GetIngressBytes() uint64 {
+	if x != nil {
+		return x.IngressBytes
+	}
+	return 0
+}
+
+func (x *ConntrackEntry) GetEgressBytes() uint64 {
+	if x != nil {
+		return x.EgressBytes
+	}
+	return 0
+}
+
+func (x *ConntrackEntry) GetFirstSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstSeen
+	}
+	return nil
+}
+
+type LoadBalancerConntrackStatRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *LoadBalancerConntrackStatRequest) Reset() {
+	*x = LoadBalancerConntrackStatRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadBalancerConntrackStatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadBalancerConntrackStatRequest) ProtoMessage() {}
+
+func (x *LoadBalancerConntrackStatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadBalancerConntrackStatRequest.ProtoReflect.Descriptor instead.
+func (*LoadBalancerConntrackStatRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{28}
+}
+
+type LoadBalancerConntrackStatResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GcEnabled bool                        `protobuf:"varint,1,opt,name=gc_enabled,json=gcEnabled,proto3" json:"gc_enabled,omitempty"`
+	Counters  []*ConntrackEvictionCounter `protobuf:"bytes,2,rep,name=counters,proto3" json:"counters,omitempty"`
+}
+
+func (x *LoadBalancerConntrackStatResponse) Reset() {
+	*x = LoadBalancerConntrackStatResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadBalancerConntrackStatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadBalancerConntrackStatResponse) ProtoMessage() {}
+
+func (x *LoadBalancerConntrackStatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadBalancerConntrackStatResponse.ProtoReflect.Descriptor instead.
+func (*LoadBalancerConntrackStatResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *LoadBalancerConntrackStatResponse) GetGcEnabled() bool {
+	if x != nil {
+		return x.GcEnabled
+	}
+	return false
+}
+
+func (x *LoadBalancerConntrackStatResponse) GetCounters() []*ConntrackEvictionCounter {
+	if x != nil {
+		return x.Counters
+	}
+	return nil
+}
+
+type ConntrackEvictionCounter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Protocol int32  `protobuf:"varint,1,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Status   int32  `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	Timeout  int64  `protobuf:"varint,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Evicted  uint64 `protobuf:"varint,4,opt,name=evicted,proto3" json:"evicted,omitempty"`
+}
+
+func (x *ConntrackEvictionCounter) Reset() {
+	*x = ConntrackEvictionCounter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConntrackEvictionCounter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConntrackEvictionCounter) ProtoMessage() {}
+
+func (x *ConntrackEvictionCounter) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConntrackEvictionCounter.ProtoReflect.Descriptor instead.
+func (*ConntrackEvictionCounter) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ConntrackEvictionCounter) GetProtocol() int32 {
+	if x != nil {
+		return x.Protocol
+	}
+	return 0
+}
+
+func (x *ConntrackEvictionCounter) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *ConntrackEvictionCounter) GetTimeout() int64 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *ConntrackEvictionCounter) GetEvicted() uint64 {
+	if x != nil {
+		return x.Evicted
+	}
+	return 0
+}
+
+type LoadBalancerConntrackWatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BackendId int32 `protobuf:"varint,1,opt,name=backend_id,json=backendId,proto3" json:"backend_id,omitempty"`
+}
+
+func (x *LoadBalancerConntrackWatchRequest) Reset() {
+	*x = LoadBalancerConntrackWatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadBalancerConntrackWatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadBalancerConntrackWatchRequest) ProtoMessage() {}
+
+func (x *LoadBalancerConntrackWatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadBalancerConntrackWatchRequest.ProtoReflect.Descriptor instead.
+func (*LoadBalancerConntrackWatchRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *LoadBalancerConntrackWatchRequest) GetBackendId() int32 {
+	if x != nil {
+		return x.BackendId
+	}
+	return 0
+}
+
+type ConntrackEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type       int32                  `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	Entry      *ConntrackEntry        `protobuf:"bytes,2,opt,name=entry,proto3" json:"entry,omitempty"`
+	PrevStatus int32                  `protobuf:"varint,3,opt,name=prev_status,json=prevStatus,proto3" json:"prev_status,omitempty"`
+	Reason     string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Timestamp  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *ConntrackEvent) Reset() {
+	*x = ConntrackEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConntrackEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConntrackEvent) ProtoMessage() {}
+
+func (x *ConntrackEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConntrackEvent.ProtoReflect.Descriptor instead.
+func (*ConntrackEvent) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ConntrackEvent) GetType() int32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *ConntrackEvent) GetEntry() *ConntrackEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *ConntrackEvent) GetPrevStatus() int32 {
+	if x != nil {
+		return x.PrevStatus
+	}
+	return 0
+}
+
+func (x *ConntrackEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ConntrackEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type LoadBalancerConntrackDeleteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tuple     *ConntrackTuple `protobuf:"bytes,1,opt,name=tuple,proto3" json:"tuple,omitempty"`
+	BackendId int32           `protobuf:"varint,2,opt,name=backend_id,json=backendId,proto3" json:"backend_id,omitempty"`
+	SrcPrefix string          `protobuf:"bytes,3,opt,name=src_prefix,json=srcPrefix,proto3" json:"src_prefix,omitempty"`
+	Protocol  int32           `protobuf:"varint,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	All       bool            `protobuf:"varint,5,opt,name=all,proto3" json:"all,omitempty"`
+}
+
+func (x *LoadBalancerConntrackDeleteRequest) Reset() {
+	*x = LoadBalancerConntrackDeleteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadBalancerConntrackDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadBalancerConntrackDeleteRequest) ProtoMessage() {}
+
+func (x *LoadBalancerConntrackDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadBalancerConntrackDeleteRequest.ProtoReflect.Descriptor instead.
+func (*LoadBalancerConntrackDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *LoadBalancerConntrackDeleteRequest) GetTuple() *ConntrackTuple {
+	if x != nil {
+		return x.Tuple
+	}
+	return nil
+}
+
+func (x *LoadBalancerConntrackDeleteRequest) GetBackendId() int32 {
+	if x != nil {
+		return x.BackendId
+	}
+	return 0
+}
+
+func (x *LoadBalancerConntrackDeleteRequest) GetSrcPrefix() string {
+	if x != nil {
+		return x.SrcPrefix
+	}
+	return ""
+}
+
+func (x *LoadBalancerConntrackDeleteRequest) GetProtocol() int32 {
+	if x != nil {
+		return x.Protocol
+	}
+	return 0
+}
+
+func (x *LoadBalancerConntrackDeleteRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+type ConntrackTuple struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SrcAddr  string `protobuf:"bytes,1,opt,name=src_addr,json=srcAddr,proto3" json:"src_addr,omitempty"`
+	DstAddr  string `protobuf:"bytes,2,opt,name=dst_addr,json=dstAddr,proto3" json:"dst_addr,omitempty"`
+	SrcPort  int32  `protobuf:"varint,3,opt,name=src_port,json=srcPort,proto3" json:"src_port,omitempty"`
+	DstPort  int32  `protobuf:"varint,4,opt,name=dst_port,json=dstPort,proto3" json:"dst_port,omitempty"`
+	Protocol int32  `protobuf:"varint,5,opt,name=protocol,proto3" json:"protocol,omitempty"`
+}
+
+func (x *ConntrackTuple) Reset() {
+	*x = ConntrackTuple{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConntrackTuple) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConntrackTuple) ProtoMessage() {}
+
+func (x *ConntrackTuple) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConntrackTuple.ProtoReflect.Descriptor instead.
+func (*ConntrackTuple) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ConntrackTuple) GetSrcAddr() string {
+	if x != nil {
+		return x.SrcAddr
+	}
+	return ""
+}
+
+func (x *ConntrackTuple) GetDstAddr() string {
+	if x != nil {
+		return x.DstAddr
+	}
+	return ""
+}
+
+func (x *ConntrackTuple) GetSrcPort() int32 {
+	if x != nil {
+		return x.SrcPort
+	}
+	return 0
+}
+
+func (x *ConntrackTuple) GetDstPort() int32 {
+	if x != nil {
+		return x.DstPort
+	}
+	return 0
+}
+
+func (x *ConntrackTuple) GetProtocol() int32 {
+	if x != nil {
+		return x.Protocol
+	}
+	return 0
+}
+
+type LoadBalancerConntrackDeleteResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Deleted int32 `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+}
+
+func (x *LoadBalancerConntrackDeleteResponse) Reset() {
+	*x = LoadBalancerConntrackDeleteResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadBalancerConntrackDeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadBalancerConntrackDeleteResponse) ProtoMessage() {}
+
+func (x *LoadBalancerConntrackDeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadBalancerConntrackDeleteResponse.ProtoReflect.Descriptor instead.
+func (*LoadBalancerConntrackDeleteResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *LoadBalancerConntrackDeleteResponse) GetDeleted() int32 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
+}
+
+// peer の scmlbd に送信する conntrack のエントリーの変更です。
+type ConntrackReplica struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type        int32           `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	Tuple       *ConntrackTuple `protobuf:"bytes,2,opt,name=tuple,proto3" json:"tuple,omitempty"`
+	Status      int32           `protobuf:"varint,3,opt,name=status,proto3" json:"status,omitempty"`
+	BackendAddr string          `protobuf:"bytes,4,opt,name=backend_addr,json=backendAddr,proto3" json:"backend_addr,omitempty"`
+	SrcMacaddr  string          `protobuf:"bytes,5,opt,name=src_macaddr,json=srcMacaddr,proto3" json:"src_macaddr,omitempty"`
+}
+
+func (x *ConntrackReplica) Reset() {
+	*x = ConntrackReplica{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConntrackReplica) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConntrackReplica) ProtoMessage() {}
+
+func (x *ConntrackReplica) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConntrackReplica.ProtoReflect.Descriptor instead.
+func (*ConntrackReplica) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ConntrackReplica) GetType() int32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *ConntrackReplica) GetTuple() *ConntrackTuple {
+	if x != nil {
+		return x.Tuple
+	}
+	return nil
+}
+
+func (x *ConntrackReplica) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *ConntrackReplica) GetBackendAddr() string {
+	if x != nil {
+		return x.BackendAddr
+	}
+	return ""
+}
+
+func (x *ConntrackReplica) GetSrcMacaddr() string {
+	if x != nil {
+		return x.SrcMacaddr
+	}
+	return ""
+}
+
+type LoadBalancerConntrackSyncResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Applied uint64 `protobuf:"varint,1,opt,name=applied,proto3" json:"applied,omitempty"`
+	Failed  uint64 `protobuf:"varint,2,opt,name=failed,proto3" json:"failed,omitempty"`
+}
+
+func (x *LoadBalancerConntrackSyncResponse) Reset() {
+	*x = LoadBalancerConntrackSyncResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadBalancerConntrackSyncResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadBalancerConntrackSyncResponse) ProtoMessage() {}
+
+func (x *LoadBalancerConntrackSyncResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadBalancerConntrackSyncResponse.ProtoReflect.Descriptor instead.
+func (*LoadBalancerConntrackSyncResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *LoadBalancerConntrackSyncResponse) GetApplied() uint64 {
+	if x != nil {
+		return x.Applied
+	}
+	return 0
+}
+
+func (x *LoadBalancerConntrackSyncResponse) GetFailed() uint64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+type ServiceSetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Service *Service `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+}
+
+func (x *ServiceSetRequest) Reset() {
+	*x = ServiceSetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServiceSetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceSetRequest) ProtoMessage() {}
+
+func (x *ServiceSetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceSetRequest.ProtoReflect.Descriptor instead.
+func (*ServiceSetRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ServiceSetRequest) GetService() *Service {
+	if x != nil {
+		return x.Service
+	}
+	return nil
+}
+
+type ServiceGetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ServiceGetRequest) Reset() {
+	*x = ServiceGetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServiceGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceGetRequest) ProtoMessage() {}
+
+func (x *ServiceGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceGetRequest.ProtoReflect.Descriptor instead.
+func (*ServiceGetRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{39}
+}
+
+type ServiceGetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Services []*Service `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+}
+
+func (x *ServiceGetResponse) Reset() {
+	*x = ServiceGetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServiceGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceGetResponse) ProtoMessage() {}
+
+func (x *ServiceGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceGetResponse.ProtoReflect.Descriptor instead.
+func (*ServiceGetResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ServiceGetResponse) GetServices() []*Service {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+type ServiceDeleteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *ServiceDeleteRequest) Reset() {
+	*x = ServiceDeleteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServiceDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceDeleteRequest) ProtoMessage() {}
+
+func (x *ServiceDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceDeleteRequest.ProtoReflect.Descriptor instead.
+func (*ServiceDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ServiceDeleteRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type Service struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              int32  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Vip             string `protobuf:"bytes,2,opt,name=vip,proto3" json:"vip,omitempty"`
+	Port            int32  `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	Protocol        int32  `protobuf:"varint,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Scheduler       string `protobuf:"bytes,5,opt,name=scheduler,proto3" json:"scheduler,omitempty"`
+	AffinityTimeout int64  `protobuf:"varint,6,opt,name=affinity_timeout,json=affinityTimeout,proto3" json:"affinity_timeout,omitempty"`
+	Forwarding      string `protobuf:"bytes,7,opt,name=forwarding,proto3" json:"forwarding,omitempty"`
+}
+
+func (x *Service) Reset() {
+	*x = Service{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Service) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Service) ProtoMessage() {}
+
+func (x *Service) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Service.ProtoReflect.Descriptor instead.
+func (*Service) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *Service) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Service) GetVip() string {
+	if x != nil {
+		return x.Vip
+	}
+	return ""
+}
+
+func (x *Service) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *Service) GetProtocol() int32 {
+	if x != nil {
+		return x.Protocol
+	}
+	return 0
+}
+
+func (x *Service) GetScheduler() string {
+	if x != nil {
+		return x.Scheduler
+	}
+	return ""
+}
+
+func (x *Service) GetAffinityTimeout() int64 {
+	if x != nil {
+		return x.AffinityTimeout
+	}
+	return 0
+}
+
+func (x *Service) GetForwarding() string {
+	if x != nil {
+		return x.Forwarding
+	}
+	return ""
+}
+
+type LoadBalancerAffinitySetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ServiceId int32 `protobuf:"varint,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Enabled   bool  `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Timeout   int64 `protobuf:"varint,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+}
+
+func (x *LoadBalancerAffinitySetRequest) Reset() {
+	*x = LoadBalancerAffinitySetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadBalancerAffinitySetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadBalancerAffinitySetRequest) ProtoMessage() {}
+
+func (x *LoadBalancerAffinitySetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadBalancerAffinitySetRequest.ProtoReflect.Descriptor instead.
+func (*LoadBalancerAffinitySetRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *LoadBalancerAffinitySetRequest) GetServiceId() int32 {
+	if x != nil {
+		return x.ServiceId
+	}
+	return 0
+}
+
+func (x *LoadBalancerAffinitySetRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *LoadBalancerAffinitySetRequest) GetTimeout() int64 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+type LoadBalancerAffinityGetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ServiceId int32 `protobuf:"varint,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+}
+
+func (x *LoadBalancerAffinityGetRequest) Reset() {
+	*x = LoadBalancerAffinityGetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadBalancerAffinityGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadBalancerAffinityGetRequest) ProtoMessage() {}
+
+func (x *LoadBalancerAffinityGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadBalancerAffinityGetRequest.ProtoReflect.Descriptor instead.
+func (*LoadBalancerAffinityGetRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *LoadBalancerAffinityGetRequest) GetServiceId() int32 {
+	if x != nil {
+		return x.ServiceId
+	}
+	return 0
+}
+
+type LoadBalancerAffinityGetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries []*AffinityEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *LoadBalancerAffinityGetResponse) Reset() {
+	*x = LoadBalancerAffinityGetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadBalancerAffinityGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadBalancerAffinityGetResponse) ProtoMessage() {}
+
+func (x *LoadBalancerAffinityGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadBalancerAffinityGetResponse.ProtoReflect.Descriptor instead.
+func (*LoadBalancerAffinityGetResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *LoadBalancerAffinityGetResponse) GetEntries() []*AffinityEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type LoadBalancerAffinityFlushRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ServiceId int32 `protobuf:"varint,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+}
+
+func (x *LoadBalancerAffinityFlushRequest) Reset() {
+	*x = LoadBalancerAffinityFlushRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadBalancerAffinityFlushRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadBalancerAffinityFlushRequest) ProtoMessage() {}
+
+func (x *LoadBalancerAffinityFlushRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadBalancerAffinityFlushRequest.ProtoReflect.Descriptor instead.
+func (*LoadBalancerAffinityFlushRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *LoadBalancerAffinityFlushRequest) GetServiceId() int32 {
+	if x != nil {
+		return x.ServiceId
+	}
+	return 0
+}
+
+type AffinityEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ServiceId  int32  `protobuf:"varint,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	ClientAddr string `protobuf:"bytes,2,opt,name=client_addr,json=clientAddr,proto3" json:"client_addr,omitempty"`
+	BackendId  int32  `protobuf:"varint,3,opt,name=backend_id,json=backendId,proto3" json:"backend_id,omitempty"`
+	Idle       int64  `protobuf:"varint,4,opt,name=idle,proto3" json:"idle,omitempty"`
+	Expired    bool   `protobuf:"varint,5,opt,name=expired,proto3" json:"expired,omitempty"`
+}
+
+func (x *AffinityEntry) Reset() {
+	*x = AffinityEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_scmlb_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AffinityEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AffinityEntry) ProtoMessage() {}
+
+func (x *AffinityEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_scmlb_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AffinityEntry.ProtoReflect.Descriptor instead.
+func (*AffinityEntry) Descriptor() ([]byte, []int) {
+	return file_protobuf_scmlb_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *AffinityEntry) GetServiceId() int32 {
+	if x != nil {
+		return x.ServiceId
+	}
+	return 0
+}
+
+func (x *AffinityEntry) GetClientAddr() string {
+	if x != nil {
+		return x.ClientAddr
+	}
+	return ""
+}
+
+func (x *AffinityEntry) GetBackendId() int32 {
+	if x != nil {
+		return x.BackendId
+	}
+	return 0
+}
+
+func (x *AffinityEntry) GetIdle() int64 {
+	if x != nil {
+		return x.Idle
+	}
+	return 0
+}
+
+func (x *AffinityEntry) GetExpired() bool {
+	if x != nil {
+		return x.Expired
+	}
+	return false
+}
+
+var File_protobuf_scmlb_proto protoreflect.FileDescriptor
+
+var file_protobuf_scmlb_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x63, 0x6d, 0x6c, 0x62,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31,
+	0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x0f,
+	0x0a, 0x0d, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
+	0x0d, 0x0a, 0x0b, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x3b,
+	0x0a, 0x0c, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b,
+	0x0a, 0x06, 0x69, 0x66, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66,
+	0x61, 0x63, 0x65, 0x52, 0x06, 0x69, 0x66, 0x61, 0x63, 0x65, 0x73, 0x22, 0x84, 0x01, 0x0a, 0x09,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x75, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12,
+	0x31, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x63, 0x6b,
+	0x65, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x65, 0x72, 0x22, 0x47, 0x0a, 0x0d, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x63, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x04, 0x69, 0x63, 0x6d, 0x70, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x63, 0x70, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x74, 0x63, 0x70, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x64, 0x70,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x75, 0x64, 0x70, 0x22, 0x43, 0x0a, 0x15, 0x46,
+	0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x52, 0x71,
+	0x65, 0x75, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69,
+	0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x04, 0x72, 0x75, 0x6c, 0x65,
+	0x22, 0x18, 0x0a, 0x16, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65,
+	0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x47, 0x0a, 0x17, 0x46, 0x69,
+	0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e,
+	0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75,
+	0x6c, 0x65, 0x73, 0x22, 0x2b, 0x0a, 0x19, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52,
+	0x75, 0x6c, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64,
+	0x22, 0x81, 0x01, 0x0a, 0x18, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c,
+	0x65, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2c, 0x0a,
+	0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x73,
+	0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c,
+	0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x64,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x05, 0x52,
+	0x09, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x64, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65,
+	0x70, 0x6c, 0x61, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x70,
+	0x6c, 0x61, 0x63, 0x65, 0x22, 0x59, 0x0a, 0x19, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c,
+	0x52, 0x75, 0x6c, 0x65, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x65, 0x64, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x05, 0x52, 0x08, 0x61, 0x64, 0x64, 0x65, 0x64, 0x49, 0x64, 0x73, 0x12, 0x1f,
+	0x0a, 0x0b, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x05, 0x52, 0x0a, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x49, 0x64, 0x73, 0x22,
+	0xc3, 0x02, 0x0a, 0x0c, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x22, 0x0a, 0x0d, 0x66, 0x72, 0x6f, 0x6d,
+	0x5f, 0x73, 0x72, 0x63, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0b, 0x66, 0x72, 0x6f, 0x6d, 0x53, 0x72, 0x63, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x1e, 0x0a, 0x0b,
+	0x74, 0x6f, 0x5f, 0x73, 0x72, 0x63, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x09, 0x74, 0x6f, 0x53, 0x72, 0x63, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x22, 0x0a, 0x0d,
+	0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x64, 0x73, 0x74, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0b, 0x66, 0x72, 0x6f, 0x6d, 0x44, 0x73, 0x74, 0x50, 0x6f, 0x72, 0x74,
+	0x12, 0x1e, 0x0a, 0x0b, 0x74, 0x6f, 0x5f, 0x64, 0x73, 0x74, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x74, 0x6f, 0x44, 0x73, 0x74, 0x50, 0x6f, 0x72, 0x74,
+	0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x14, 0x0a, 0x05,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x16,
+	0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x73, 0x74, 0x5f, 0x70, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x73, 0x74, 0x50,
+	0x72, 0x65, 0x66, 0x69, 0x78, 0x22, 0x56, 0x0a, 0x1d, 0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x53, 0x65, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x35, 0x0a, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76,
+	0x31, 0x2e, 0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x22, 0x1f, 0x0a,
+	0x1d, 0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x5b,
+	0x0a, 0x1e, 0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x39, 0x0a, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f,
+	0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x52, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x22, 0x32, 0x0a, 0x20, 0x44,
+	0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22,
+	0xa3, 0x01, 0x0a, 0x13, 0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1e, 0x0a, 0x0b, 0x66, 0x77, 0x5f, 0x72, 0x75, 0x6c, 0x65,
+	0x5f, 0x69, 0x64, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x05, 0x52, 0x09, 0x66, 0x77, 0x52, 0x75,
+	0x6c, 0x65, 0x49, 0x64, 0x73, 0x22, 0xf2, 0x01, 0x0a, 0x16, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x53, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x20,
+	0x0a, 0x0b, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x63, 0x68, 0x65, 0x63, 0x6b,
+	0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x6f, 0x72, 0x77, 0x61,
+	0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x6f, 0x72,
+	0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x61, 0x63, 0x5f, 0x61,
+	0x64, 0x64, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x61, 0x63, 0x41, 0x64,
+	0x64, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x22, 0x18, 0x0a, 0x16, 0x4c, 0x6f,
+	0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x54, 0x0a, 0x17, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x72, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x39, 0x0a, 0x08, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1d, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61,
+	0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x42, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64,
+	0x52, 0x08, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x73, 0x22, 0x2b, 0x0a, 0x19, 0x4c, 0x6f,
+	0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x46, 0x0a, 0x18, 0x4c, 0x6f, 0x61, 0x64, 0x42,
+	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x22,
+	0x2e, 0x0a, 0x1c, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x44,
+	0x72, 0x61, 0x69, 0x6e, 0x57, 0x61, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22,
+	0x9d, 0x01, 0x0a, 0x17, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72,
+	0x44, 0x72, 0x61, 0x69, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x72,
+	0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09,
+	0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x36, 0x0a, 0x08, 0x64, 0x65, 0x61,
+	0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x22,
+	0xe0, 0x02, 0x0a, 0x13, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72,
+	0x42, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x64,
+	0x65, 0x76, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64,
+	0x65, 0x76, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x70, 0x5f, 0x61, 0x64, 0x64,
+	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x12,
+	0x19, 0x0a, 0x08, 0x6d, 0x61, 0x63, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6d, 0x61, 0x63, 0x41, 0x64, 0x64, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x68, 0x65,
+	0x61, 0x6c, 0x74, 0x68, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06,
+	0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x77, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x49, 0x64, 0x12, 0x2d, 0x0a, 0x12, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x63, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x11, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69,
+	0x6e, 0x67, 0x22, 0xb8, 0x02, 0x0a, 0x1f, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e,
+	0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x47, 0x65, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e,
+	0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x62, 0x61, 0x63, 0x6b,
+	0x65, 0x6e, 0x64, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x72, 0x63,
+	0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73,
+	0x72, 0x63, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x19, 0x0a, 0x08, 0x64, 0x73, 0x74, 0x5f,
+	0x70, 0x6f, 0x72, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x64, 0x73, 0x74, 0x50,
+	0x6f, 0x72, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x69, 0x6e, 0x5f, 0x69, 0x64, 0x6c, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6d, 0x69, 0x6e, 0x49, 0x64, 0x6c, 0x65, 0x12, 0x17,
+	0x0a, 0x07, 0x73, 0x6f, 0x72, 0x74, 0x5f, 0x62, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x6f, 0x72, 0x74, 0x42, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x76, 0x65, 0x72,
+	0x73, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73,
+	0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d,
+	0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x94, 0x01,
+	0x0a, 0x20, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x43, 0x6f,
+	0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x32, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x43,
+	0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65,
+	0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x70,
+	0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x14,
+	0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x22, 0xcd, 0x03, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61,
+	0x63, 0x6b, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x72, 0x63, 0x5f, 0x61,
+	0x64, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x72, 0x63, 0x41, 0x64,
+	0x64, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x64, 0x73, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x73, 0x74, 0x41, 0x64, 0x64, 0x72, 0x12, 0x19, 0x0a,
+	0x08, 0x73, 0x72, 0x63, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x07, 0x73, 0x72, 0x63, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x64, 0x73, 0x74, 0x5f,
+	0x70, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x64, 0x73, 0x74, 0x50,
+	0x6f, 0x72, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x5f, 0x69, 0x64, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x49, 0x64,
+	0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x25, 0x0a, 0x0e, 0x65, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x5f, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0d, 0x65, 0x67, 0x72, 0x65, 0x73, 0x73, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74,
+	0x73, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x65, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x65, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x66, 0x69, 0x72,
+	0x73, 0x74, 0x5f, 0x73, 0x65, 0x65, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x66, 0x69, 0x72, 0x73, 0x74,
+	0x53, 0x65, 0x65, 0x6e, 0x22, 0x22, 0x0a, 0x20, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x82, 0x01, 0x0a, 0x21, 0x4c, 0x6f, 0x61,
+	0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61,
+	0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d,
+	0x0a, 0x0a, 0x67, 0x63, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x09, 0x67, 0x63, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x3e, 0x0a,
+	0x08, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x22, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x74,
+	0x72, 0x61, 0x63, 0x6b, 0x45, 0x76, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x65, 0x72, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x22, 0x82, 0x01,
+	0x0a, 0x18, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x45, 0x76, 0x69, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x76, 0x69, 0x63,
+	0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x65, 0x76, 0x69, 0x63, 0x74,
+	0x65, 0x64, 0x22, 0x42, 0x0a, 0x21, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x57, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x65,
+	0x6e, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x62, 0x61, 0x63,
+	0x6b, 0x65, 0x6e, 0x64, 0x49, 0x64, 0x22, 0xc7, 0x01, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x6e, 0x74,
+	0x72, 0x61, 0x63, 0x6b, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2e, 0x0a,
+	0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x73,
+	0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63,
+	0x6b, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1f, 0x0a,
+	0x0b, 0x70, 0x72, 0x65, 0x76, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0a, 0x70, 0x72, 0x65, 0x76, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x22, 0xc0, 0x01, 0x0a, 0x22, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65,
+	0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x05, 0x74, 0x75, 0x70, 0x6c, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x54, 0x75, 0x70, 0x6c, 0x65,
+	0x52, 0x05, 0x74, 0x75, 0x70, 0x6c, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x65,
+	0x6e, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x62, 0x61, 0x63,
+	0x6b, 0x65, 0x6e, 0x64, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x72, 0x63, 0x5f, 0x70, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x72, 0x63, 0x50,
+	0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x6c, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03,
+	0x61, 0x6c, 0x6c, 0x22, 0x98, 0x01, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63,
+	0x6b, 0x54, 0x75, 0x70, 0x6c, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x72, 0x63, 0x5f, 0x61, 0x64,
+	0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x72, 0x63, 0x41, 0x64, 0x64,
+	0x72, 0x12, 0x19, 0x0a, 0x08, 0x64, 0x73, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x73, 0x74, 0x41, 0x64, 0x64, 0x72, 0x12, 0x19, 0x0a, 0x08,
+	0x73, 0x72, 0x63, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07,
+	0x73, 0x72, 0x63, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x64, 0x73, 0x74, 0x5f, 0x70,
+	0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x64, 0x73, 0x74, 0x50, 0x6f,
+	0x72, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x22, 0x3f,
+	0x0a, 0x23, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x43, 0x6f,
+	0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x22,
+	0xb2, 0x01, 0x0a, 0x10, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x70,
+	0x6c, 0x69, 0x63, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2e, 0x0a, 0x05, 0x74, 0x75, 0x70, 0x6c,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x54, 0x75, 0x70, 0x6c,
+	0x65, 0x52, 0x05, 0x74, 0x75, 0x70, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x21, 0x0a, 0x0c, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x5f, 0x61, 0x64, 0x64, 0x72,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x41,
+	0x64, 0x64, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x72, 0x63, 0x5f, 0x6d, 0x61, 0x63, 0x61, 0x64,
+	0x64, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x72, 0x63, 0x4d, 0x61, 0x63,
+	0x61, 0x64, 0x64, 0x72, 0x22, 0x55, 0x0a, 0x21, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x53, 0x79, 0x6e,
+	0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x70, 0x70,
+	0x6c, 0x69, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x61, 0x70, 0x70, 0x6c,
+	0x69, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x22, 0x40, 0x0a, 0x11, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x53, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x2b, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x22, 0x13, 0x0a,
+	0x11, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x22, 0x43, 0x0a, 0x12, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x47, 0x65, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x08, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x73, 0x63, 0x6d,
+	0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x08, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x22, 0x26, 0x0a, 0x14, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22,
+	0xc4, 0x01, 0x0a, 0x07, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x76,
+	0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x76, 0x69, 0x70, 0x12, 0x12, 0x0a,
+	0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f, 0x72,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x1c, 0x0a,
+	0x09, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x12, 0x29, 0x0a, 0x10, 0x61,
+	0x66, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x79, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x61, 0x66, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x79, 0x54,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72,
+	0x64, 0x69, 0x6e, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x6f, 0x72, 0x77,
+	0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x73, 0x0a, 0x1e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x41, 0x66, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x79, 0x53, 0x65,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c,
+	0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65,
+	0x64, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x22, 0x3f, 0x0a, 0x1e, 0x4c,
+	0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x41, 0x66, 0x66, 0x69, 0x6e,
+	0x69, 0x74, 0x79, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a,
+	0x0a, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x09, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x22, 0x54, 0x0a, 0x1f,
+	0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x41, 0x66, 0x66, 0x69,
+	0x6e, 0x69, 0x74, 0x79, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x31, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x66, 0x66, 0x69,
+	0x6e, 0x69, 0x74, 0x79, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69,
+	0x65, 0x73, 0x22, 0x41, 0x0a, 0x20, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x72, 0x41, 0x66, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x79, 0x46, 0x6c, 0x75, 0x73, 0x68, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x49, 0x64, 0x22, 0x9c, 0x01, 0x0a, 0x0d, 0x41, 0x66, 0x66, 0x69, 0x6e, 0x69,
+	0x74, 0x79, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x41, 0x64, 0x64, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x65,
+	0x6e, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x62, 0x61, 0x63,
+	0x6b, 0x65, 0x6e, 0x64, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x64, 0x6c, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x69, 0x64, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x78, 0x70,
+	0x69, 0x72, 0x65, 0x64, 0x32, 0xe0, 0x11, 0x0a, 0x08, 0x53, 0x63, 0x6d, 0x4c, 0x62, 0x41, 0x70,
+	0x69, 0x12, 0x39, 0x0a, 0x06, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x12, 0x17, 0x2e, 0x73, 0x63,
+	0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x35, 0x0a, 0x04,
+	0x53, 0x74, 0x61, 0x74, 0x12, 0x15, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x73, 0x63,
+	0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0f, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52,
+	0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x12, 0x1f, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76,
+	0x31, 0x2e, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65,
+	0x74, 0x52, 0x71, 0x65, 0x75, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12,
+	0x56, 0x0a, 0x0f, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x47,
+	0x65, 0x74, 0x12, 0x20, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69,
+	0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e,
+	0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x47, 0x65, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x12, 0x46, 0x69, 0x72, 0x65, 0x57,
+	0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x23, 0x2e,
+	0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x72, 0x65, 0x57, 0x61, 0x6c,
+	0x6c, 0x52, 0x75, 0x6c, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x5c, 0x0a, 0x11, 0x46, 0x69,
+	0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x12,
+	0x22, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x72, 0x65, 0x57,
+	0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x46,
+	0x69, 0x72, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x41, 0x70, 0x70, 0x6c, 0x79,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x59, 0x0a, 0x16, 0x44, 0x6f, 0x53, 0x50,
+	0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x53,
+	0x65, 0x74, 0x12, 0x27, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f,
+	0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x53, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x12, 0x6b, 0x0a, 0x16, 0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x47, 0x65, 0x74, 0x12, 0x27, 0x2e,
+	0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x47, 0x65, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76,
+	0x31, 0x2e, 0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x5f, 0x0a, 0x19, 0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x2a, 0x2e,
+	0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x53, 0x50, 0x72, 0x6f, 0x74,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x12, 0x4b, 0x0a, 0x0f, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65,
+	0x72, 0x53, 0x65, 0x74, 0x12, 0x20, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e,
+	0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x53, 0x65, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x56,
+	0x0a, 0x0f, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x47, 0x65,
+	0x74, 0x12, 0x20, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61,
+	0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x47, 0x65, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x12, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x23, 0x2e, 0x73,
+	0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x72, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x4f, 0x0a, 0x11, 0x4c, 0x6f, 0x61,
+	0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x12, 0x22,
+	0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x64, 0x0a, 0x15, 0x4c, 0x6f,
+	0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x57,
+	0x61, 0x69, 0x74, 0x12, 0x26, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x44, 0x72, 0x61, 0x69, 0x6e,
+	0x57, 0x61, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x73, 0x63,
+	0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e,
+	0x63, 0x65, 0x72, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x30, 0x01,
+	0x12, 0x71, 0x0a, 0x18, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72,
+	0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x47, 0x65, 0x74, 0x12, 0x29, 0x2e, 0x73,
+	0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x47, 0x65, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x43,
+	0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x74, 0x0a, 0x19, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e,
+	0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74,
+	0x12, 0x2a, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64,
+	0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63,
+	0x6b, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x73,
+	0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x65, 0x0a, 0x1a, 0x4c, 0x6f, 0x61,
+	0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61,
+	0x63, 0x6b, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x2b, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x43,
+	0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01,
+	0x12, 0x7a, 0x0a, 0x1b, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72,
+	0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12,
+	0x2c, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42,
+	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e,
+	0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c,
+	0x61, 0x6e, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x66, 0x0a, 0x19,
+	0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e,
+	0x74, 0x72, 0x61, 0x63, 0x6b, 0x53, 0x79, 0x6e, 0x63, 0x12, 0x1a, 0x2e, 0x73, 0x63, 0x6d, 0x6c,
+	0x62, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x52, 0x65,
+	0x70, 0x6c, 0x69, 0x63, 0x61, 0x1a, 0x2b, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31,
+	0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x43, 0x6f, 0x6e,
+	0x6e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x28, 0x01, 0x12, 0x41, 0x0a, 0x0a, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x53,
+	0x65, 0x74, 0x12, 0x1b, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x53, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x47, 0x0a, 0x0a, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x47, 0x65, 0x74, 0x12, 0x1b, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x47, 0x0a, 0x0d, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x12, 0x1e, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x5b, 0x0a, 0x17, 0x4c, 0x6f, 0x61,
+	0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x41, 0x66, 0x66, 0x69, 0x6e, 0x69, 0x74,
+	0x79, 0x53, 0x65, 0x74, 0x12, 0x28, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e,
+	0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x41, 0x66, 0x66, 0x69,
+	0x6e, 0x69, 0x74, 0x79, 0x53, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x6e, 0x0a, 0x17, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x41, 0x66, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x79, 0x47, 0x65,
+	0x74, 0x12, 0x28, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61,
+	0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x41, 0x66, 0x66, 0x69, 0x6e, 0x69, 0x74,
+	0x79, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x73, 0x63,
+	0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e,
+	0x63, 0x65, 0x72, 0x41, 0x66, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x79, 0x47, 0x65, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5f, 0x0a, 0x19, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x41, 0x66, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x79, 0x46, 0x6c,
+	0x75, 0x73, 0x68, 0x12, 0x2a, 0x2e, 0x73, 0x63, 0x6d, 0x6c, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x41, 0x66, 0x66, 0x69, 0x6e,
+	0x69, 0x74, 0x79, 0x46, 0x6c, 0x75, 0x73, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x42, 0x2b, 0x5a, 0x29, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x65, 0x72, 0x61, 0x73, 0x73, 0x79, 0x69, 0x2f, 0x73,
+	0x65, 0x63, 0x63, 0x61, 0x6d, 0x70, 0x2d, 0x78, 0x64, 0x70, 0x2f, 0x73, 0x63, 0x6d, 0x6c, 0x62,
+	0x2f, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_protobuf_scmlb_proto_rawDescOnce sync.Once
+	file_protobuf_scmlb_proto_rawDescData = file_protobuf_scmlb_proto_rawDesc
+)
+
+func file_protobuf_scmlb_proto_rawDescGZIP() []byte {
+	file_protobuf_scmlb_proto_rawDescOnce.Do(func() {
+		file_protobuf_scmlb_proto_rawDescData = protoimpl.X.CompressGZIP(file_protobuf_scmlb_proto_rawDescData)
+	})
+	return file_protobuf_scmlb_proto_rawDescData
+}
+
+var file_protobuf_scmlb_proto_msgTypes = make([]protoimpl.MessageInfo, 48)
+var file_protobuf_scmlb_proto_goTypes = []interface{}{
+	(*HealthRequest)(nil),                       // 0: scmlb.v1.HealthRequest
+	(*StatRequest)(nil),                         // 1: scmlb.v1.StatRequest
+	(*StatResponse)(nil),                        // 2: scmlb.v1.StatResponse
+	(*Interface)(nil),                           // 3: scmlb.v1.Interface
+	(*PacketCounter)(nil),                       // 4: scmlb.v1.PacketCounter
+	(*FireWallRuleSetRqeust)(nil),               // 5: scmlb.v1.FireWallRuleSetRqeust
+	(*FireWallRuleGetRequest)(nil),              // 6: scmlb.v1.FireWallRuleGetRequest
+	(*FireWallRuleGetResponse)(nil),             // 7: scmlb.v1.FireWallRuleGetResponse
+	(*FireWallRuleDeleteRequest)(nil),           // 8: scmlb.v1.FireWallRuleDeleteRequest
+	(*FireWallRuleApplyRequest)(nil),            // 9: scmlb.v1.FireWallRuleApplyRequest
+	(*FireWallRuleApplyResponse)(nil),           // 10: scmlb.v1.FireWallRuleApplyResponse
+	(*FireWallRule)(nil),                        // 11: scmlb.v1.FireWallRule
+	(*DoSProtectionPolicySetRequest)(nil),       // 12: scmlb.v1.DoSProtectionPolicySetRequest
+	(*DoSProtectionPolicyGetRequest)(nil),       // 13: scmlb.v1.DoSProtectionPolicyGetRequest
+	(*DoSProtectionPolicyGetResponse)(nil),      // 14: scmlb.v1.DoSProtectionPolicyGetResponse
+	(*DoSProtectionPolicyDeleteRequest)(nil),    // 15: scmlb.v1.DoSProtectionPolicyDeleteRequest
+	(*DoSProtectionPolicy)(nil),                 // 16: scmlb.v1.DoSProtectionPolicy
+	(*LoadBalancerSetRequest)(nil),              // 17: scmlb.v1.LoadBalancerSetRequest
+	(*LoadBalancerGetRequest)(nil),              // 18: scmlb.v1.LoadBalancerGetRequest
+	(*LoadBalancerGetResponse)(nil),             // 19: scmlb.v1.LoadBalancerGetResponse
+	(*LoadBalancerDeleteRequest)(nil),           // 20: scmlb.v1.LoadBalancerDeleteRequest
+	(*LoadBalancerDrainRequest)(nil),            // 21: scmlb.v1.LoadBalancerDrainRequest
+	(*LoadBalancerDrainWaitRequest)(nil),        // 22: scmlb.v1.LoadBalancerDrainWaitRequest
+	(*LoadBalancerDrainStatus)(nil),             // 23: scmlb.v1.LoadBalancerDrainStatus
+	(*LoadBalancerBackend)(nil),                 // 24: scmlb.v1.LoadBalancerBackend
+	(*LoadBalancerConntrackGetRequest)(nil),     // 25: scmlb.v1.LoadBalancerConntrackGetRequest
+	(*LoadBalancerConntrackGetResponse)(nil),    // 26: scmlb.v1.LoadBalancerConntrackGetResponse
+	(*ConntrackEntry)(nil),                      // 27: scmlb.v1.ConntrackEntry
+	(*LoadBalancerConntrackStatRequest)(nil),    // 28: scmlb.v1.LoadBalancerConntrackStatRequest
+	(*LoadBalancerConntrackStatResponse)(nil),   // 29: scmlb.v1.LoadBalancerConntrackStatResponse
+	(*ConntrackEvictionCounter)(nil),            // 30: scmlb.v1.ConntrackEvictionCounter
+	(*LoadBalancerConntrackWatchRequest)(nil),   // 31: scmlb.v1.LoadBalancerConntrackWatchRequest
+	(*ConntrackEvent)(nil),                      // 32: scmlb.v1.ConntrackEvent
+	(*LoadBalancerConntrackDeleteRequest)(nil),  // 33: scmlb.v1.LoadBalancerConntrackDeleteRequest
+	(*ConntrackTuple)(nil),                      // 34: scmlb.v1.ConntrackTuple
+	(*LoadBalancerConntrackDeleteResponse)(nil), // 35: scmlb.v1.LoadBalancerConntrackDeleteResponse
+	(*ConntrackReplica)(nil),                    // 36: scmlb.v1.ConntrackReplica
+	(*LoadBalancerConntrackSyncResponse)(nil),   // 37: scmlb.v1.LoadBalancerConntrackSyncResponse
+	(*ServiceSetRequest)(nil),                   // 38: scmlb.v1.ServiceSetRequest
+	(*ServiceGetRequest)(nil),                   // 39: scmlb.v1.ServiceGetRequest
+	(*ServiceGetResponse)(nil),                  // 40: scmlb.v1.ServiceGetResponse
+	(*ServiceDeleteRequest)(nil),                // 41: scmlb.v1.ServiceDeleteRequest
+	(*Service)(nil),                             // 42: scmlb.v1.Service
+	(*LoadBalancerAffinitySetRequest)(nil),      // 43: scmlb.v1.LoadBalancerAffinitySetRequest
+	(*LoadBalancerAffinityGetRequest)(nil),      // 44: scmlb.v1.LoadBalancerAffinityGetRequest
+	(*LoadBalancerAffinityGetResponse)(nil),     // 45: scmlb.v1.LoadBalancerAffinityGetResponse
+	(*LoadBalancerAffinityFlushRequest)(nil),    // 46: scmlb.v1.LoadBalancerAffinityFlushRequest
+	(*AffinityEntry)(nil),                       // 47: scmlb.v1.AffinityEntry
+	(*timestamppb.Timestamp)(nil),               // 48: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),                       // 49: google.protobuf.Empty
+}
+var file_protobuf_scmlb_proto_depIdxs = []int32{
+	3,  // 0: scmlb.v1.StatResponse.ifaces:type_name -> scmlb.v1.Interface
+	4,  // 1: scmlb.v1.Interface.counter:type_name -> scmlb.v1.PacketCounter
+	11, // 2: scmlb.v1.FireWallRuleSetRqeust.rule:type_name -> scmlb.v1.FireWallRule
+	11, // 3: scmlb.v1.FireWallRuleGetResponse.rules:type_name -> scmlb.v1.FireWallRule
+	11, // 4: scmlb.v1.FireWallRuleApplyRequest.rules:type_name -> scmlb.v1.FireWallRule
+	16, // 5: scmlb.v1.DoSProtectionPolicySetRequest.policy:type_name -> scmlb.v1.DoSProtectionPolicy
+	16, // 6: scmlb.v1.DoSProtectionPolicyGetResponse.policies:type_name -> scmlb.v1.DoSProtectionPolicy
+	24, // 7: scmlb.v1.LoadBalancerGetResponse.backends:type_name -> scmlb.v1.LoadBalancerBackend
+	48, // 8: scmlb.v1.LoadBalancerDrainStatus.deadline:type_name -> google.protobuf.Timestamp
+	27, // 9: scmlb.v1.LoadBalancerConntrackGetResponse.entries:type_name -> scmlb.v1.ConntrackEntry
+	48, // 10: scmlb.v1.ConntrackEntry.timestamp:type_name -> google.protobuf.Timestamp
+	48, // 11: scmlb.v1.ConntrackEntry.first_seen:type_name -> google.protobuf.Timestamp
+	30, // 12: scmlb.v1.LoadBalancerConntrackStatResponse.counters:type_name -> scmlb.v1.ConntrackEvictionCounter
+	27, // 13: scmlb.v1.ConntrackEvent.entry:type_name -> scmlb.v1.ConntrackEntry
+	48, // 14: scmlb.v1.ConntrackEvent.timestamp:type_name -> google.protobuf.Timestamp
+	34, // 15: scmlb.v1.LoadBalancerConntrackDeleteRequest.tuple:type_name -> scmlb.v1.ConntrackTuple
+	34, // 16: scmlb.v1.ConntrackReplica.tuple:type_name -> scmlb.v1.ConntrackTuple
+	42, // 17: scmlb.v1.ServiceSetRequest.service:type_name -> scmlb.v1.Service
+	42, // 18: scmlb.v1.ServiceGetResponse.services:type_name -> scmlb.v1.Service
+	47, // 19: scmlb.v1.LoadBalancerAffinityGetResponse.entries:type_name -> scmlb.v1.AffinityEntry
+	0,  // 20: scmlb.v1.ScmLbApi.Health:input_type -> scmlb.v1.HealthRequest
+	1,  // 21: scmlb.v1.ScmLbApi.Stat:input_type -> scmlb.v1.StatRequest
+	5,  // 22: scmlb.v1.ScmLbApi.FireWallRuleSet:input_type -> scmlb.v1.FireWallRuleSetRqeust
+	6,  // 23: scmlb.v1.ScmLbApi.FireWallRuleGet:input_type -> scmlb.v1.FireWallRuleGetRequest
+	8,  // 24: scmlb.v1.ScmLbApi.FireWallRuleDelete:input_type -> scmlb.v1.FireWallRuleDeleteRequest
+	9,  // 25: scmlb.v1.ScmLbApi.FireWallRuleApply:input_type -> scmlb.v1.FireWallRuleApplyRequest
+	12, // 26: scmlb.v1.ScmLbApi.DoSProtectionPolicySet:input_type -> scmlb.v1.DoSProtectionPolicySetRequest
+	13, // 27: scmlb.v1.ScmLbApi.DoSProtectionPolicyGet:input_type -> scmlb.v1.DoSProtectionPolicyGetRequest
+	15, // 28: scmlb.v1.ScmLbApi.DoSProtectionPolicyDelete:input_type -> scmlb.v1.DoSProtectionPolicyDeleteRequest
+	17, // 29: scmlb.v1.ScmLbApi.LoadBalancerSet:input_type -> scmlb.v1.LoadBalancerSetRequest
+	18, // 30: scmlb.v1.ScmLbApi.LoadBalancerGet:input_type -> scmlb.v1.LoadBalancerGetRequest
+	20, // 31: scmlb.v1.ScmLbApi.LoadBalancerDelete:input_type -> scmlb.v1.LoadBalancerDeleteRequest
+	21, // 32: scmlb.v1.ScmLbApi.LoadBalancerDrain:input_type -> scmlb.v1.LoadBalancerDrainRequest
+	22, // 33: scmlb.v1.ScmLbApi.LoadBalancerDrainWait:input_type -> scmlb.v1.LoadBalancerDrainWaitRequest
+	25, // 34: scmlb.v1.ScmLbApi.LoadBalancerConntrackGet:input_type -> scmlb.v1.LoadBalancerConntrackGetRequest
+	28, // 35: scmlb.v1.ScmLbApi.LoadBalancerConntrackStat:input_type -> scmlb.v1.LoadBalancerConntrackStatRequest
+	31, // 36: scmlb.v1.ScmLbApi.LoadBalancerConntrackWatch:input_type -> scmlb.v1.LoadBalancerConntrackWatchRequest
+	33, // 37: scmlb.v1.ScmLbApi.LoadBalancerConntrackDelete:input_type -> scmlb.v1.LoadBalancerConntrackDeleteRequest
+	36, // 38: scmlb.v1.ScmLbApi.LoadBalancerConntrackSync:input_type -> scmlb.v1.ConntrackReplica
+	38, // 39: scmlb.v1.ScmLbApi.ServiceSet:input_type -> scmlb.v1.ServiceSetRequest
+	39, // 40: scmlb.v1.ScmLbApi.ServiceGet:input_type -> scmlb.v1.ServiceGetRequest
+	41, // 41: scmlb.v1.ScmLbApi.ServiceDelete:input_type -> scmlb.v1.ServiceDeleteRequest
+	43, // 42: scmlb.v1.ScmLbApi.LoadBalancerAffinitySet:input_type -> scmlb.v1.LoadBalancerAffinitySetRequest
+	44, // 43: scmlb.v1.ScmLbApi.LoadBalancerAffinityGet:input_type -> scmlb.v1.LoadBalancerAffinityGetRequest
+	46, // 44: scmlb.v1.ScmLbApi.LoadBalancerAffinityFlush:input_type -> scmlb.v1.LoadBalancerAffinityFlushRequest
+	49, // 45: scmlb.v1.ScmLbApi.Health:output_type -> google.protobuf.Empty
+	2,  // 46: scmlb.v1.ScmLbApi.Stat:output_type -> scmlb.v1.StatResponse
+	49, // 47: scmlb.v1.ScmLbApi.FireWallRuleSet:output_type -> google.protobuf.Empty
+	7,  // 48: scmlb.v1.ScmLbApi.FireWallRuleGet:output_type -> scmlb.v1.FireWallRuleGetResponse
+	49, // 49: scmlb.v1.ScmLbApi.FireWallRuleDelete:output_type -> google.protobuf.Empty
+	10, // 50: scmlb.v1.ScmLbApi.FireWallRuleApply:output_type -> scmlb.v1.FireWallRuleApplyResponse
+	49, // 51: scmlb.v1.ScmLbApi.DoSProtectionPolicySet:output_type -> google.protobuf.Empty
+	14, // 52: scmlb.v1.ScmLbApi.DoSProtectionPolicyGet:output_type -> scmlb.v1.DoSProtectionPolicyGetResponse
+	49, // 53: scmlb.v1.ScmLbApi.DoSProtectionPolicyDelete:output_type -> google.protobuf.Empty
+	49, // 54: scmlb.v1.ScmLbApi.LoadBalancerSet:output_type -> google.protobuf.Empty
+	19, // 55: scmlb.v1.ScmLbApi.LoadBalancerGet:output_type -> scmlb.v1.LoadBalancerGetResponse
+	49, // 56: scmlb.v1.ScmLbApi.LoadBalancerDelete:output_type -> google.protobuf.Empty
+	49, // 57: scmlb.v1.ScmLbApi.LoadBalancerDrain:output_type -> google.protobuf.Empty
+	23, // 58: scmlb.v1.ScmLbApi.LoadBalancerDrainWait:output_type -> scmlb.v1.LoadBalancerDrainStatus
+	26, // 59: scmlb.v1.ScmLbApi.LoadBalancerConntrackGet:output_type -> scmlb.v1.LoadBalancerConntrackGetResponse
+	29, // 60: scmlb.v1.ScmLbApi.LoadBalancerConntrackStat:output_type -> scmlb.v1.LoadBalancerConntrackStatResponse
+	32, // 61: scmlb.v1.ScmLbApi.LoadBalancerConntrackWatch:output_type -> scmlb.v1.ConntrackEvent
+	35, // 62: scmlb.v1.ScmLbApi.LoadBalancerConntrackDelete:output_type -> scmlb.v1.LoadBalancerConntrackDeleteResponse
+	37, // 63: scmlb.v1.ScmLbApi.LoadBalancerConntrackSync:output_type -> scmlb.v1.LoadBalancerConntrackSyncResponse
+	49, // 64: scmlb.v1.ScmLbApi.ServiceSet:output_type -> google.protobuf.Empty
+	40, // 65: scmlb.v1.ScmLbApi.ServiceGet:output_type -> scmlb.v1.ServiceGetResponse
+	49, // 66: scmlb.v1.ScmLbApi.ServiceDelete:output_type -> google.protobuf.Empty
+	49, // 67: scmlb.v1.ScmLbApi.LoadBalancerAffinitySet:output_type -> google.protobuf.Empty
+	45, // 68: scmlb.v1.ScmLbApi.LoadBalancerAffinityGet:output_type -> scmlb.v1.LoadBalancerAffinityGetResponse
+	49, // 69: scmlb.v1.ScmLbApi.LoadBalancerAffinityFlush:output_type -> google.protobuf.Empty
+	45, // [45:70] is the sub-list for method output_type
+	20, // [20:45] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
+}
+
+func init() { file_protobuf_scmlb_proto_init() }
+func file_protobuf_scmlb_proto_init() {
+	if File_protobuf_scmlb_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_protobuf_scmlb_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HealthRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Interface); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PacketCounter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FireWallRuleSetRqeust); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FireWallRuleGetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FireWallRuleGetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FireWallRuleDeleteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FireWallRuleApplyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FireWallRuleApplyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FireWallRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DoSProtectionPolicySetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DoSProtectionPolicyGetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DoSProtectionPolicyGetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DoSProtectionPolicyDeleteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DoSProtectionPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerSetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerGetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerGetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerDeleteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerDrainRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1678,8 +3946,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StatRequest); i {
+		file_protobuf_scmlb_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerDrainWaitRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1690,8 +3958,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StatResponse); i {
+		file_protobuf_scmlb_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerDrainStatus); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1702,8 +3970,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Interface); i {
+		file_protobuf_scmlb_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerBackend); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1714,8 +3982,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PacketCounter); i {
+		file_protobuf_scmlb_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerConntrackGetRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1726,8 +3994,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FireWallRuleSetRqeust); i {
+		file_protobuf_scmlb_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerConntrackGetResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1738,8 +4006,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FireWallRuleGetRequest); i {
+		file_protobuf_scmlb_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConntrackEntry); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1750,8 +4018,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FireWallRuleGetResponse); i {
+		file_protobuf_scmlb_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerConntrackStatRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1762,8 +4030,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FireWallRuleDeleteRequest); i {
+		file_protobuf_scmlb_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerConntrackStatResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1774,8 +4042,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FireWallRule); i {
+		file_protobuf_scmlb_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConntrackEvictionCounter); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1786,8 +4054,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DoSProtectionPolicySetRequest); i {
+		file_protobuf_scmlb_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerConntrackWatchRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1798,8 +4066,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DoSProtectionPolicyGetRequest); i {
+		file_protobuf_scmlb_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConntrackEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1810,8 +4078,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DoSProtectionPolicyGetResponse); i {
+		file_protobuf_scmlb_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerConntrackDeleteRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1822,8 +4090,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DoSProtectionPolicyDeleteRequest); i {
+		file_protobuf_scmlb_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConntrackTuple); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1834,8 +4102,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DoSProtectionPolicy); i {
+		file_protobuf_scmlb_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerConntrackDeleteResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1846,8 +4114,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LoadBalancerSetRequest); i {
+		file_protobuf_scmlb_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConntrackReplica); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1858,8 +4126,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LoadBalancerGetRequest); i {
+		file_protobuf_scmlb_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerConntrackSyncResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1870,8 +4138,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LoadBalancerGetResponse); i {
+		file_protobuf_scmlb_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServiceSetRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1882,8 +4150,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LoadBalancerDeleteRequest); i {
+		file_protobuf_scmlb_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServiceGetRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1894,8 +4162,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LoadBalancerDrainRequest); i {
+		file_protobuf_scmlb_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServiceGetResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1906,8 +4174,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LoadBalancerBackend); i {
+		file_protobuf_scmlb_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServiceDeleteRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1918,8 +4186,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LoadBalancerConntrackGetRequest); i {
+		file_protobuf_scmlb_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Service); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1930,8 +4198,8 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LoadBalancerConntrackGetResponse); i {
+		file_protobuf_scmlb_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerAffinitySetRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1942,8 +4210,44 @@ func file_protobuf_scmlb_proto_init() {
 				return nil
 			}
 		}
-		file_protobuf_scmlb_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ConntrackEntry); i {
+		file_protobuf_scmlb_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerAffinityGetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerAffinityGetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerAffinityFlushRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_scmlb_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AffinityEntry); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1961,7 +4265,7 @@ func file_protobuf_scmlb_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_protobuf_scmlb_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   24,
+			NumMessages:   48,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -20,19 +20,31 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	ScmLbApi_Health_FullMethodName                    = "/scmlb.v1.ScmLbApi/Health"
-	ScmLbApi_Stat_FullMethodName                      = "/scmlb.v1.ScmLbApi/Stat"
-	ScmLbApi_FireWallRuleSet_FullMethodName           = "/scmlb.v1.ScmLbApi/FireWallRuleSet"
-	ScmLbApi_FireWallRuleGet_FullMethodName           = "/scmlb.v1.ScmLbApi/FireWallRuleGet"
-	ScmLbApi_FireWallRuleDelete_FullMethodName        = "/scmlb.v1.ScmLbApi/FireWallRuleDelete"
-	ScmLbApi_DoSProtectionPolicySet_FullMethodName    = "/scmlb.v1.ScmLbApi/DoSProtectionPolicySet"
-	ScmLbApi_DoSProtectionPolicyGet_FullMethodName    = "/scmlb.v1.ScmLbApi/DoSProtectionPolicyGet"
-	ScmLbApi_DoSProtectionPolicyDelete_FullMethodName = "/scmlb.v1.ScmLbApi/DoSProtectionPolicyDelete"
-	ScmLbApi_LoadBalancerSet_FullMethodName           = "/scmlb.v1.ScmLbApi/LoadBalancerSet"
-	ScmLbApi_LoadBalancerGet_FullMethodName           = "/scmlb.v1.ScmLbApi/LoadBalancerGet"
-	ScmLbApi_LoadBalancerDelete_FullMethodName        = "/scmlb.v1.ScmLbApi/LoadBalancerDelete"
-	ScmLbApi_LoadBalancerDrain_FullMethodName         = "/scmlb.v1.ScmLbApi/LoadBalancerDrain"
-	ScmLbApi_LoadBalancerConntrackGet_FullMethodName  = "/scmlb.v1.ScmLbApi/LoadBalancerConntrackGet"
+	ScmLbApi_Health_FullMethodName                      = "/scmlb.v1.ScmLbApi/Health"
+	ScmLbApi_Stat_FullMethodName                        = "/scmlb.v1.ScmLbApi/Stat"
+	ScmLbApi_FireWallRuleSet_FullMethodName             = "/scmlb.v1.ScmLbApi/FireWallRuleSet"
+	ScmLbApi_FireWallRuleGet_FullMethodName             = "/scmlb.v1.ScmLbApi/FireWallRuleGet"
+	ScmLbApi_FireWallRuleDelete_FullMethodName          = "/scmlb.v1.ScmLbApi/FireWallRuleDelete"
+	ScmLbApi_FireWallRuleApply_FullMethodName           = "/scmlb.v1.ScmLbApi/FireWallRuleApply"
+	ScmLbApi_DoSProtectionPolicySet_FullMethodName      = "/scmlb.v1.ScmLbApi/DoSProtectionPolicySet"
+	ScmLbApi_DoSProtectionPolicyGet_FullMethodName      = "/scmlb.v1.ScmLbApi/DoSProtectionPolicyGet"
+	ScmLbApi_DoSProtectionPolicyDelete_FullMethodName   = "/scmlb.v1.ScmLbApi/DoSProtectionPolicyDelete"
+	ScmLbApi_LoadBalancerSet_FullMethodName             = "/scmlb.v1.ScmLbApi/LoadBalancerSet"
+	ScmLbApi_LoadBalancerGet_FullMethodName             = "/scmlb.v1.ScmLbApi/LoadBalancerGet"
+	ScmLbApi_LoadBalancerDelete_FullMethodName          = "/scmlb.v1.ScmLbApi/LoadBalancerDelete"
+	ScmLbApi_LoadBalancerDrain_FullMethodName           = "/scmlb.v1.ScmLbApi/LoadBalancerDrain"
+	ScmLbApi_LoadBalancerDrainWait_FullMethodName       = "/scmlb.v1.ScmLbApi/LoadBalancerDrainWait"
+	ScmLbApi_LoadBalancerConntrackGet_FullMethodName    = "/scmlb.v1.ScmLbApi/LoadBalancerConntrackGet"
+	ScmLbApi_LoadBalancerConntrackStat_FullMethodName   = "/scmlb.v1.ScmLbApi/LoadBalancerConntrackStat"
+	ScmLbApi_LoadBalancerConntrackWatch_FullMethodName  = "/scmlb.v1.ScmLbApi/LoadBalancerConntrackWatch"
+	ScmLbApi_LoadBalancerConntrackDelete_FullMethodName = "/scmlb.v1.ScmLbApi/LoadBalancerConntrackDelete"
+	ScmLbApi_LoadBalancerConntrackSync_FullMethodName   = "/scmlb.v1.ScmLbApi/LoadBalancerConntrackSync"
+	ScmLbApi_ServiceSet_FullMethodName                  = "/scmlb.v1.ScmLbApi/ServiceSet"
+	ScmLbApi_ServiceGet_FullMethodName                  = "/scmlb.v1.ScmLbApi/ServiceGet"
+	ScmLbApi_ServiceDelete_FullMethodName               = "/scmlb.v1.ScmLbApi/ServiceDelete"
+	ScmLbApi_LoadBalancerAffinitySet_FullMethodName     = "/scmlb.v1.ScmLbApi/LoadBalancerAffinitySet"
+	ScmLbApi_LoadBalancerAffinityGet_FullMethodName     = "/scmlb.v1.ScmLbApi/LoadBalancerAffinityGet"
+	ScmLbApi_LoadBalancerAffinityFlush_FullMethodName   = "/scmlb.v1.ScmLbApi/LoadBalancerAffinityFlush"
 )
 
 // ScmLbApiClient is the client API for ScmLbApi service.
@@ -44,6 +56,7 @@ type ScmLbApiClient interface {
 	FireWallRuleSet(ctx context.Context, in *FireWallRuleSetRqeust, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	FireWallRuleGet(ctx context.Context, in *FireWallRuleGetRequest, opts ...grpc.CallOption) (*FireWallRuleGetResponse, error)
 	FireWallRuleDelete(ctx context.Context, in *FireWallRuleDeleteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	FireWallRuleApply(ctx context.Context, in *FireWallRuleApplyRequest, opts ...grpc.CallOption) (*FireWallRuleApplyResponse, error)
 	DoSProtectionPolicySet(ctx context.Context, in *DoSProtectionPolicySetRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	DoSProtectionPolicyGet(ctx context.Context, in *DoSProtectionPolicyGetRequest, opts ...grpc.CallOption) (*DoSProtectionPolicyGetResponse, error)
 	DoSProtectionPolicyDelete(ctx context.Context, in *DoSProtectionPolicyDeleteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
@@ -51,7 +64,18 @@ type ScmLbApiClient interface {
 	LoadBalancerGet(ctx context.Context, in *LoadBalancerGetRequest, opts ...grpc.CallOption) (*LoadBalancerGetResponse, error)
 	LoadBalancerDelete(ctx context.Context, in *LoadBalancerDeleteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	LoadBalancerDrain(ctx context.Context, in *LoadBalancerDrainRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	LoadBalancerDrainWait(ctx context.Context, in *LoadBalancerDrainWaitRequest, opts ...grpc.CallOption) (ScmLbApi_LoadBalancerDrainWaitClient, error)
 	LoadBalancerConntrackGet(ctx context.Context, in *LoadBalancerConntrackGetRequest, opts ...grpc.CallOption) (*LoadBalancerConntrackGetResponse, error)
+	LoadBalancerConntrackStat(ctx context.Context, in *LoadBalancerConntrackStatRequest, opts ...grpc.CallOption) (*LoadBalancerConntrackStatResponse, error)
+	LoadBalancerConntrackWatch(ctx context.Context, in *LoadBalancerConntrackWatchRequest, opts ...grpc.CallOption) (ScmLbApi_LoadBalancerConntrackWatchClient, error)
+	LoadBalancerConntrackDelete(ctx context.Context, in *LoadBalancerConntrackDeleteRequest, opts ...grpc.CallOption) (*LoadBalancerConntrackDeleteResponse, error)
+	LoadBalancerConntrackSync(ctx context.Context, opts ...grpc.CallOption) (ScmLbApi_LoadBalancerConntrackSyncClient, error)
+	ServiceSet(ctx context.Context, in *ServiceSetRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ServiceGet(ctx context.Context, in *ServiceGetRequest, opts ...grpc.CallOption) (*ServiceGetResponse, error)
+	ServiceDelete(ctx context.Context, in *ServiceDeleteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	LoadBalancerAffinitySet(ctx context.Context, in *LoadBalancerAffinitySetRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	LoadBalancerAffinityGet(ctx context.Context, in *LoadBalancerAffinityGetRequest, opts ...grpc.CallOption) (*LoadBalancerAffinityGetResponse, error)
+	LoadBalancerAffinityFlush(ctx context.Context, in *LoadBalancerAffinityFlushRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type scmLbApiClient struct {
@@ -107,6 +131,15 @@ func (c *scmLbApiClient) FireWallRuleDelete(ctx context.Context, in *FireWallRul
 	return out, nil
 }
 
+func (c *scmLbApiClient) FireWallRuleApply(ctx context.Context, in *FireWallRuleApplyRequest, opts ...grpc.CallOption) (*FireWallRuleApplyResponse, error) {
+	out := new(FireWallRuleApplyResponse)
+	err := c.cc.Invoke(ctx, ScmLbApi_FireWallRuleApply_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *scmLbApiClient) DoSProtectionPolicySet(ctx context.Context, in *DoSProtectionPolicySetRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, ScmLbApi_DoSProtectionPolicySet_FullMethodName, in, out, opts...)
@@ -170,6 +203,38 @@ func (c *scmLbApiClient) LoadBalancerDrain(ctx context.Context, in *LoadBalancer
 	return out, nil
 }
 
+func (c *scmLbApiClient) LoadBalancerDrainWait(ctx context.Context, in *LoadBalancerDrainWaitRequest, opts ...grpc.CallOption) (ScmLbApi_LoadBalancerDrainWaitClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ScmLbApi_ServiceDesc.Streams[0], ScmLbApi_LoadBalancerDrainWait_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &scmLbApiLoadBalancerDrainWaitClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ScmLbApi_LoadBalancerDrainWaitClient interface {
+	Recv() (*LoadBalancerDrainStatus, error)
+	grpc.ClientStream
+}
+
+type scmLbApiLoadBalancerDrainWaitClient struct {
+	grpc.ClientStream
+}
+
+func (x *scmLbApiLoadBalancerDrainWaitClient) Recv() (*LoadBalancerDrainStatus, error) {
+	m := new(LoadBalancerDrainStatus)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *scmLbApiClient) LoadBalancerConntrackGet(ctx context.Context, in *LoadBalancerConntrackGetRequest, opts ...grpc.CallOption) (*LoadBalancerConntrackGetResponse, error) {
 	out := new(LoadBalancerConntrackGetResponse)
 	err := c.cc.Invoke(ctx, ScmLbApi_LoadBalancerConntrackGet_FullMethodName, in, out, opts...)
@@ -179,6 +244,144 @@ func (c *scmLbApiClient) LoadBalancerConntrackGet(ctx context.Context, in *LoadB
 	return out, nil
 }
 
+func (c *scmLbApiClient) LoadBalancerConntrackStat(ctx context.Context, in *LoadBalancerConntrackStatRequest, opts ...grpc.CallOption) (*LoadBalancerConntrackStatResponse, error) {
+	out := new(LoadBalancerConntrackStatResponse)
+	err := c.cc.Invoke(ctx, ScmLbApi_LoadBalancerConntrackStat_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scmLbApiClient) LoadBalancerConntrackWatch(ctx context.Context, in *LoadBalancerConntrackWatchRequest, opts ...grpc.CallOption) (ScmLbApi_LoadBalancerConntrackWatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ScmLbApi_ServiceDesc.Streams[1], ScmLbApi_LoadBalancerConntrackWatch_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &scmLbApiLoadBalancerConntrackWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ScmLbApi_LoadBalancerConntrackWatchClient interface {
+	Recv() (*ConntrackEvent, error)
+	grpc.ClientStream
+}
+
+type scmLbApiLoadBalancerConntrackWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *scmLbApiLoadBalancerConntrackWatchClient) Recv() (*ConntrackEvent, error) {
+	m := new(ConntrackEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *scmLbApiClient) LoadBalancerConntrackDelete(ctx context.Context, in *LoadBalancerConntrackDeleteRequest, opts ...grpc.CallOption) (*LoadBalancerConntrackDeleteResponse, error) {
+	out := new(LoadBalancerConntrackDeleteResponse)
+	err := c.cc.Invoke(ctx, ScmLbApi_LoadBalancerConntrackDelete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scmLbApiClient) LoadBalancerConntrackSync(ctx context.Context, opts ...grpc.CallOption) (ScmLbApi_LoadBalancerConntrackSyncClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ScmLbApi_ServiceDesc.Streams[2], ScmLbApi_LoadBalancerConntrackSync_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &scmLbApiLoadBalancerConntrackSyncClient{stream}
+	return x, nil
+}
+
+type ScmLbApi_LoadBalancerConntrackSyncClient interface {
+	Send(*ConntrackReplica) error
+	CloseAndRecv() (*LoadBalancerConntrackSyncResponse, error)
+	grpc.ClientStream
+}
+
+type scmLbApiLoadBalancerConntrackSyncClient struct {
+	grpc.ClientStream
+}
+
+func (x *scmLbApiLoadBalancerConntrackSyncClient) Send(m *ConntrackReplica) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *scmLbApiLoadBalancerConntrackSyncClient) CloseAndRecv() (*LoadBalancerConntrackSyncResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(LoadBalancerConntrackSyncResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *scmLbApiClient) ServiceSet(ctx context.Context, in *ServiceSetRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ScmLbApi_ServiceSet_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scmLbApiClient) ServiceGet(ctx context.Context, in *ServiceGetRequest, opts ...grpc.CallOption) (*ServiceGetResponse, error) {
+	out := new(ServiceGetResponse)
+	err := c.cc.Invoke(ctx, ScmLbApi_ServiceGet_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scmLbApiClient) ServiceDelete(ctx context.Context, in *ServiceDeleteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ScmLbApi_ServiceDelete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scmLbApiClient) LoadBalancerAffinitySet(ctx context.Context, in *LoadBalancerAffinitySetRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ScmLbApi_LoadBalancerAffinitySet_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scmLbApiClient) LoadBalancerAffinityGet(ctx context.Context, in *LoadBalancerAffinityGetRequest, opts ...grpc.CallOption) (*LoadBalancerAffinityGetResponse, error) {
+	out := new(LoadBalancerAffinityGetResponse)
+	err := c.cc.Invoke(ctx, ScmLbApi_LoadBalancerAffinityGet_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scmLbApiClient) LoadBalancerAffinityFlush(ctx context.Context, in *LoadBalancerAffinityFlushRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ScmLbApi_LoadBalancerAffinityFlush_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ScmLbApiServer is the server API for ScmLbApi service.
 // All implementations must embed UnimplementedScmLbApiServer
 // for forward compatibility
@@ -188,6 +391,7 @@ type ScmLbApiServer interface {
 	FireWallRuleSet(context.Context, *FireWallRuleSetRqeust) (*emptypb.Empty, error)
 	FireWallRuleGet(context.Context, *FireWallRuleGetRequest) (*FireWallRuleGetResponse, error)
 	FireWallRuleDelete(context.Context, *FireWallRuleDeleteRequest) (*emptypb.Empty, error)
+	FireWallRuleApply(context.Context, *FireWallRuleApplyRequest) (*FireWallRuleApplyResponse, error)
 	DoSProtectionPolicySet(context.Context, *DoSProtectionPolicySetRequest) (*emptypb.Empty, error)
 	DoSProtectionPolicyGet(context.Context, *DoSProtectionPolicyGetRequest) (*DoSProtectionPolicyGetResponse, error)
 	DoSProtectionPolicyDelete(context.Context, *DoSProtectionPolicyDeleteRequest) (*emptypb.Empty, error)
@@ -195,7 +399,18 @@ type ScmLbApiServer interface {
 	LoadBalancerGet(context.Context, *LoadBalancerGetRequest) (*LoadBalancerGetResponse, error)
 	LoadBalancerDelete(context.Context, *LoadBalancerDeleteRequest) (*emptypb.Empty, error)
 	LoadBalancerDrain(context.Context, *LoadBalancerDrainRequest) (*emptypb.Empty, error)
+	LoadBalancerDrainWait(*LoadBalancerDrainWaitRequest, ScmLbApi_LoadBalancerDrainWaitServer) error
 	LoadBalancerConntrackGet(context.Context, *LoadBalancerConntrackGetRequest) (*LoadBalancerConntrackGetResponse, error)
+	LoadBalancerConntrackStat(context.Context, *LoadBalancerConntrackStatRequest) (*LoadBalancerConntrackStatResponse, error)
+	LoadBalancerConntrackWatch(*LoadBalancerConntrackWatchRequest, ScmLbApi_LoadBalancerConntrackWatchServer) error
+	LoadBalancerConntrackDelete(context.Context, *LoadBalancerConntrackDeleteRequest) (*LoadBalancerConntrackDeleteResponse, error)
+	LoadBalancerConntrackSync(ScmLbApi_LoadBalancerConntrackSyncServer) error
+	ServiceSet(context.Context, *ServiceSetRequest) (*emptypb.Empty, error)
+	ServiceGet(context.Context, *ServiceGetRequest) (*ServiceGetResponse, error)
+	ServiceDelete(context.Context, *ServiceDeleteRequest) (*emptypb.Empty, error)
+	LoadBalancerAffinitySet(context.Context, *LoadBalancerAffinitySetRequest) (*emptypb.Empty, error)
+	LoadBalancerAffinityGet(context.Context, *LoadBalancerAffinityGetRequest) (*LoadBalancerAffinityGetResponse, error)
+	LoadBalancerAffinityFlush(context.Context, *LoadBalancerAffinityFlushRequest) (*emptypb.Empty, error)
 	mustEmbedUnimplementedScmLbApiServer()
 }
 
@@ -218,6 +433,9 @@ func (UnimplementedScmLbApiServer) FireWallRuleGet(context.Context, *FireWallRul
 func (UnimplementedScmLbApiServer) FireWallRuleDelete(context.Context, *FireWallRuleDeleteRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FireWallRuleDelete not implemented")
 }
+func (UnimplementedScmLbApiServer) FireWallRuleApply(context.Context, *FireWallRuleApplyRequest) (*FireWallRuleApplyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FireWallRuleApply not implemented")
+}
 func (UnimplementedScmLbApiServer) DoSProtectionPolicySet(context.Context, *DoSProtectionPolicySetRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DoSProtectionPolicySet not implemented")
 }
@@ -239,9 +457,42 @@ func (UnimplementedScmLbApiServer) LoadBalancerDelete(context.Context, *LoadBala
 func (UnimplementedScmLbApiServer) LoadBalancerDrain(context.Context, *LoadBalancerDrainRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LoadBalancerDrain not implemented")
 }
+func (UnimplementedScmLbApiServer) LoadBalancerDrainWait(*LoadBalancerDrainWaitRequest, ScmLbApi_LoadBalancerDrainWaitServer) error {
+	return status.Errorf(codes.Unimplemented, "method LoadBalancerDrainWait not implemented")
+}
 func (UnimplementedScmLbApiServer) LoadBalancerConntrackGet(context.Context, *LoadBalancerConntrackGetRequest) (*LoadBalancerConntrackGetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LoadBalancerConntrackGet not implemented")
 }
+func (UnimplementedScmLbApiServer) LoadBalancerConntrackStat(context.Context, *LoadBalancerConntrackStatRequest) (*LoadBalancerConntrackStatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoadBalancerConntrackStat not implemented")
+}
+func (UnimplementedScmLbApiServer) LoadBalancerConntrackWatch(*LoadBalancerConntrackWatchRequest, ScmLbApi_LoadBalancerConntrackWatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method LoadBalancerConntrackWatch not implemented")
+}
+func (UnimplementedScmLbApiServer) LoadBalancerConntrackDelete(context.Context, *LoadBalancerConntrackDeleteRequest) (*LoadBalancerConntrackDeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoadBalancerConntrackDelete not implemented")
+}
+func (UnimplementedScmLbApiServer) LoadBalancerConntrackSync(ScmLbApi_LoadBalancerConntrackSyncServer) error {
+	return status.Errorf(codes.Unimplemented, "method LoadBalancerConntrackSync not implemented")
+}
+func (UnimplementedScmLbApiServer) ServiceSet(context.Context, *ServiceSetRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ServiceSet not implemented")
+}
+func (UnimplementedScmLbApiServer) ServiceGet(context.Context, *ServiceGetRequest) (*ServiceGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ServiceGet not implemented")
+}
+func (UnimplementedScmLbApiServer) ServiceDelete(context.Context, *ServiceDeleteRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ServiceDelete not implemented")
+}
+func (UnimplementedScmLbApiServer) LoadBalancerAffinitySet(context.Context, *LoadBalancerAffinitySetRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoadBalancerAffinitySet not implemented")
+}
+func (UnimplementedScmLbApiServer) LoadBalancerAffinityGet(context.Context, *LoadBalancerAffinityGetRequest) (*LoadBalancerAffinityGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoadBalancerAffinityGet not implemented")
+}
+func (UnimplementedScmLbApiServer) LoadBalancerAffinityFlush(context.Context, *LoadBalancerAffinityFlushRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoadBalancerAffinityFlush not implemented")
+}
 func (UnimplementedScmLbApiServer) mustEmbedUnimplementedScmLbApiServer() {}
 
 // UnsafeScmLbApiServer may be embedded to opt out of forward compatibility for this service.
@@ -345,6 +596,24 @@ func _ScmLbApi_FireWallRuleDelete_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ScmLbApi_FireWallRuleApply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FireWallRuleApplyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScmLbApiServer).FireWallRuleApply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScmLbApi_FireWallRuleApply_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScmLbApiServer).FireWallRuleApply(ctx, req.(*FireWallRuleApplyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ScmLbApi_DoSProtectionPolicySet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DoSProtectionPolicySetRequest)
 	if err := dec(in); err != nil {
@@ -471,6 +740,27 @@ func _ScmLbApi_LoadBalancerDrain_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ScmLbApi_LoadBalancerDrainWait_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LoadBalancerDrainWaitRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ScmLbApiServer).LoadBalancerDrainWait(m, &scmLbApiLoadBalancerDrainWaitServer{stream})
+}
+
+type ScmLbApi_LoadBalancerDrainWaitServer interface {
+	Send(*LoadBalancerDrainStatus) error
+	grpc.ServerStream
+}
+
+type scmLbApiLoadBalancerDrainWaitServer struct {
+	grpc.ServerStream
+}
+
+func (x *scmLbApiLoadBalancerDrainWaitServer) Send(m *LoadBalancerDrainStatus) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _ScmLbApi_LoadBalancerConntrackGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(LoadBalancerConntrackGetRequest)
 	if err := dec(in); err != nil {
@@ -489,6 +779,197 @@ func _ScmLbApi_LoadBalancerConntrackGet_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ScmLbApi_LoadBalancerConntrackStat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadBalancerConntrackStatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScmLbApiServer).LoadBalancerConntrackStat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScmLbApi_LoadBalancerConntrackStat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScmLbApiServer).LoadBalancerConntrackStat(ctx, req.(*LoadBalancerConntrackStatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScmLbApi_LoadBalancerConntrackWatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LoadBalancerConntrackWatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ScmLbApiServer).LoadBalancerConntrackWatch(m, &scmLbApiLoadBalancerConntrackWatchServer{stream})
+}
+
+type ScmLbApi_LoadBalancerConntrackWatchServer interface {
+	Send(*ConntrackEvent) error
+	grpc.ServerStream
+}
+
+type scmLbApiLoadBalancerConntrackWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *scmLbApiLoadBalancerConntrackWatchServer) Send(m *ConntrackEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ScmLbApi_LoadBalancerConntrackDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadBalancerConntrackDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScmLbApiServer).LoadBalancerConntrackDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScmLbApi_LoadBalancerConntrackDelete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScmLbApiServer).LoadBalancerConntrackDelete(ctx, req.(*LoadBalancerConntrackDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScmLbApi_LoadBalancerConntrackSync_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ScmLbApiServer).LoadBalancerConntrackSync(&scmLbApiLoadBalancerConntrackSyncServer{stream})
+}
+
+type ScmLbApi_LoadBalancerConntrackSyncServer interface {
+	SendAndClose(*LoadBalancerConntrackSyncResponse) error
+	Recv() (*ConntrackReplica, error)
+	grpc.ServerStream
+}
+
+type scmLbApiLoadBalancerConntrackSyncServer struct {
+	grpc.ServerStream
+}
+
+func (x *scmLbApiLoadBalancerConntrackSyncServer) SendAndClose(m *LoadBalancerConntrackSyncResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *scmLbApiLoadBalancerConntrackSyncServer) Recv() (*ConntrackReplica, error) {
+	m := new(ConntrackReplica)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _ScmLbApi_ServiceSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServiceSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScmLbApiServer).ServiceSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScmLbApi_ServiceSet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScmLbApiServer).ServiceSet(ctx, req.(*ServiceSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScmLbApi_ServiceGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServiceGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScmLbApiServer).ServiceGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScmLbApi_ServiceGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScmLbApiServer).ServiceGet(ctx, req.(*ServiceGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScmLbApi_ServiceDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServiceDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScmLbApiServer).ServiceDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScmLbApi_ServiceDelete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScmLbApiServer).ServiceDelete(ctx, req.(*ServiceDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScmLbApi_LoadBalancerAffinitySet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadBalancerAffinitySetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScmLbApiServer).LoadBalancerAffinitySet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScmLbApi_LoadBalancerAffinitySet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScmLbApiServer).LoadBalancerAffinitySet(ctx, req.(*LoadBalancerAffinitySetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScmLbApi_LoadBalancerAffinityGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadBalancerAffinityGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScmLbApiServer).LoadBalancerAffinityGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScmLbApi_LoadBalancerAffinityGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScmLbApiServer).LoadBalancerAffinityGet(ctx, req.(*LoadBalancerAffinityGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScmLbApi_LoadBalancerAffinityFlush_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadBalancerAffinityFlushRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScmLbApiServer).LoadBalancerAffinityFlush(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScmLbApi_LoadBalancerAffinityFlush_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScmLbApiServer).LoadBalancerAffinityFlush(ctx, req.(*LoadBalancerAffinityFlushRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ScmLbApi_ServiceDesc is the grpc.ServiceDesc for ScmLbApi service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -516,6 +997,10 @@ var ScmLbApi_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FireWallRuleDelete",
 			Handler:    _ScmLbApi_FireWallRuleDelete_Handler,
 		},
+		{
+			MethodName: "FireWallRuleApply",
+			Handler:    _ScmLbApi_FireWallRuleApply_Handler,
+		},
 		{
 			MethodName: "DoSProtectionPolicySet",
 			Handler:    _ScmLbApi_DoSProtectionPolicySet_Handler,
@@ -548,7 +1033,55 @@ var ScmLbApi_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LoadBalancerConntrackGet",
 			Handler:    _ScmLbApi_LoadBalancerConntrackGet_Handler,
 		},
+		{
+			MethodName: "LoadBalancerConntrackStat",
+			Handler:    _ScmLbApi_LoadBalancerConntrackStat_Handler,
+		},
+		{
+			MethodName: "LoadBalancerConntrackDelete",
+			Handler:    _ScmLbApi_LoadBalancerConntrackDelete_Handler,
+		},
+		{
+			MethodName: "ServiceSet",
+			Handler:    _ScmLbApi_ServiceSet_Handler,
+		},
+		{
+			MethodName: "ServiceGet",
+			Handler:    _ScmLbApi_ServiceGet_Handler,
+		},
+		{
+			MethodName: "ServiceDelete",
+			Handler:    _ScmLbApi_ServiceDelete_Handler,
+		},
+		{
+			MethodName: "LoadBalancerAffinitySet",
+			Handler:    _ScmLbApi_LoadBalancerAffinitySet_Handler,
+		},
+		{
+			MethodName: "LoadBalancerAffinityGet",
+			Handler:    _ScmLbApi_LoadBalancerAffinityGet_Handler,
+		},
+		{
+			MethodName: "LoadBalancerAffinityFlush",
+			Handler:    _ScmLbApi_LoadBalancerAffinityFlush_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "LoadBalancerDrainWait",
+			Handler:       _ScmLbApi_LoadBalancerDrainWait_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "LoadBalancerConntrackWatch",
+			Handler:       _ScmLbApi_LoadBalancerConntrackWatch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "LoadBalancerConntrackSync",
+			Handler:       _ScmLbApi_LoadBalancerConntrackSync_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "protobuf/scmlb.proto",
 }
@@ -0,0 +1,44 @@
+#!/bin/bash
+# tree トポロジーのバックエンド(host5, host6, host7) で DSR のためにカプセル化されたパケットを受け取れるように設定します。
+# 使い方: topology/dsr.sh [ipip|gue] (デフォルトは ipip)
+# VIP 環境変数で VIP を変更できます。
+set -x
+
+MODE=${1:-ipip}
+VIP=${VIP:-203.0.113.11}
+GUE_PORT=6080 # scmlb の pkg/constants の GUE_PORT に対応しています
+
+if [ "$MODE" != "ipip" ] && [ "$MODE" != "gue" ]; then
+	echo "unknown mode: $MODE (expected value is ipip/gue)"
+	exit 1
+fi
+
+# tunl0 と fou のデバイスを使うためにカーネルモジュールを読み込みます
+modprobe ipip
+modprobe fou
+
+for ns in host5 host6 host7; do
+	# バックエンドは VIP 宛のパケットを自身宛として受け取り、VIP を送信元としてクライアントに直接応答します
+	ip netns exec $ns ip addr add $VIP/32 dev lo
+	# VIP の ARP に応答しないようにします
+	ip netns exec $ns sysctl -w net.ipv4.conf.all.arp_ignore=1
+	ip netns exec $ns sysctl -w net.ipv4.conf.all.arp_announce=2
+
+	# カプセル化を解除したパケットは tunl0 から受信されるので、クライアントへの経路と一致しなくても破棄しないようにします
+	ip netns exec $ns ip link set up dev tunl0
+	ip netns exec $ns sysctl -w net.ipv4.conf.all.rp_filter=0
+	ip netns exec $ns sysctl -w net.ipv4.conf.tunl0.rp_filter=0
+
+	if [ "$MODE" = "gue" ]; then
+		# GUE のポートで受信した UDP パケットのカプセル化を解除します
+		ip netns exec $ns ip fou add port $GUE_PORT gue
+	fi
+done
+
+# バックエンドからの応答は VIP を送信元としてロードバランサー(host2) を経由するので、
+# host2 で自身のアドレスを送信元とするパケットを受け取って転送できるようにします
+ip netns exec host2 sysctl -w net.ipv4.conf.all.rp_filter=0
+for dev in h2-h5 h2-h6 h2-h7; do
+	ip netns exec host2 sysctl -w net.ipv4.conf.$dev.rp_filter=0
+	ip netns exec host2 sysctl -w net.ipv4.conf.$dev.accept_local=1
+done